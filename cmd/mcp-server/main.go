@@ -1,19 +1,119 @@
 package main
 
 import (
-	"log"
+	"context"
+	"flag"
+	"log/slog"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
 
+	"microsoft.com/aml-mcp/internal/logging"
 	"microsoft.com/aml-mcp/internal/server"
 )
 
 func main() {
 	config := server.Config{
-		Name:    "Azure Machine Learning SDK",
-		Version: "1.0.0",
+		Name:            "Azure Machine Learning SDK",
+		Version:         "1.0.0",
+		Transport:       server.TransportStdio,
+		Address:         server.DefaultAddress,
+		ShutdownTimeout: server.DefaultShutdownTimeout,
 	}
 
+	configPath := flag.String("config", os.Getenv("AML_MCP_CONFIG"), "Path to a JSON config file (or set AML_MCP_CONFIG)")
+	transport := flag.String("transport", config.Transport, "Transport to serve on: stdio, sse or http")
+	address := flag.String("addr", config.Address, "Listen address for the sse and http transports")
+	basePath := flag.String("base-path", "", "URL path prefix for the sse and http transports (e.g. /aml)")
+	shutdownTimeout := flag.Duration("shutdown-timeout", config.ShutdownTimeout, "Graceful shutdown timeout for the sse and http transports")
+	cloud := flag.String("cloud", "", "Azure cloud: AzurePublic (default), AzureChina or AzureGovernment")
+	authModes := flag.String("auth", "", "Comma-separated credential types to try in order: cli, default, service-principal, workload-identity, managed-identity, device-code or browser")
+	readOnly := flag.Bool("read-only", false, "Register only tools that do not change Azure resources")
+	allowTools := flag.String("allow-tools", "", "Comma-separated tools or categories to register (default all)")
+	denyTools := flag.String("deny-tools", "", "Comma-separated tools or categories to leave out")
+	skipConfirmation := flag.Bool("skip-confirmation", false, "Let mutating tools act without asking for confirmation")
+	auditLog := flag.String("audit-log", "", "Record mutating tool calls to a JSONL file, \"syslog\" or \"syslog://host:port\"")
+	logLevel := flag.String("log-level", "", "Minimum level to log: debug, info, warn or error (default info)")
+	logFormat := flag.String("log-format", "", "Log format: text or json (default text)")
+	logFile := flag.String("log-file", "", "Append logs to this file instead of stderr")
+	otlpEndpoint := flag.String("otlp-endpoint", "", "Export traces of tool calls and ARM requests to this OTLP/HTTP endpoint (e.g. http://localhost:4318)")
+	flag.Parse()
+
+	// Layer the config file, environment and explicit flags over the defaults
+	if *configPath != "" {
+		if err := server.LoadConfigFile(*configPath, &config); err != nil {
+			fatal(err)
+		}
+	}
+	server.ApplyEnvironment(&config)
+	flag.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "transport":
+			config.Transport = *transport
+		case "addr":
+			config.Address = *address
+		case "base-path":
+			config.BasePath = *basePath
+		case "shutdown-timeout":
+			config.ShutdownTimeout = *shutdownTimeout
+		case "cloud":
+			config.Cloud.Name = *cloud
+		case "auth":
+			config.Auth.Modes = splitList(*authModes)
+		case "read-only":
+			config.ReadOnly = *readOnly
+		case "allow-tools":
+			config.AllowTools = splitList(*allowTools)
+		case "deny-tools":
+			config.DenyTools = splitList(*denyTools)
+		case "skip-confirmation":
+			config.SkipConfirmation = *skipConfirmation
+		case "audit-log":
+			config.AuditLog = *auditLog
+		case "log-level":
+			config.Logging.Level = *logLevel
+		case "log-format":
+			config.Logging.Format = *logFormat
+		case "log-file":
+			config.Logging.File = *logFile
+		case "otlp-endpoint":
+			config.Tracing.Endpoint = *otlpEndpoint
+		}
+	})
+
+	// Logs never go to stdout, which carries the stdio transport
+	logger, closer, err := logging.New(config.Logging)
+	if err != nil {
+		fatal(err)
+	}
+	defer closer.Close()
+	slog.SetDefault(logger)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
 	s := server.New(config)
-	if err := s.Serve(); err != nil {
-		log.Fatalf("Failed to start server: %v", err)
+	if err := s.Serve(ctx); err != nil {
+		slog.Error("Failed to start server", "error", err)
+		closer.Close()
+		os.Exit(1)
+	}
+}
+
+// fatal logs err and exits
+func fatal(err error) {
+	slog.Error(err.Error())
+	os.Exit(1)
+}
+
+// splitList splits a comma-separated flag value, dropping empty entries
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
 	}
+	return items
 }
@@ -0,0 +1,161 @@
+// Package audit records mutating tool calls to an append-only log
+package audit
+
+import (
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Outcomes of a tool call other than the status the tool itself reports
+const (
+	OutcomeSucceeded = "Succeeded"
+	OutcomeFailed    = "Failed"
+)
+
+// recentEvents is how many events are kept in memory for queries
+const recentEvents = 1000
+
+// Caller identifies who made a tool call
+type Caller struct {
+	ObjectID string `json:"objectId,omitempty"`
+	TenantID string `json:"tenantId,omitempty"`
+	Name     string `json:"name,omitempty"`
+	AppID    string `json:"appId,omitempty"`
+}
+
+// Event is a single audited tool call
+type Event struct {
+	Time      time.Time      `json:"time"`
+	Tool      string         `json:"tool"`
+	Arguments map[string]any `json:"arguments,omitempty"`
+	Caller    Caller         `json:"caller"`
+	SessionID string         `json:"sessionId,omitempty"`
+	// Outcome is Failed or the status reported by the tool
+	Outcome       string   `json:"outcome"`
+	Error         string   `json:"error,omitempty"`
+	CorrelationID string   `json:"correlationId,omitempty"`
+	RequestIDs    []string `json:"requestIds,omitempty"`
+	DurationMs    int64    `json:"durationMs"`
+}
+
+// Sink persists events
+type Sink interface {
+	Write(event Event) error
+	Close() error
+}
+
+// Reader is implemented by sinks whose events can be read back
+type Reader interface {
+	// ReadAll returns every stored event, oldest first
+	ReadAll() ([]Event, error)
+}
+
+// Filter selects events in Query. Zero fields match everything.
+type Filter struct {
+	Tool    string
+	Outcome string
+	Since   time.Time
+	// Limit caps the number of events returned
+	Limit int
+}
+
+// Log records events to a sink and keeps the most recent ones in memory
+type Log struct {
+	sink Sink
+
+	mu     sync.Mutex
+	recent []Event
+}
+
+// NewLog creates a Log writing to sink, which may be nil
+func NewLog(sink Sink) *Log {
+	return &Log{sink: sink}
+}
+
+// Open creates a Log for "", "syslog", "syslog://host:port" or the path of a JSONL file
+func Open(destination string) (*Log, error) {
+	var (
+		sink Sink
+		err  error
+	)
+	switch {
+	case destination == "":
+	case destination == "syslog":
+		sink, err = NewSyslogSink("")
+	case strings.HasPrefix(destination, "syslog://"):
+		sink, err = NewSyslogSink(strings.TrimPrefix(destination, "syslog://"))
+	default:
+		sink, err = NewFileSink(destination)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %v", err)
+	}
+	return NewLog(sink), nil
+}
+
+// Record stores an event, logging a sink failure
+func (l *Log) Record(event Event) {
+	l.mu.Lock()
+	l.recent = append(l.recent, event)
+	if len(l.recent) > recentEvents {
+		l.recent = l.recent[len(l.recent)-recentEvents:]
+	}
+	l.mu.Unlock()
+
+	if l.sink != nil {
+		if err := l.sink.Write(event); err != nil {
+			slog.Error("Failed to write audit event", "tool", event.Tool, "error", err)
+		}
+	}
+}
+
+// Query returns the events matching filter, newest first
+func (l *Log) Query(filter Filter) ([]Event, error) {
+	var events []Event
+	if reader, ok := l.sink.(Reader); ok {
+		var err error
+		if events, err = reader.ReadAll(); err != nil {
+			return nil, err
+		}
+	} else {
+		l.mu.Lock()
+		events = append([]Event(nil), l.recent...)
+		l.mu.Unlock()
+	}
+
+	// Walk newest first so events recorded at the same instant keep their order
+	matched := []Event{}
+	for i := len(events) - 1; i >= 0; i-- {
+		event := events[i]
+		if filter.Tool != "" && event.Tool != filter.Tool {
+			continue
+		}
+		if filter.Outcome != "" && !strings.EqualFold(event.Outcome, filter.Outcome) {
+			continue
+		}
+		if !filter.Since.IsZero() && event.Time.Before(filter.Since) {
+			continue
+		}
+		matched = append(matched, event)
+	}
+
+	sort.SliceStable(matched, func(i, j int) bool {
+		return matched[i].Time.After(matched[j].Time)
+	})
+	if filter.Limit > 0 && len(matched) > filter.Limit {
+		matched = matched[:filter.Limit]
+	}
+	return matched, nil
+}
+
+// Close closes the sink
+func (l *Log) Close() error {
+	if l.sink == nil {
+		return nil
+	}
+	return l.sink.Close()
+}
@@ -0,0 +1,121 @@
+package audit_test
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"microsoft.com/aml-mcp/internal/audit"
+)
+
+func TestLogQuery(t *testing.T) {
+	l, err := audit.Open("")
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+
+	start := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	l.Record(audit.Event{Time: start, Tool: "start_compute", Outcome: audit.OutcomeSucceeded})
+	l.Record(audit.Event{Time: start.Add(time.Minute), Tool: "stop_compute", Outcome: audit.OutcomeFailed})
+	l.Record(audit.Event{Time: start.Add(2 * time.Minute), Tool: "start_compute", Outcome: "DryRun"})
+
+	tests := []struct {
+		name   string
+		filter audit.Filter
+		want   []string
+	}{
+		{"all, newest first", audit.Filter{}, []string{"start_compute", "stop_compute", "start_compute"}},
+		{"by tool", audit.Filter{Tool: "stop_compute"}, []string{"stop_compute"}},
+		{"by outcome", audit.Filter{Outcome: "failed"}, []string{"stop_compute"}},
+		{"since", audit.Filter{Since: start.Add(time.Minute)}, []string{"start_compute", "stop_compute"}},
+		{"limit", audit.Filter{Limit: 1}, []string{"start_compute"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events, err := l.Query(tt.filter)
+			if err != nil {
+				t.Fatalf("Query() error = %v", err)
+			}
+			var got []string
+			for _, event := range events {
+				got = append(got, event.Tool)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Query() tools = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFileSink(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+
+	l, err := audit.Open(path)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	l.Record(audit.Event{
+		Time:       time.Now().UTC(),
+		Tool:       "create_workspace",
+		Arguments:  map[string]any{"workspace_name": "ws-1"},
+		Caller:     audit.Caller{Name: "user@example.com"},
+		Outcome:    audit.OutcomeSucceeded,
+		RequestIDs: []string{"req-1"},
+	})
+	if err := l.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	// A new log appends to the same file and reads back earlier events
+	l, err = audit.Open(path)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	defer l.Close()
+	l.Record(audit.Event{Time: time.Now().UTC(), Tool: "stop_compute", Outcome: audit.OutcomeSucceeded})
+
+	events, err := l.Query(audit.Filter{Tool: "create_workspace"})
+	if err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+	if len(events) != 1 || events[0].Caller.Name != "user@example.com" || events[0].Arguments["workspace_name"] != "ws-1" {
+		t.Errorf("Query() = %+v, want the create_workspace event from the first log", events)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read audit file: %v", err)
+	}
+	if lines := strings.Count(string(data), "\n"); lines != 2 {
+		t.Errorf("audit file has %d lines, want 2", lines)
+	}
+}
+
+func TestRedact(t *testing.T) {
+	got := audit.Redact(map[string]any{
+		"workspace_name": "ws-1",
+		"confirm_token":  "confirm-123",
+		"properties": map[string]any{
+			"adminPassword": "hunter2",
+			"vmSize":        "Standard_DS3_v2",
+		},
+		"connections": []any{map[string]any{"accessKey": "abc"}},
+	})
+
+	want := map[string]any{
+		"workspace_name": "ws-1",
+		"confirm_token":  audit.Redacted,
+		"properties": map[string]any{
+			"adminPassword": audit.Redacted,
+			"vmSize":        "Standard_DS3_v2",
+		},
+		"connections": []any{map[string]any{"accessKey": audit.Redacted}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Redact() = %v, want %v", got, want)
+	}
+}
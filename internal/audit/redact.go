@@ -0,0 +1,50 @@
+package audit
+
+import "strings"
+
+// Redacted replaces the value of arguments that look like secrets
+const Redacted = "[REDACTED]"
+
+// secretMarkers are substrings of argument names whose values are never logged
+var secretMarkers = []string{"password", "secret", "token", "key", "credential", "connection_string", "sas"}
+
+// Redact returns a copy of arguments with secret-looking values replaced by Redacted
+func Redact(arguments map[string]any) map[string]any {
+	if arguments == nil {
+		return nil
+	}
+	redacted := make(map[string]any, len(arguments))
+	for name, value := range arguments {
+		if isSecret(name) {
+			redacted[name] = Redacted
+			continue
+		}
+		redacted[name] = redactValue(value)
+	}
+	return redacted
+}
+
+func redactValue(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		return Redact(v)
+	case []any:
+		items := make([]any, len(v))
+		for i, item := range v {
+			items[i] = redactValue(item)
+		}
+		return items
+	default:
+		return value
+	}
+}
+
+func isSecret(name string) bool {
+	name = strings.ToLower(name)
+	for _, marker := range secretMarkers {
+		if strings.Contains(name, marker) {
+			return true
+		}
+	}
+	return false
+}
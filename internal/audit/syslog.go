@@ -0,0 +1,47 @@
+//go:build !windows && !plan9
+
+package audit
+
+import (
+	"encoding/json"
+	"log/syslog"
+)
+
+const syslogTag = "aml-mcp"
+
+// SyslogSink sends each event as a JSON message to syslog
+type SyslogSink struct {
+	writer *syslog.Writer
+}
+
+// NewSyslogSink connects to the syslog daemon at address, or the local one
+func NewSyslogSink(address string) (*SyslogSink, error) {
+	priority := syslog.LOG_NOTICE | syslog.LOG_AUTH
+	var (
+		writer *syslog.Writer
+		err    error
+	)
+	if address == "" {
+		writer, err = syslog.New(priority, syslogTag)
+	} else {
+		writer, err = syslog.Dial("udp", address, priority, syslogTag)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &SyslogSink{writer: writer}, nil
+}
+
+// Write sends event to syslog
+func (s *SyslogSink) Write(event Event) error {
+	message, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	return s.writer.Notice(string(message))
+}
+
+// Close closes the connection to syslog
+func (s *SyslogSink) Close() error {
+	return s.writer.Close()
+}
@@ -0,0 +1,26 @@
+//go:build windows || plan9
+
+package audit
+
+import (
+	"errors"
+	"runtime"
+)
+
+// SyslogSink is not available on this platform
+type SyslogSink struct{}
+
+// NewSyslogSink always fails: syslog is not available on this platform
+func NewSyslogSink(address string) (*SyslogSink, error) {
+	return nil, errors.New("syslog is not supported on " + runtime.GOOS)
+}
+
+// Write is never called; NewSyslogSink does not return a sink
+func (s *SyslogSink) Write(event Event) error {
+	return errors.ErrUnsupported
+}
+
+// Close is never called; NewSyslogSink does not return a sink
+func (s *SyslogSink) Close() error {
+	return nil
+}
@@ -0,0 +1,268 @@
+package azure
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"microsoft.com/aml-mcp/internal/logging"
+)
+
+// Credential types AuthOptions.Modes can list
+const (
+	// AuthCLI uses the Azure CLI's signed-in account
+	AuthCLI = "cli"
+	// AuthDefault uses DefaultAzureCredential
+	AuthDefault = "default"
+	// AuthServicePrincipal signs in as an app registration with a secret or certificate
+	AuthServicePrincipal = "service-principal"
+	// AuthWorkloadIdentity exchanges a Kubernetes service account token
+	AuthWorkloadIdentity = "workload-identity"
+	// AuthManagedIdentity uses the managed identity of the Azure host
+	AuthManagedIdentity = "managed-identity"
+	// AuthDeviceCode asks the user to sign in on another device
+	AuthDeviceCode = "device-code"
+	// AuthBrowser opens a browser for the user to sign in
+	AuthBrowser = "browser"
+)
+
+// AuthCustom is the mode of a credential passed in ClientCacheOptions
+const AuthCustom = "custom"
+
+// AuthModes lists every credential type, in the order of the constants
+var AuthModes = []string{AuthCLI, AuthDefault, AuthServicePrincipal, AuthWorkloadIdentity, AuthManagedIdentity, AuthDeviceCode, AuthBrowser}
+
+// Environment variables service principal sign-in falls back to
+const (
+	EnvTenantID            = "AZURE_TENANT_ID"
+	EnvClientID            = "AZURE_CLIENT_ID"
+	EnvClientSecret        = "AZURE_CLIENT_SECRET"
+	EnvCertificatePath     = "AZURE_CLIENT_CERTIFICATE_PATH"
+	EnvCertificatePassword = "AZURE_CLIENT_CERTIFICATE_PASSWORD"
+)
+
+// ErrNoCredential is returned when none of the configured auth modes can get a token
+var ErrNoCredential = errors.New("no Azure credential available")
+
+// DefaultRedirectURL is where browser sign-in returns to when none is configured
+const DefaultRedirectURL = "http://localhost:8080"
+
+// AuthOptions selects how the server signs in to Azure
+type AuthOptions struct {
+	// Modes are the credential types to try, in order; an interactive one may only come last
+	Modes []string `json:"modes"`
+
+	// TenantID is the tenant to sign in to
+	TenantID string `json:"tenantId"`
+	// ClientID is the app registration, user-assigned identity or public client to sign in with
+	ClientID string `json:"clientId"`
+	// ClientSecret is the service principal's secret, never read from the config file
+	ClientSecret string `json:"-"`
+	// CertificatePath is a PEM or PKCS#12 file with the service principal's certificate and key
+	CertificatePath string `json:"certificatePath"`
+	// CertificatePassword decrypts the certificate file, never read from the config file
+	CertificatePassword string `json:"-"`
+	// RedirectURL is where browser sign-in returns to, DefaultRedirectURL by default
+	RedirectURL string `json:"redirectUrl"`
+
+	// SubscriptionTenants maps subscription IDs to the tenant their tokens are requested from
+	SubscriptionTenants map[string]string `json:"subscriptionTenants"`
+}
+
+// Validate reports settings that can never work
+func (o AuthOptions) Validate() error {
+	for i, mode := range o.Modes {
+		if !slices.Contains(AuthModes, mode) {
+			return fmt.Errorf("unknown auth mode %q: use %s", mode, strings.Join(AuthModes, ", "))
+		}
+		if isInteractive(mode) && i != len(o.Modes)-1 {
+			return fmt.Errorf("auth mode %s prompts the user and must come last", mode)
+		}
+	}
+	for subscriptionID, tenantID := range o.SubscriptionTenants {
+		if strings.TrimSpace(tenantID) == "" {
+			return fmt.Errorf("subscription %s has an empty tenant ID", subscriptionID)
+		}
+	}
+	return nil
+}
+
+// isInteractive reports whether a credential type prompts the user to sign in
+func isInteractive(mode string) bool {
+	return mode == AuthDeviceCode || mode == AuthBrowser
+}
+
+// defaultAuthModes are the credential types tried when none are configured
+var defaultAuthModes = []string{AuthCLI, AuthDefault}
+
+// tenantFor returns the tenant configured for a subscription, or ""
+func (o AuthOptions) tenantFor(subscriptionID string) string {
+	for id, tenantID := range o.SubscriptionTenants {
+		if strings.EqualFold(id, subscriptionID) {
+			return tenantID
+		}
+	}
+	return ""
+}
+
+// additionalTenants are the other tenants credentials may request tokens from
+func (o AuthOptions) additionalTenants() []string {
+	var tenants []string
+	for _, tenantID := range o.SubscriptionTenants {
+		if !slices.Contains(tenants, tenantID) {
+			tenants = append(tenants, tenantID)
+		}
+	}
+	return tenants
+}
+
+// newCredential returns a credential of the first configured type that gets a token, and that type
+func newCredential(ctx context.Context, options AuthOptions, prompt DeviceCodePrompt, cloudConfig cloud.Configuration) (azcore.TokenCredential, string, error) {
+	logger := logging.FromContext(ctx)
+	scope := resourceManagerScope(cloudConfig)
+
+	modes := options.Modes
+	if len(modes) == 0 {
+		modes = defaultAuthModes
+	}
+	var failures []string
+	for _, mode := range modes {
+		cred, err := options.credential(mode, prompt, cloudConfig)
+		// Interactive credentials are returned untested
+		if err == nil && !isInteractive(mode) {
+			_, err = cred.GetToken(ctx, policy.TokenRequestOptions{Scopes: []string{scope}})
+		}
+		if err != nil {
+			logger.Info("Azure credential unavailable", "mode", mode, "error", err)
+			failures = append(failures, fmt.Sprintf("%s: %v", mode, err))
+			continue
+		}
+		logger.Info("Using Azure credential", "mode", mode)
+		return cred, mode, nil
+	}
+
+	interactive := ""
+	if !slices.ContainsFunc(modes, isInteractive) {
+		interactive = " (interactive sign-in is not enabled; add device-code or browser to the auth modes to allow it)"
+	}
+	return nil, "", fmt.Errorf("%w from auth modes %s%s: %s", ErrNoCredential, strings.Join(modes, ", "), interactive, strings.Join(failures, "; "))
+}
+
+// credential creates a credential of the given type without getting a token
+func (o AuthOptions) credential(mode string, prompt DeviceCodePrompt, cloudConfig cloud.Configuration) (azcore.TokenCredential, error) {
+	clientOptions := azcore.ClientOptions{Cloud: cloudConfig}
+	tenants := o.additionalTenants()
+
+	switch mode {
+	case AuthCLI:
+		// The CLI signs in to the cloud chosen with "az cloud set"
+		return azidentity.NewAzureCLICredential(&azidentity.AzureCLICredentialOptions{
+			TenantID:                   o.TenantID,
+			AdditionallyAllowedTenants: tenants,
+		})
+	case AuthDefault:
+		return azidentity.NewDefaultAzureCredential(&azidentity.DefaultAzureCredentialOptions{
+			ClientOptions:              clientOptions,
+			TenantID:                   o.TenantID,
+			AdditionallyAllowedTenants: tenants,
+		})
+	case AuthServicePrincipal:
+		return o.servicePrincipalCredential(clientOptions, tenants)
+	case AuthWorkloadIdentity:
+		// Settings left out are read from the environment
+		return azidentity.NewWorkloadIdentityCredential(&azidentity.WorkloadIdentityCredentialOptions{
+			ClientOptions:              clientOptions,
+			TenantID:                   o.TenantID,
+			ClientID:                   o.ClientID,
+			AdditionallyAllowedTenants: tenants,
+		})
+	case AuthManagedIdentity:
+		options := &azidentity.ManagedIdentityCredentialOptions{ClientOptions: clientOptions}
+		if o.ClientID != "" {
+			options.ID = azidentity.ClientID(o.ClientID)
+		}
+		return azidentity.NewManagedIdentityCredential(options)
+	case AuthDeviceCode:
+		// stdout carries the stdio transport
+		if prompt == nil {
+			prompt = logDeviceCodePrompt
+		}
+		return azidentity.NewDeviceCodeCredential(&azidentity.DeviceCodeCredentialOptions{
+			ClientOptions:              clientOptions,
+			TenantID:                   o.TenantID,
+			ClientID:                   o.ClientID,
+			AdditionallyAllowedTenants: tenants,
+			UserPrompt: func(ctx context.Context, message azidentity.DeviceCodeMessage) error {
+				return prompt(ctx, message.Message)
+			},
+		})
+	case AuthBrowser:
+		redirectURL := o.RedirectURL
+		if redirectURL == "" {
+			redirectURL = DefaultRedirectURL
+		}
+		return azidentity.NewInteractiveBrowserCredential(&azidentity.InteractiveBrowserCredentialOptions{
+			ClientOptions:              clientOptions,
+			TenantID:                   o.TenantID,
+			ClientID:                   o.ClientID,
+			AdditionallyAllowedTenants: tenants,
+			RedirectURL:                redirectURL,
+		})
+	default:
+		return nil, fmt.Errorf("unknown auth mode %q", mode)
+	}
+}
+
+// servicePrincipalCredential signs in as an app registration with a secret or certificate
+func (o AuthOptions) servicePrincipalCredential(clientOptions azcore.ClientOptions, tenants []string) (azcore.TokenCredential, error) {
+	tenantID := firstNonEmpty(o.TenantID, os.Getenv(EnvTenantID))
+	clientID := firstNonEmpty(o.ClientID, os.Getenv(EnvClientID))
+	if tenantID == "" || clientID == "" {
+		return nil, fmt.Errorf("service principal sign-in needs a tenant and client ID: set tenantId and clientId, or %s and %s", EnvTenantID, EnvClientID)
+	}
+
+	if secret := firstNonEmpty(o.ClientSecret, os.Getenv(EnvClientSecret)); secret != "" {
+		return azidentity.NewClientSecretCredential(tenantID, clientID, secret, &azidentity.ClientSecretCredentialOptions{
+			ClientOptions:              clientOptions,
+			AdditionallyAllowedTenants: tenants,
+		})
+	}
+
+	certificatePath := firstNonEmpty(o.CertificatePath, os.Getenv(EnvCertificatePath))
+	if certificatePath == "" {
+		return nil, fmt.Errorf("service principal sign-in needs a secret or certificate: set %s, or certificatePath or %s", EnvClientSecret, EnvCertificatePath)
+	}
+	data, err := os.ReadFile(certificatePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read service principal certificate: %v", err)
+	}
+	password := firstNonEmpty(o.CertificatePassword, os.Getenv(EnvCertificatePassword))
+	certs, key, err := azidentity.ParseCertificates(data, []byte(password))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse service principal certificate %s: %v", certificatePath, err)
+	}
+	return azidentity.NewClientCertificateCredential(tenantID, clientID, certs, key, &azidentity.ClientCertificateCredentialOptions{
+		ClientOptions:              clientOptions,
+		AdditionallyAllowedTenants: tenants,
+	})
+}
+
+// tenantCredential requests tokens from a fixed tenant
+type tenantCredential struct {
+	azcore.TokenCredential
+	tenantID string
+}
+
+func (c tenantCredential) GetToken(ctx context.Context, options policy.TokenRequestOptions) (azcore.AccessToken, error) {
+	if options.TenantID == "" {
+		options.TenantID = c.tenantID
+	}
+	return c.TokenCredential.GetToken(ctx, options)
+}
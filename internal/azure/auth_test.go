@@ -0,0 +1,179 @@
+package azure_test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/machinelearning/armmachinelearning"
+	"microsoft.com/aml-mcp/internal/azure"
+	"microsoft.com/aml-mcp/internal/fakearm"
+)
+
+func TestAuthOptionsValidate(t *testing.T) {
+	tests := []struct {
+		name        string
+		options     azure.AuthOptions
+		errContains string
+	}{
+		{name: "default"},
+		{name: "every mode", options: azure.AuthOptions{Modes: azure.AuthModes[:len(azure.AuthModes)-1]}},
+		{name: "non-interactive", options: azure.AuthOptions{Modes: []string{azure.AuthManagedIdentity, azure.AuthCLI}}},
+		{
+			name:        "unknown mode",
+			options:     azure.AuthOptions{Modes: []string{"cli", "password"}},
+			errContains: `unknown auth mode "password"`,
+		},
+		{
+			name:        "interactive mode not last",
+			options:     azure.AuthOptions{Modes: []string{azure.AuthBrowser, azure.AuthCLI}},
+			errContains: "auth mode browser prompts the user and must come last",
+		},
+		{
+			name:        "empty subscription tenant",
+			options:     azure.AuthOptions{SubscriptionTenants: map[string]string{"sub-1": " "}},
+			errContains: "subscription sub-1 has an empty tenant ID",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.options.Validate()
+			if tt.errContains == "" {
+				if err != nil {
+					t.Errorf("Validate() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.errContains) {
+				t.Errorf("Validate() error = %v, want one containing %q", err, tt.errContains)
+			}
+		})
+	}
+}
+
+func TestClientCacheAuthModes(t *testing.T) {
+	for _, name := range []string{azure.EnvTenantID, azure.EnvClientID, azure.EnvClientSecret, azure.EnvCertificatePath, "AZURE_FEDERATED_TOKEN_FILE"} {
+		t.Setenv(name, "")
+	}
+
+	tests := []struct {
+		name     string
+		options  azure.AuthOptions
+		contains []string
+	}{
+		{
+			name:    "service principal without client ID",
+			options: azure.AuthOptions{Modes: []string{azure.AuthServicePrincipal}},
+			contains: []string{
+				"no Azure credential available from auth modes service-principal (interactive sign-in is not enabled",
+				"needs a tenant and client ID",
+			},
+		},
+		{
+			name:     "service principal without secret",
+			options:  azure.AuthOptions{Modes: []string{azure.AuthServicePrincipal}, TenantID: "tenant-1", ClientID: "client-1"},
+			contains: []string{"needs a secret or certificate"},
+		},
+		{
+			name: "service principal with missing certificate",
+			options: azure.AuthOptions{
+				Modes:           []string{azure.AuthServicePrincipal},
+				TenantID:        "tenant-1",
+				ClientID:        "client-1",
+				CertificatePath: filepath.Join(t.TempDir(), "missing.pem"),
+			},
+			contains: []string{"failed to read service principal certificate"},
+		},
+		{
+			name:    "every failure is reported",
+			options: azure.AuthOptions{Modes: []string{azure.AuthServicePrincipal, azure.AuthWorkloadIdentity}},
+			contains: []string{
+				"auth modes service-principal, workload-identity",
+				"service-principal: service principal sign-in needs",
+				"workload-identity: ",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cache := azure.NewClientCacheWithOptions(azure.ClientCacheOptions{Auth: tt.options})
+			_, err := cache.Get(context.Background(), "sub-1")
+			if err == nil {
+				t.Fatal("Get() succeeded without a usable credential")
+			}
+			for _, want := range tt.contains {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("Get() error = %v, want one containing %q", err, want)
+				}
+			}
+		})
+	}
+}
+
+func TestClientCacheDefaultAuthModes(t *testing.T) {
+	for _, name := range []string{azure.EnvTenantID, azure.EnvClientID, azure.EnvClientSecret, azure.EnvCertificatePath, "AZURE_FEDERATED_TOKEN_FILE"} {
+		t.Setenv(name, "")
+	}
+	// Neither the Azure CLI nor the Azure Developer CLI can be found
+	t.Setenv("PATH", t.TempDir())
+	t.Setenv("DISPLAY", ":0")
+
+	// A display does not make the server open a browser
+	cache := azure.NewClientCacheWithOptions(azure.ClientCacheOptions{})
+	_, err := cache.Get(context.Background(), "sub-1")
+	if !errors.Is(err, azure.ErrNoCredential) {
+		t.Fatalf("Get() error = %v, want %v", err, azure.ErrNoCredential)
+	}
+	if want := "auth modes cli, default (interactive sign-in is not enabled"; !strings.Contains(err.Error(), want) {
+		t.Errorf("Get() error = %v, want one containing %q", err, want)
+	}
+}
+
+func TestClientCacheSubscriptionTenants(t *testing.T) {
+	fake := fakearm.New()
+	defer fake.Close()
+	fake.AddWorkspace("sub-home", "rg-1", armmachinelearning.Workspace{Name: to.Ptr("ws-1")})
+	fake.AddWorkspace("sub-guest", "rg-1", armmachinelearning.Workspace{Name: to.Ptr("ws-1")})
+
+	credential := &tokenRecorder{TokenCredential: fake.Credential()}
+	cache := azure.NewClientCacheWithOptions(azure.ClientCacheOptions{
+		Credential:    credential,
+		ClientOptions: fake.ClientOptions(),
+		Auth:          azure.AuthOptions{SubscriptionTenants: map[string]string{"SUB-GUEST": "guest-tenant"}},
+	})
+
+	for _, tt := range []struct {
+		subscriptionID string
+		tenantID       string
+	}{
+		{"sub-home", ""},
+		{"sub-guest", "guest-tenant"},
+	} {
+		clients, err := cache.Get(context.Background(), tt.subscriptionID)
+		if err != nil {
+			t.Fatalf("Get(%s) error = %v", tt.subscriptionID, err)
+		}
+		credential.mu.Lock()
+		credential.requests = nil
+		credential.mu.Unlock()
+		if _, err := clients.WorkspacesClient.Get(context.Background(), "rg-1", "ws-1", nil); err != nil {
+			t.Fatalf("Get workspace in %s error = %v", tt.subscriptionID, err)
+		}
+
+		credential.mu.Lock()
+		if len(credential.requests) == 0 {
+			t.Errorf("no token was requested for %s", tt.subscriptionID)
+		}
+		for _, request := range credential.requests {
+			if request.TenantID != tt.tenantID {
+				t.Errorf("token for %s requested from tenant %q, want %q", tt.subscriptionID, request.TenantID, tt.tenantID)
+			}
+		}
+		credential.mu.Unlock()
+	}
+}
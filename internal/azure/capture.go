@@ -0,0 +1,81 @@
+package azure
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+)
+
+// CapturedRequest is an ARM request recorded instead of being sent
+type CapturedRequest struct {
+	Method string          `json:"method"`
+	URL    string          `json:"url"`
+	Body   json.RawMessage `json:"body,omitempty"`
+}
+
+// errCaptured stops the SDK pipeline once the request has been recorded
+var errCaptured = errors.New("request captured")
+
+// Capture runs send against clients that record requests instead of sending them and returns the first
+func (c *ClientCache) Capture(ctx context.Context, subscriptionID string, send func(*ClientSet) error) (*CapturedRequest, error) {
+	var options arm.ClientOptions
+	if c.options.ClientOptions != nil {
+		options = *c.options.ClientOptions
+	}
+	options.Cloud = c.cloud()
+	transport := &captureTransport{}
+	options.Transport = transport
+	options.Retry.MaxRetries = -1
+	options.DisableRPRegistration = true
+
+	clients, err := NewClientSetWithCredential(subscriptionID, placeholderCredential{}, &options)
+	if err != nil {
+		return nil, err
+	}
+
+	err = send(clients)
+	if transport.captured != nil {
+		return transport.captured, nil
+	}
+	if err == nil {
+		err = errors.New("no request was made")
+	}
+	return nil, fmt.Errorf("failed to build request: %v", err)
+}
+
+// captureTransport records the first request it is given and fails it
+type captureTransport struct {
+	captured *CapturedRequest
+}
+
+func (t *captureTransport) Do(req *http.Request) (*http.Response, error) {
+	if t.captured == nil {
+		captured := &CapturedRequest{Method: req.Method, URL: req.URL.String()}
+		if req.Body != nil {
+			body, err := io.ReadAll(req.Body)
+			if err != nil {
+				return nil, err
+			}
+			if len(body) > 0 {
+				captured.Body = body
+			}
+		}
+		t.captured = captured
+	}
+	return nil, errCaptured
+}
+
+// placeholderCredential issues a token that is never sent anywhere
+type placeholderCredential struct{}
+
+func (placeholderCredential) GetToken(ctx context.Context, options policy.TokenRequestOptions) (azcore.AccessToken, error) {
+	return azcore.AccessToken{Token: "capture", ExpiresOn: time.Now().Add(time.Hour)}, nil
+}
@@ -2,15 +2,26 @@ package azure
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"log"
-	"os"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
-	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
 	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/authorization/armauthorization/v2"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/machinelearning/armmachinelearning"
-) // ClientSet holds all Azure ML service clients
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armsubscriptions"
+	"go.opentelemetry.io/otel/trace"
+	"microsoft.com/aml-mcp/internal/logging"
+)
+
+// ClientSet holds all Azure ML service clients
 type ClientSet struct {
 	WorkspacesClient           *armmachinelearning.WorkspacesClient
 	ComputeClient              *armmachinelearning.ComputeClient
@@ -20,107 +31,88 @@ type ClientSet struct {
 	PrivateEndpointClient      *armmachinelearning.PrivateEndpointConnectionsClient
 	WorkspaceConnectionsClient *armmachinelearning.WorkspaceConnectionsClient
 	WorkspaceFeaturesClient    *armmachinelearning.WorkspaceFeaturesClient
+	PermissionsClient          *armauthorization.PermissionsClient
+	RoleAssignmentsClient      *armauthorization.RoleAssignmentsClient
+	RoleDefinitionsClient      *armauthorization.RoleDefinitionsClient
+}
+
+// DeviceCodePrompt shows the device code sign-in instructions to the user
+type DeviceCodePrompt func(ctx context.Context, message string) error
+
+// logDeviceCodePrompt is the DeviceCodePrompt used when none is configured
+func logDeviceCodePrompt(ctx context.Context, message string) error {
+	logging.FromContext(ctx).Warn("Azure sign-in required", "instructions", message)
+	return nil
 }
 
-// getAzureCredential attempts to get Azure credentials using multiple methods
-func getAzureCredential() (azcore.TokenCredential, error) {
-	// First, try Azure CLI credentials (which VS Code often uses)
-	if cred, err := azidentity.NewAzureCLICredential(nil); err == nil {
-		// Test the credential by trying to get a token
-		ctx := context.Background()
-		_, err = cred.GetToken(ctx, policy.TokenRequestOptions{
-			Scopes: []string{"https://management.azure.com/.default"},
-		})
-		if err == nil {
-			log.Println("Using Azure CLI credentials (Visual Studio/VS Code compatible)")
-			return cred, nil
-		}
-		log.Printf("Azure CLI credentials failed: %v", err)
-	}
-
-	// Second, try DefaultAzureCredential (includes Azure CLI, managed identity, etc.)
-	if cred, err := azidentity.NewDefaultAzureCredential(nil); err == nil {
-		// Test the credential by trying to get a token
-		ctx := context.Background()
-		_, err = cred.GetToken(ctx, policy.TokenRequestOptions{
-			Scopes: []string{"https://management.azure.com/.default"},
-		})
-		if err == nil {
-			log.Println("Using existing Azure credentials (Azure CLI, Managed Identity, or Environment)")
-			return cred, nil
-		}
-		log.Printf("Default credentials failed: %v", err)
-	}
-
-	// If no existing credentials, try interactive browser authentication
-	log.Println("No existing Azure credentials found. Opening browser for interactive login...")
-
-	// Check if we're in a headless environment
-	if os.Getenv("DISPLAY") == "" && os.Getenv("WAYLAND_DISPLAY") == "" && os.Getenv("XDG_SESSION_TYPE") == "" {
-		// Headless environment, use device code flow
-		log.Println("Headless environment detected. Using device code authentication...")
-		return azidentity.NewDeviceCodeCredential(&azidentity.DeviceCodeCredentialOptions{
-			UserPrompt: func(ctx context.Context, message azidentity.DeviceCodeMessage) error {
-				fmt.Printf("\n%s\n", message.Message)
-				return nil
-			},
-		})
-	}
-
-	// Use interactive browser authentication
-	return azidentity.NewInteractiveBrowserCredential(&azidentity.InteractiveBrowserCredentialOptions{
-		RedirectURL: "http://localhost:8080",
-	})
-}
-
-// NewClientSet creates a new set of Azure ML clients
+// NewClientSet creates a new set of Azure ML clients in the public cloud
 func NewClientSet(subscriptionID string) (*ClientSet, error) {
 	// Get Azure credential with interactive fallback
-	cred, err := getAzureCredential()
+	cred, _, err := newCredential(context.Background(), AuthOptions{}, nil, cloud.AzurePublic)
 	if err != nil {
 		return nil, fmt.Errorf("failed to obtain Azure credential: %v", err)
 	}
 
-	workspacesClient, err := armmachinelearning.NewWorkspacesClient(subscriptionID, cred, nil)
+	return NewClientSetWithCredential(subscriptionID, cred, nil)
+}
+
+// NewClientSetWithCredential creates a new set of Azure ML clients using an existing credential and options
+func NewClientSetWithCredential(subscriptionID string, cred azcore.TokenCredential, options *arm.ClientOptions) (*ClientSet, error) {
+	workspacesClient, err := armmachinelearning.NewWorkspacesClient(subscriptionID, cred, options)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create workspaces client: %v", err)
 	}
 
-	computeClient, err := armmachinelearning.NewComputeClient(subscriptionID, cred, nil)
+	computeClient, err := armmachinelearning.NewComputeClient(subscriptionID, cred, options)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create compute client: %v", err)
 	}
 
-	quotasClient, err := armmachinelearning.NewQuotasClient(subscriptionID, cred, nil)
+	quotasClient, err := armmachinelearning.NewQuotasClient(subscriptionID, cred, options)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create quotas client: %v", err)
 	}
 
-	usagesClient, err := armmachinelearning.NewUsagesClient(subscriptionID, cred, nil)
+	usagesClient, err := armmachinelearning.NewUsagesClient(subscriptionID, cred, options)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create usages client: %v", err)
 	}
 
-	vmSizesClient, err := armmachinelearning.NewVirtualMachineSizesClient(subscriptionID, cred, nil)
+	vmSizesClient, err := armmachinelearning.NewVirtualMachineSizesClient(subscriptionID, cred, options)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create VM sizes client: %v", err)
 	}
 
-	privateEndpointClient, err := armmachinelearning.NewPrivateEndpointConnectionsClient(subscriptionID, cred, nil)
+	privateEndpointClient, err := armmachinelearning.NewPrivateEndpointConnectionsClient(subscriptionID, cred, options)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create private endpoint client: %v", err)
 	}
 
-	workspaceConnectionsClient, err := armmachinelearning.NewWorkspaceConnectionsClient(subscriptionID, cred, nil)
+	workspaceConnectionsClient, err := armmachinelearning.NewWorkspaceConnectionsClient(subscriptionID, cred, options)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create workspace connections client: %v", err)
 	}
 
-	workspaceFeaturesClient, err := armmachinelearning.NewWorkspaceFeaturesClient(subscriptionID, cred, nil)
+	workspaceFeaturesClient, err := armmachinelearning.NewWorkspaceFeaturesClient(subscriptionID, cred, options)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create workspace features client: %v", err)
 	}
 
+	permissionsClient, err := armauthorization.NewPermissionsClient(subscriptionID, cred, options)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create permissions client: %v", err)
+	}
+
+	roleAssignmentsClient, err := armauthorization.NewRoleAssignmentsClient(subscriptionID, cred, options)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create role assignments client: %v", err)
+	}
+
+	roleDefinitionsClient, err := armauthorization.NewRoleDefinitionsClient(cred, options)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create role definitions client: %v", err)
+	}
+
 	return &ClientSet{
 		WorkspacesClient:           workspacesClient,
 		ComputeClient:              computeClient,
@@ -130,5 +122,237 @@ func NewClientSet(subscriptionID string) (*ClientSet, error) {
 		PrivateEndpointClient:      privateEndpointClient,
 		WorkspaceConnectionsClient: workspaceConnectionsClient,
 		WorkspaceFeaturesClient:    workspaceFeaturesClient,
+		PermissionsClient:          permissionsClient,
+		RoleAssignmentsClient:      roleAssignmentsClient,
+		RoleDefinitionsClient:      roleDefinitionsClient,
 	}, nil
 }
+
+// ClientCache owns a long-lived Azure credential and lazily builds one ClientSet per subscription
+type ClientCache struct {
+	options ClientCacheOptions
+
+	// credMu serialises credential discovery so concurrent callers share one probe
+	credMu     sync.Mutex
+	credential azcore.TokenCredential
+	// credentialMode is the auth mode credential was selected by
+	credentialMode string
+
+	mu sync.RWMutex
+	// clientSets and limiters are keyed on the lower-cased subscription ID
+	clientSets map[string]*ClientSet
+	// subscriptions lists the subscriptions the credential can see
+	subscriptions *armsubscriptions.Client
+	// limiters outlive the client sets, so Invalidate does not reset them
+	limiters map[string]*tokenBucket
+}
+
+// ClientCacheOptions customises how a ClientCache authenticates and reaches ARM
+type ClientCacheOptions struct {
+	// Credential is used instead of running credential discovery when set
+	Credential azcore.TokenCredential
+
+	// ClientOptions is passed to every ARM client the cache creates
+	ClientOptions *arm.ClientOptions
+
+	// Cloud is the Azure cloud to use instead of the cloud of ClientOptions
+	Cloud cloud.Configuration
+
+	// Auth selects the credential types tried when Credential is not set
+	Auth AuthOptions
+
+	// DeviceCodePrompt shows the instructions of the device-code auth mode
+	DeviceCodePrompt DeviceCodePrompt
+
+	// RequestPolicy sets retries, timeouts and rate limits of every ARM client
+	RequestPolicy RequestPolicy
+
+	// TracerProvider, when set, records every ARM request attempt as a span
+	TracerProvider trace.TracerProvider
+}
+
+// NewClientCache creates an empty ClientCache
+func NewClientCache() *ClientCache {
+	return NewClientCacheWithOptions(ClientCacheOptions{})
+}
+
+// NewClientCacheWithOptions creates an empty ClientCache with the given options
+func NewClientCacheWithOptions(options ClientCacheOptions) *ClientCache {
+	return &ClientCache{
+		options:    options,
+		clientSets: make(map[string]*ClientSet),
+		limiters:   make(map[string]*tokenBucket),
+	}
+}
+
+// Get returns the cached ClientSet for a subscription, creating it on first use
+func (c *ClientCache) Get(ctx context.Context, subscriptionID string) (*ClientSet, error) {
+	key := strings.ToLower(subscriptionID)
+	c.mu.RLock()
+	clients, ok := c.clientSets[key]
+	c.mu.RUnlock()
+	if ok {
+		return clients, nil
+	}
+
+	cred, err := c.getCredential(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// Another caller may have built the clients while we were waiting
+	if clients, ok := c.clientSets[key]; ok {
+		return clients, nil
+	}
+
+	// Subscriptions in other tenants get tokens from their own tenant
+	if tenantID := c.options.Auth.tenantFor(subscriptionID); tenantID != "" {
+		cred = tenantCredential{TokenCredential: cred, tenantID: tenantID}
+	}
+	clients, err = NewClientSetWithCredential(subscriptionID, cred, c.clientOptions(subscriptionID))
+	if err != nil {
+		return nil, err
+	}
+	c.clientSets[key] = clients
+	return clients, nil
+}
+
+// SubscriptionsClient returns the client listing the subscriptions the credential can see
+func (c *ClientCache) SubscriptionsClient(ctx context.Context) (*armsubscriptions.Client, error) {
+	c.mu.RLock()
+	client := c.subscriptions
+	c.mu.RUnlock()
+	if client != nil {
+		return client, nil
+	}
+
+	cred, err := c.getCredential(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.subscriptions != nil {
+		return c.subscriptions, nil
+	}
+	client, err = armsubscriptions.NewClient(cred, c.clientOptions(""))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create subscriptions client: %v", err)
+	}
+	c.subscriptions = client
+	return client, nil
+}
+
+// clientOptions returns the client options for a subscription's clients; the caller must hold c.mu
+func (c *ClientCache) clientOptions(subscriptionID string) *arm.ClientOptions {
+	var options arm.ClientOptions
+	if c.options.ClientOptions != nil {
+		options = *c.options.ClientOptions
+	}
+	options.Cloud = c.cloud()
+	options.PerCallPolicies = append(slices.Clone(options.PerCallPolicies), requestTracePolicy{})
+	options.PerRetryPolicies = slices.Clone(options.PerRetryPolicies)
+	c.options.RequestPolicy.apply(&options)
+
+	key := strings.ToLower(subscriptionID)
+	limiter, ok := c.limiters[key]
+	if !ok {
+		limiter = c.options.RequestPolicy.newLimiter()
+		c.limiters[key] = limiter
+	}
+	if limiter != nil {
+		options.PerRetryPolicies = append(options.PerRetryPolicies, rateLimitPolicy{bucket: limiter})
+	}
+	if c.options.TracerProvider != nil {
+		options.PerRetryPolicies = append(options.PerRetryPolicies, newOTelPolicy(c.options.TracerProvider))
+	}
+	return &options
+}
+
+// cloud returns the Azure cloud of the cache's credential and clients
+func (c *ClientCache) cloud() cloud.Configuration {
+	if !isZeroCloud(c.options.Cloud) {
+		return c.options.Cloud
+	}
+	if options := c.options.ClientOptions; options != nil && !isZeroCloud(options.Cloud) {
+		return options.Cloud
+	}
+	return cloud.AzurePublic
+}
+
+// getCredential returns the cached credential, running discovery if there is none
+func (c *ClientCache) getCredential(ctx context.Context) (azcore.TokenCredential, error) {
+	c.credMu.Lock()
+	defer c.credMu.Unlock()
+
+	if c.credential != nil {
+		return c.credential, nil
+	}
+
+	if c.options.Credential != nil {
+		c.credential, c.credentialMode = c.options.Credential, AuthCustom
+		return c.credential, nil
+	}
+
+	cred, mode, err := newCredential(ctx, c.options.Auth, c.options.DeviceCodePrompt, c.cloud())
+	if err != nil {
+		return nil, fmt.Errorf("failed to obtain Azure credential: %w", err)
+	}
+	c.credential, c.credentialMode = cred, mode
+	return cred, nil
+}
+
+// Invalidate discards the cached credential and every cached client
+func (c *ClientCache) Invalidate() {
+	c.credMu.Lock()
+	c.credential, c.credentialMode = nil, ""
+	c.credMu.Unlock()
+
+	c.mu.Lock()
+	c.clientSets = make(map[string]*ClientSet)
+	c.subscriptions = nil
+	c.mu.Unlock()
+}
+
+// InvalidateOnAuthError calls Invalidate if err is an authentication failure and reports whether it did
+func (c *ClientCache) InvalidateOnAuthError(err error) bool {
+	if !IsAuthError(err) {
+		return false
+	}
+	slog.Warn("Discarding cached Azure credential after authentication failure", "error", err)
+	c.Invalidate()
+	return true
+}
+
+// IsAuthError reports whether err means the credential could not authenticate
+func IsAuthError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	if errors.Is(err, ErrNoCredential) {
+		return true
+	}
+
+	var authFailed *azidentity.AuthenticationFailedError
+	if errors.As(err, &authFailed) {
+		return true
+	}
+
+	var authRequired *azidentity.AuthenticationRequiredError
+	if errors.As(err, &authRequired) {
+		return true
+	}
+
+	var respErr *azcore.ResponseError
+	if errors.As(err, &respErr) && respErr.StatusCode == http.StatusUnauthorized {
+		return true
+	}
+
+	return false
+}
@@ -1,9 +1,19 @@
 package azure_test
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
 	"testing"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/machinelearning/armmachinelearning"
 	"microsoft.com/aml-mcp/internal/azure"
+	"microsoft.com/aml-mcp/internal/fakearm"
 )
 
 func TestNewClientSet(t *testing.T) {
@@ -110,3 +120,236 @@ func TestClientSetStructure(t *testing.T) {
 		t.Error("Expected WorkspaceFeaturesClient to be nil in uninitialized ClientSet")
 	}
 }
+
+func TestClientCacheInvalidate(t *testing.T) {
+	cache := azure.NewClientCache()
+
+	// Invalidating an empty cache must be safe
+	cache.Invalidate()
+
+	if cache.InvalidateOnAuthError(nil) {
+		t.Error("InvalidateOnAuthError(nil) = true, want false")
+	}
+	if cache.InvalidateOnAuthError(errors.New("boom")) {
+		t.Error("InvalidateOnAuthError() = true for a non-auth error")
+	}
+}
+
+func TestIsAuthError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{
+			name:     "nil error",
+			err:      nil,
+			expected: false,
+		},
+		{
+			name:     "plain error",
+			err:      errors.New("connection reset"),
+			expected: false,
+		},
+		{
+			name:     "unauthorized response",
+			err:      &azcore.ResponseError{StatusCode: http.StatusUnauthorized},
+			expected: true,
+		},
+		{
+			name:     "wrapped unauthorized response",
+			err:      fmt.Errorf("list failed: %w", &azcore.ResponseError{StatusCode: http.StatusUnauthorized}),
+			expected: true,
+		},
+		{
+			name:     "forbidden response",
+			err:      &azcore.ResponseError{StatusCode: http.StatusForbidden},
+			expected: false,
+		},
+		{
+			name:     "authentication failed",
+			err:      &azidentity.AuthenticationFailedError{},
+			expected: true,
+		},
+		{
+			name:     "no credential",
+			err:      fmt.Errorf("failed to obtain Azure credential: %w", azure.ErrNoCredential),
+			expected: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := azure.IsAuthError(tt.err); got != tt.expected {
+				t.Errorf("IsAuthError() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestNewClientSetWithCredential(t *testing.T) {
+	fake := fakearm.New()
+	defer fake.Close()
+	fake.AddWorkspace("sub-1", "rg-1", armmachinelearning.Workspace{Name: to.Ptr("ws-1")})
+
+	clients, err := azure.NewClientSetWithCredential("sub-1", fake.Credential(), fake.ClientOptions())
+	if err != nil {
+		t.Fatalf("NewClientSetWithCredential() error = %v", err)
+	}
+
+	resp, err := clients.WorkspacesClient.Get(context.Background(), "rg-1", "ws-1", nil)
+	if err != nil {
+		t.Fatalf("Get() through fake ARM server failed: %v", err)
+	}
+	if got := *resp.Name; got != "ws-1" {
+		t.Errorf("workspace name = %q, want %q", got, "ws-1")
+	}
+}
+
+func TestClientCacheGet(t *testing.T) {
+	fake := fakearm.New()
+	defer fake.Close()
+
+	cache := fake.ClientCache()
+	ctx := context.Background()
+
+	first, err := cache.Get(ctx, "sub-1")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	second, err := cache.Get(ctx, "sub-1")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if first != second {
+		t.Error("Get() built a new ClientSet for a cached subscription")
+	}
+	upper, err := cache.Get(ctx, "SUB-1")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if upper != first {
+		t.Error("Get() built a new ClientSet for a differently-cased subscription ID")
+	}
+
+	other, err := cache.Get(ctx, "sub-2")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if other == first {
+		t.Error("Get() returned the same ClientSet for different subscriptions")
+	}
+
+	if !cache.InvalidateOnAuthError(&azcore.ResponseError{StatusCode: http.StatusUnauthorized}) {
+		t.Fatal("InvalidateOnAuthError() = false for a 401 response")
+	}
+	third, err := cache.Get(ctx, "sub-1")
+	if err != nil {
+		t.Fatalf("Get() after invalidation error = %v", err)
+	}
+	if third == first {
+		t.Error("Get() returned a stale ClientSet after invalidation")
+	}
+}
+
+func TestClientCacheCapture(t *testing.T) {
+	fake := fakearm.New()
+	defer fake.Close()
+
+	cache := fake.ClientCache()
+	captured, err := cache.Capture(context.Background(), "sub-1", func(clients *azure.ClientSet) error {
+		_, err := clients.WorkspacesClient.BeginCreateOrUpdate(context.Background(), "rg-1", "ws-1", armmachinelearning.Workspace{
+			Location: to.Ptr("eastus"),
+		}, nil)
+		return err
+	})
+	if err != nil {
+		t.Fatalf("Capture() error = %v", err)
+	}
+
+	if captured.Method != http.MethodPut {
+		t.Errorf("Method = %q, want PUT", captured.Method)
+	}
+	wantURL := fake.URL() + "/subscriptions/sub-1/resourceGroups/rg-1/providers/Microsoft.MachineLearningServices/workspaces/ws-1?api-version="
+	if !strings.HasPrefix(captured.URL, wantURL) {
+		t.Errorf("URL = %q, want prefix %q", captured.URL, wantURL)
+	}
+	if !strings.Contains(string(captured.Body), `"location":"eastus"`) {
+		t.Errorf("Body = %s, want the workspace location", captured.Body)
+	}
+	if requests := fake.Requests(); len(requests) != 0 {
+		t.Errorf("Capture() sent %d requests to ARM, want none", len(requests))
+	}
+
+	_, err = cache.Capture(context.Background(), "sub-1", func(clients *azure.ClientSet) error {
+		return errors.New("invalid input")
+	})
+	if err == nil || !strings.Contains(err.Error(), "invalid input") {
+		t.Errorf("Capture() error = %v, want the send error", err)
+	}
+}
+
+func TestClientCacheIdentity(t *testing.T) {
+	fake := fakearm.New()
+	defer fake.Close()
+
+	cache := fake.ClientCache()
+	if _, ok := cache.Identity(context.Background()); ok {
+		t.Error("Identity() reported an identity before any credential was acquired")
+	}
+
+	if _, err := cache.Get(context.Background(), "sub-1"); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	identity, ok := cache.Identity(context.Background())
+	if !ok {
+		t.Fatal("Identity() = false after the credential was acquired")
+	}
+	want := azure.Identity{ObjectID: fakearm.ObjectID, TenantID: fakearm.TenantID, Name: fakearm.UserName}
+	if identity != want {
+		t.Errorf("Identity() = %+v, want %+v", identity, want)
+	}
+}
+
+func TestParseTokenIdentity(t *testing.T) {
+	// {"oid":"o","tid":"t","appid":"a"}, as issued to a service principal
+	identity, err := azure.ParseTokenIdentity("e30.eyJvaWQiOiJvIiwidGlkIjoidCIsImFwcGlkIjoiYSJ9.sig")
+	if err != nil {
+		t.Fatalf("ParseTokenIdentity() error = %v", err)
+	}
+	if want := (azure.Identity{ObjectID: "o", TenantID: "t", AppID: "a"}); identity != want {
+		t.Errorf("ParseTokenIdentity() = %+v, want %+v", identity, want)
+	}
+
+	if _, err := azure.ParseTokenIdentity("not-a-jwt"); err == nil {
+		t.Error("ParseTokenIdentity() accepted a token that is not a JWT")
+	}
+}
+
+func TestRequestTrace(t *testing.T) {
+	fake := fakearm.New()
+	defer fake.Close()
+	fake.AddWorkspace("sub-1", "rg-1", armmachinelearning.Workspace{Name: to.Ptr("ws-1")})
+
+	clients, err := fake.ClientCache().Get(context.Background(), "sub-1")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+
+	ctx, trace := azure.WithRequestTrace(context.Background())
+	if _, err := clients.WorkspacesClient.Get(ctx, "rg-1", "ws-1", nil); err != nil {
+		t.Fatalf("Get workspace error = %v", err)
+	}
+	if _, err := clients.WorkspacesClient.Get(ctx, "rg-1", "missing", nil); err == nil {
+		t.Fatal("Get of a missing workspace succeeded")
+	}
+
+	if ids := trace.RequestIDs(); len(ids) != 2 {
+		t.Errorf("RequestIDs() = %v, want one per request including the failed one", ids)
+	}
+	for _, request := range fake.Requests() {
+		if request.CorrelationID != trace.CorrelationID {
+			t.Errorf("request %s correlation ID = %q, want %q", request.Path, request.CorrelationID, trace.CorrelationID)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package azure
+
+import (
+	"fmt"
+	"maps"
+	"net/url"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
+)
+
+// Names of the Azure clouds CloudOptions can select
+const (
+	CloudPublic     = "AzurePublic"
+	CloudChina      = "AzureChina"
+	CloudGovernment = "AzureGovernment"
+)
+
+// clouds maps the lower-cased cloud names CloudOptions accepts to their configuration
+var clouds = map[string]cloud.Configuration{
+	"azurepublic":       cloud.AzurePublic,
+	"azurecloud":        cloud.AzurePublic,
+	"azurechina":        cloud.AzureChina,
+	"azurechinacloud":   cloud.AzureChina,
+	"azuregovernment":   cloud.AzureGovernment,
+	"azureusgovernment": cloud.AzureGovernment,
+}
+
+// CloudOptions selects the Azure cloud the server signs in to and manages resources in
+type CloudOptions struct {
+	// Name is AzurePublic (default), AzureChina, AzureGovernment or an Azure CLI cloud name
+	Name string `json:"name"`
+
+	// ResourceManagerEndpoint replaces the named cloud's Resource Manager endpoint
+	ResourceManagerEndpoint string `json:"resourceManagerEndpoint"`
+	// ResourceManagerAudience replaces the audience of Resource Manager access tokens
+	ResourceManagerAudience string `json:"resourceManagerAudience"`
+	// AuthorityHost replaces the named cloud's Microsoft Entra ID host
+	AuthorityHost string `json:"authorityHost"`
+}
+
+// Configuration returns the cloud configuration the options select
+func (o CloudOptions) Configuration() (cloud.Configuration, error) {
+	name := o.Name
+	if name == "" {
+		name = CloudPublic
+	}
+	named, ok := clouds[strings.ToLower(name)]
+	if !ok {
+		return cloud.Configuration{}, fmt.Errorf("unknown Azure cloud %q: use %s, %s or %s, or set a custom Resource Manager endpoint",
+			o.Name, CloudPublic, CloudChina, CloudGovernment)
+	}
+
+	// Copy the shared named configuration before changing it
+	config := cloud.Configuration{
+		ActiveDirectoryAuthorityHost: named.ActiveDirectoryAuthorityHost,
+		Services:                     maps.Clone(named.Services),
+	}
+	if config.Services == nil {
+		config.Services = make(map[cloud.ServiceName]cloud.ServiceConfiguration)
+	}
+
+	if o.AuthorityHost != "" {
+		if err := validateEndpoint("authority host", o.AuthorityHost); err != nil {
+			return cloud.Configuration{}, err
+		}
+		config.ActiveDirectoryAuthorityHost = o.AuthorityHost
+	}
+
+	resourceManager := config.Services[cloud.ResourceManager]
+	if o.ResourceManagerEndpoint != "" {
+		if err := validateEndpoint("Resource Manager endpoint", o.ResourceManagerEndpoint); err != nil {
+			return cloud.Configuration{}, err
+		}
+		resourceManager.Endpoint = o.ResourceManagerEndpoint
+		resourceManager.Audience = o.ResourceManagerEndpoint
+	}
+	if o.ResourceManagerAudience != "" {
+		resourceManager.Audience = o.ResourceManagerAudience
+	}
+	if resourceManager.Endpoint == "" || resourceManager.Audience == "" {
+		return cloud.Configuration{}, fmt.Errorf("Azure cloud %q has no Resource Manager endpoint: set resourceManagerEndpoint", name)
+	}
+	config.Services[cloud.ResourceManager] = resourceManager
+	return config, nil
+}
+
+// validateEndpoint checks that endpoint is an absolute HTTPS URL
+func validateEndpoint(name, endpoint string) error {
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Scheme != "https" || u.Host == "" {
+		return fmt.Errorf("invalid %s %q: must be an https URL", name, endpoint)
+	}
+	return nil
+}
+
+// resourceManagerScope is the OAuth scope of config's Resource Manager endpoint
+func resourceManagerScope(config cloud.Configuration) string {
+	audience := config.Services[cloud.ResourceManager].Audience
+	if audience == "" {
+		audience = cloud.AzurePublic.Services[cloud.ResourceManager].Audience
+	}
+	return strings.TrimSuffix(audience, "/") + "/.default"
+}
+
+// isZeroCloud reports whether config is unset
+func isZeroCloud(config cloud.Configuration) bool {
+	return config.ActiveDirectoryAuthorityHost == "" && len(config.Services) == 0
+}
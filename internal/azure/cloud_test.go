@@ -0,0 +1,185 @@
+package azure_test
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/machinelearning/armmachinelearning"
+	"microsoft.com/aml-mcp/internal/azure"
+	"microsoft.com/aml-mcp/internal/fakearm"
+)
+
+func TestCloudOptionsConfiguration(t *testing.T) {
+	tests := []struct {
+		name          string
+		options       azure.CloudOptions
+		authorityHost string
+		endpoint      string
+		audience      string
+		errContains   string
+	}{
+		{
+			name:          "default",
+			authorityHost: "https://login.microsoftonline.com/",
+			endpoint:      "https://management.azure.com",
+			audience:      "https://management.core.windows.net/",
+		},
+		{
+			name:          "china",
+			options:       azure.CloudOptions{Name: "azurechina"},
+			authorityHost: "https://login.chinacloudapi.cn/",
+			endpoint:      "https://management.chinacloudapi.cn",
+			audience:      "https://management.core.chinacloudapi.cn",
+		},
+		{
+			name:          "government by Azure CLI name",
+			options:       azure.CloudOptions{Name: "AzureUSGovernment"},
+			authorityHost: "https://login.microsoftonline.us/",
+			endpoint:      "https://management.usgovcloudapi.net",
+			audience:      "https://management.core.usgovcloudapi.net",
+		},
+		{
+			name: "custom endpoint",
+			options: azure.CloudOptions{
+				ResourceManagerEndpoint: "https://management.local.azurestack.external",
+				AuthorityHost:           "https://login.local.azurestack.external/",
+			},
+			authorityHost: "https://login.local.azurestack.external/",
+			endpoint:      "https://management.local.azurestack.external",
+			audience:      "https://management.local.azurestack.external",
+		},
+		{
+			name: "custom audience",
+			options: azure.CloudOptions{
+				ResourceManagerEndpoint: "https://arm.example.com",
+				ResourceManagerAudience: "https://management.example.com/",
+			},
+			authorityHost: "https://login.microsoftonline.com/",
+			endpoint:      "https://arm.example.com",
+			audience:      "https://management.example.com/",
+		},
+		{
+			name:        "unknown cloud",
+			options:     azure.CloudOptions{Name: "AzureMoon"},
+			errContains: `unknown Azure cloud "AzureMoon"`,
+		},
+		{
+			name:        "endpoint without https",
+			options:     azure.CloudOptions{ResourceManagerEndpoint: "http://arm.example.com"},
+			errContains: "invalid Resource Manager endpoint",
+		},
+		{
+			name:        "relative authority host",
+			options:     azure.CloudOptions{AuthorityHost: "login.example.com"},
+			errContains: "invalid authority host",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, err := tt.options.Configuration()
+			if tt.errContains != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errContains) {
+					t.Errorf("Configuration() error = %v, want one containing %q", err, tt.errContains)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Configuration() error = %v", err)
+			}
+			if config.ActiveDirectoryAuthorityHost != tt.authorityHost {
+				t.Errorf("authority host = %q, want %q", config.ActiveDirectoryAuthorityHost, tt.authorityHost)
+			}
+			resourceManager := config.Services[cloud.ResourceManager]
+			if resourceManager.Endpoint != tt.endpoint || resourceManager.Audience != tt.audience {
+				t.Errorf("Resource Manager = %+v, want endpoint %q and audience %q", resourceManager, tt.endpoint, tt.audience)
+			}
+		})
+	}
+
+	// Overriding a named cloud must not change the shared configuration
+	if _, err := (azure.CloudOptions{ResourceManagerEndpoint: "https://arm.example.com"}).Configuration(); err != nil {
+		t.Fatalf("Configuration() error = %v", err)
+	}
+	if endpoint := cloud.AzurePublic.Services[cloud.ResourceManager].Endpoint; endpoint != "https://management.azure.com" {
+		t.Errorf("public cloud endpoint changed to %q", endpoint)
+	}
+}
+
+// tokenRecorder is a credential that records the token requests it serves
+type tokenRecorder struct {
+	azcore.TokenCredential
+
+	mu       sync.Mutex
+	requests []policy.TokenRequestOptions
+}
+
+func (r *tokenRecorder) GetToken(ctx context.Context, options policy.TokenRequestOptions) (azcore.AccessToken, error) {
+	r.mu.Lock()
+	r.requests = append(r.requests, options)
+	r.mu.Unlock()
+	return r.TokenCredential.GetToken(ctx, options)
+}
+
+// scopes returns the scopes of every recorded token request
+func (r *tokenRecorder) scopes() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var scopes []string
+	for _, request := range r.requests {
+		scopes = append(scopes, request.Scopes...)
+	}
+	return scopes
+}
+
+func TestClientCacheCloud(t *testing.T) {
+	fake := fakearm.New()
+	defer fake.Close()
+	fake.AddWorkspace("sub-1", "rg-1", armmachinelearning.Workspace{Name: to.Ptr("ws-1")})
+
+	// The cloud option takes precedence over the cloud of the client options
+	clientOptions := fake.ClientOptions()
+	clientOptions.Cloud = cloud.AzureChina
+	cloudConfig, err := azure.CloudOptions{
+		ResourceManagerEndpoint: fake.URL(),
+		ResourceManagerAudience: "https://management.example.com/",
+	}.Configuration()
+	if err != nil {
+		t.Fatalf("Configuration() error = %v", err)
+	}
+	credential := &tokenRecorder{TokenCredential: fake.Credential()}
+	cache := azure.NewClientCacheWithOptions(azure.ClientCacheOptions{
+		Credential:    credential,
+		ClientOptions: clientOptions,
+		Cloud:         cloudConfig,
+	})
+
+	clients, err := cache.Get(context.Background(), "sub-1")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if _, err := clients.WorkspacesClient.Get(context.Background(), "rg-1", "ws-1", nil); err != nil {
+		t.Fatalf("Get workspace through the custom endpoint failed: %v", err)
+	}
+	if _, ok := cache.Identity(context.Background()); !ok {
+		t.Fatal("Identity() = false after the credential was acquired")
+	}
+
+	scopes := credential.scopes()
+	if len(scopes) == 0 {
+		t.Fatal("no token was requested")
+	}
+	// The SDK appends /.default to the audience as is, so the scopes of
+	// ARM requests may have a double slash
+	for _, scope := range scopes {
+		if !strings.HasPrefix(scope, "https://management.example.com/") || !strings.HasSuffix(scope, "/.default") {
+			t.Errorf("token requested for scope %q, want the custom audience's", scope)
+		}
+	}
+}
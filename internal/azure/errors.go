@@ -0,0 +1,81 @@
+package azure
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+)
+
+// ResponseErrorDetails are the parts of a failed ARM response needed to explain the failure
+type ResponseErrorDetails struct {
+	// StatusCode is the HTTP status of the response
+	StatusCode int
+	// Code is the ARM error code, e.g. AuthorizationFailed
+	Code string
+	// Message is the message ARM returned with the error code
+	Message string
+	// RequestID is the x-ms-request-id of the failed response
+	RequestID string
+	// RetryAfter is how long ARM asked the caller to wait, if it said
+	RetryAfter time.Duration
+}
+
+// armErrorBody is the error format returned by ARM
+type armErrorBody struct {
+	Error *struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// ParseResponseError extracts the details of the azcore.ResponseError in err's chain
+func ParseResponseError(err error) (details ResponseErrorDetails, ok bool) {
+	var respErr *azcore.ResponseError
+	if !errors.As(err, &respErr) {
+		return ResponseErrorDetails{}, false
+	}
+
+	details = ResponseErrorDetails{StatusCode: respErr.StatusCode, Code: respErr.ErrorCode}
+	resp := respErr.RawResponse
+	if resp == nil {
+		return details, true
+	}
+
+	details.RequestID = resp.Header.Get(headerRequestID)
+	details.RetryAfter = RetryAfter(resp.Header)
+	if payload, err := runtime.Payload(resp); err == nil {
+		var body armErrorBody
+		if json.Unmarshal(payload, &body) == nil && body.Error != nil {
+			details.Message = body.Error.Message
+			if details.Code == "" {
+				details.Code = body.Error.Code
+			}
+		}
+	}
+	if details.Message == "" {
+		details.Message = http.StatusText(details.StatusCode)
+	}
+	return details, true
+}
+
+// RetryAfter returns the delay a response's Retry-After header asks for, or zero
+func RetryAfter(header http.Header) time.Duration {
+	value := header.Get("Retry-After")
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		if wait := time.Until(at); wait > 0 {
+			return wait
+		}
+	}
+	return 0
+}
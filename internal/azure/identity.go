@@ -0,0 +1,132 @@
+package azure
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+)
+
+// Identity is who the server acts as, taken from the claims of its ARM access token
+type Identity struct {
+	ObjectID string `json:"objectId,omitempty"`
+	TenantID string `json:"tenantId,omitempty"`
+	// Name is the user principal name, empty for service principals and managed identities
+	Name  string `json:"name,omitempty"`
+	AppID string `json:"appId,omitempty"`
+}
+
+// Identity returns the identity of the cached credential, without starting credential discovery
+func (c *ClientCache) Identity(ctx context.Context) (Identity, bool) {
+	c.credMu.Lock()
+	cred := c.credential
+	c.credMu.Unlock()
+	if cred == nil {
+		return Identity{}, false
+	}
+
+	token, err := cred.GetToken(ctx, policy.TokenRequestOptions{Scopes: []string{c.scope()}})
+	if err != nil {
+		return Identity{}, false
+	}
+	identity, err := ParseTokenIdentity(token.Token)
+	if err != nil {
+		return Identity{}, false
+	}
+	return identity, true
+}
+
+// TokenStatus describes the cached credential and a Resource Manager token it issued
+type TokenStatus struct {
+	Identity
+	// Mode is the auth mode the credential was selected by, or AuthCustom
+	Mode string `json:"mode"`
+	// ExpiresOn is when the token expires. The credential renews it before then.
+	ExpiresOn time.Time `json:"expiresOn"`
+	// AuthorityHost and ResourceManagerEndpoint locate the cloud signed in to
+	AuthorityHost           string `json:"authorityHost"`
+	ResourceManagerEndpoint string `json:"resourceManagerEndpoint"`
+}
+
+// TokenStatus gets a Resource Manager token for subscriptionID's tenant and describes it
+func (c *ClientCache) TokenStatus(ctx context.Context, subscriptionID string) (TokenStatus, error) {
+	cred, err := c.getCredential(ctx)
+	if err != nil {
+		return TokenStatus{}, err
+	}
+	c.credMu.Lock()
+	mode := c.credentialMode
+	c.credMu.Unlock()
+
+	token, err := cred.GetToken(ctx, policy.TokenRequestOptions{
+		Scopes:   []string{c.scope()},
+		TenantID: c.options.Auth.tenantFor(subscriptionID),
+	})
+	if err != nil {
+		return TokenStatus{}, err
+	}
+	identity, err := ParseTokenIdentity(token.Token)
+	if err != nil {
+		return TokenStatus{}, err
+	}
+
+	cloudConfig := c.cloud()
+	return TokenStatus{
+		Identity:                identity,
+		Mode:                    mode,
+		ExpiresOn:               token.ExpiresOn,
+		AuthorityHost:           cloudConfig.ActiveDirectoryAuthorityHost,
+		ResourceManagerEndpoint: cloudConfig.Services[cloud.ResourceManager].Endpoint,
+	}, nil
+}
+
+// scope is the OAuth scope of the Resource Manager endpoint the cache's clients use
+func (c *ClientCache) scope() string {
+	return resourceManagerScope(c.cloud())
+}
+
+// ParseTokenIdentity reads the identity claims of a JWT access token without verifying it
+func ParseTokenIdentity(token string) (Identity, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return Identity{}, errors.New("access token is not a JWT")
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return Identity{}, errors.New("access token payload is not base64url")
+	}
+
+	var claims struct {
+		ObjectID          string `json:"oid"`
+		TenantID          string `json:"tid"`
+		UPN               string `json:"upn"`
+		PreferredUsername string `json:"preferred_username"`
+		UniqueName        string `json:"unique_name"`
+		AppID             string `json:"appid"`
+		AuthorizedParty   string `json:"azp"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return Identity{}, errors.New("access token payload is not JSON")
+	}
+
+	return Identity{
+		ObjectID: claims.ObjectID,
+		TenantID: claims.TenantID,
+		Name:     firstNonEmpty(claims.UPN, claims.PreferredUsername, claims.UniqueName),
+		AppID:    firstNonEmpty(claims.AppID, claims.AuthorizedParty),
+	}, nil
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
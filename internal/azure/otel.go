@@ -0,0 +1,58 @@
+package azure
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName is the instrumentation scope of ARM request spans
+const tracerName = "microsoft.com/aml-mcp/internal/azure"
+
+// otelPolicy records every attempt of an ARM request as a client span
+type otelPolicy struct {
+	tracer trace.Tracer
+}
+
+func newOTelPolicy(provider trace.TracerProvider) otelPolicy {
+	return otelPolicy{tracer: provider.Tracer(tracerName)}
+}
+
+func (p otelPolicy) Do(req *policy.Request) (*http.Response, error) {
+	raw := req.Raw()
+	ctx, span := p.tracer.Start(raw.Context(), raw.Method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("http.request.method", raw.Method),
+			attribute.String("url.full", raw.URL.String()),
+			attribute.String("server.address", raw.URL.Hostname()),
+		),
+	)
+	defer span.End()
+
+	// ARM passes the trace context on to the resource providers it calls
+	if span.SpanContext().IsValid() {
+		propagation.TraceContext{}.Inject(ctx, propagation.HeaderCarrier(raw.Header))
+	}
+
+	resp, err := req.Next()
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return resp, err
+	}
+	span.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
+	if requestID := resp.Header.Get(headerRequestID); requestID != "" {
+		span.SetAttributes(attribute.String("az.service_request_id", requestID))
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		span.SetAttributes(attribute.String("error.type", strconv.Itoa(resp.StatusCode)))
+		span.SetStatus(codes.Error, resp.Status)
+	}
+	return resp, err
+}
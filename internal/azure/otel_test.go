@@ -0,0 +1,90 @@
+package azure_test
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/machinelearning/armmachinelearning"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"microsoft.com/aml-mcp/internal/azure"
+	"microsoft.com/aml-mcp/internal/fakearm"
+)
+
+func TestOTelPolicy(t *testing.T) {
+	fake := fakearm.New()
+	t.Cleanup(fake.Close)
+	fake.AddWorkspace("sub-1", "rg-1", armmachinelearning.Workspace{Name: to.Ptr("ws-1")})
+	fake.AddFailure(fakearm.Failure{Status: http.StatusServiceUnavailable, Code: "ServiceUnavailable", Times: 1})
+
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	cache := azure.NewClientCacheWithOptions(azure.ClientCacheOptions{
+		Credential:     fake.Credential(),
+		ClientOptions:  fake.ClientOptions(),
+		RequestPolicy:  azure.RequestPolicy{MaxRetries: 1, RetryDelay: time.Millisecond},
+		TracerProvider: provider,
+	})
+	clients, err := cache.Get(context.Background(), "sub-1")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+
+	ctx, parent := provider.Tracer("test").Start(context.Background(), "tool call")
+	_, err = clients.WorkspacesClient.Get(ctx, "rg-1", "ws-1", nil)
+	parent.End()
+	if err != nil {
+		t.Fatalf("Get workspace error = %v", err)
+	}
+
+	// Each attempt is a span of its own under the caller's span
+	var attempts []tracetest.SpanStub
+	for _, span := range exporter.GetSpans() {
+		if span.Parent.SpanID() == parent.SpanContext().SpanID() {
+			attempts = append(attempts, span)
+		}
+	}
+	if len(attempts) != 2 {
+		t.Fatalf("recorded %d request spans, want one per attempt", len(attempts))
+	}
+	for i, wantStatus := range []int64{http.StatusServiceUnavailable, http.StatusOK} {
+		span := attempts[i]
+		attrs := attributeMap(span.Attributes)
+		if span.Name != http.MethodGet || span.SpanKind != trace.SpanKindClient {
+			t.Errorf("span %d = %s (%v), want a GET client span", i, span.Name, span.SpanKind)
+		}
+		if got := attrs["http.response.status_code"].AsInt64(); got != wantStatus {
+			t.Errorf("span %d status code = %d, want %d", i, got, wantStatus)
+		}
+		if url := attrs["url.full"].AsString(); !strings.Contains(url, "/workspaces/ws-1") {
+			t.Errorf("span %d url.full = %q, want the workspace URL", i, url)
+		}
+		if wantError := wantStatus >= 400; (span.Status.Code == codes.Error) != wantError {
+			t.Errorf("span %d status = %v, want error %v", i, span.Status, wantError)
+		}
+	}
+	if requestID := attributeMap(attempts[1].Attributes)["az.service_request_id"].AsString(); !strings.HasPrefix(requestID, "fake-request-") {
+		t.Errorf("az.service_request_id = %q, want the ARM request ID", requestID)
+	}
+
+	// The trace context travels with the request
+	requests := fake.Requests()
+	if traceParent := requests[len(requests)-1].TraceParent; !strings.Contains(traceParent, parent.SpanContext().TraceID().String()) {
+		t.Errorf("traceparent = %q, want the caller's trace", traceParent)
+	}
+}
+
+func attributeMap(attrs []attribute.KeyValue) map[attribute.Key]attribute.Value {
+	m := make(map[attribute.Key]attribute.Value, len(attrs))
+	for _, attr := range attrs {
+		m[attr.Key] = attr.Value
+	}
+	return m
+}
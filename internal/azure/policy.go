@@ -0,0 +1,124 @@
+package azure
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+)
+
+// RequestPolicy tunes how ARM requests are retried, timed out and rate limited
+type RequestPolicy struct {
+	// MaxRetries is how many times a failed request is retried; -1 disables retries
+	MaxRetries int32
+	// RetryDelay is the initial backoff between retries
+	RetryDelay time.Duration
+	// MaxRetryDelay caps the backoff
+	MaxRetryDelay time.Duration
+	// TryTimeout bounds each attempt of a request. Zero means no limit.
+	TryTimeout time.Duration
+	// IgnoreRetryAfter makes retries use the backoff even when ARM sends Retry-After
+	IgnoreRetryAfter bool
+
+	// RequestsPerSecond limits the requests sent for each subscription; zero means no limit
+	RequestsPerSecond float64
+	// Burst is how many requests may be sent at once before the limit applies
+	Burst int
+}
+
+// apply sets the retry settings of p on options
+func (p RequestPolicy) apply(options *arm.ClientOptions) {
+	if p.MaxRetries != 0 {
+		options.Retry.MaxRetries = p.MaxRetries
+	}
+	if p.RetryDelay > 0 {
+		options.Retry.RetryDelay = p.RetryDelay
+	}
+	if p.MaxRetryDelay > 0 {
+		options.Retry.MaxRetryDelay = p.MaxRetryDelay
+	}
+	if p.TryTimeout > 0 {
+		options.Retry.TryTimeout = p.TryTimeout
+	}
+	if p.IgnoreRetryAfter {
+		options.PerRetryPolicies = append(options.PerRetryPolicies, ignoreRetryAfterPolicy{})
+	}
+}
+
+// newLimiter returns the rate limiter for one subscription, or nil
+func (p RequestPolicy) newLimiter() *tokenBucket {
+	if p.RequestsPerSecond <= 0 {
+		return nil
+	}
+	burst := float64(p.Burst)
+	if burst <= 0 {
+		burst = max(p.RequestsPerSecond, 1)
+	}
+	return &tokenBucket{rate: p.RequestsPerSecond, burst: burst, tokens: burst, last: time.Now()}
+}
+
+// ignoreRetryAfterPolicy hides Retry-After from the retry policy
+type ignoreRetryAfterPolicy struct{}
+
+func (ignoreRetryAfterPolicy) Do(req *policy.Request) (*http.Response, error) {
+	resp, err := req.Next()
+	if resp != nil {
+		resp.Header.Del("Retry-After")
+		resp.Header.Del("retry-after-ms")
+		resp.Header.Del("x-ms-retry-after-ms")
+	}
+	return resp, err
+}
+
+// tokenBucket is a token bucket rate limiter. It is safe for concurrent use.
+type tokenBucket struct {
+	rate  float64
+	burst float64
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+// wait blocks until a token is available or ctx is done
+func (b *tokenBucket) wait(ctx context.Context) error {
+	b.mu.Lock()
+	now := time.Now()
+	b.tokens = min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+	// Take the token now, going into debt if need be
+	b.tokens--
+	delay := time.Duration(-b.tokens / b.rate * float64(time.Second))
+	b.mu.Unlock()
+
+	if delay <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		// Give the token back for the next caller
+		b.mu.Lock()
+		b.tokens++
+		b.mu.Unlock()
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// rateLimitPolicy holds every request attempt until its token bucket allows it
+type rateLimitPolicy struct {
+	bucket *tokenBucket
+}
+
+func (p rateLimitPolicy) Do(req *policy.Request) (*http.Response, error) {
+	if err := p.bucket.wait(req.Raw().Context()); err != nil {
+		return nil, err
+	}
+	return req.Next()
+}
@@ -0,0 +1,128 @@
+package azure_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/machinelearning/armmachinelearning"
+	"microsoft.com/aml-mcp/internal/azure"
+	"microsoft.com/aml-mcp/internal/fakearm"
+)
+
+// newPolicyClients returns the clients for sub-1 of a fake ARM server seeded
+// with one workspace, built with policy
+func newPolicyClients(t *testing.T, policy azure.RequestPolicy) (*fakearm.Server, *azure.ClientSet) {
+	t.Helper()
+
+	fake := fakearm.New()
+	t.Cleanup(fake.Close)
+	fake.AddWorkspace("sub-1", "rg-1", armmachinelearning.Workspace{Name: to.Ptr("ws-1")})
+
+	cache := azure.NewClientCacheWithOptions(azure.ClientCacheOptions{
+		Credential:    fake.Credential(),
+		ClientOptions: fake.ClientOptions(),
+		RequestPolicy: policy,
+	})
+	clients, err := cache.Get(context.Background(), "sub-1")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	return fake, clients
+}
+
+func TestRequestPolicyRetries(t *testing.T) {
+	fake, clients := newPolicyClients(t, azure.RequestPolicy{MaxRetries: 2, RetryDelay: time.Millisecond})
+	fake.AddFailure(fakearm.Failure{Status: http.StatusServiceUnavailable, Code: "ServiceUnavailable", Times: 2})
+
+	if _, err := clients.WorkspacesClient.Get(context.Background(), "rg-1", "ws-1", nil); err != nil {
+		t.Fatalf("Get workspace error = %v, want success on the third attempt", err)
+	}
+	if got := len(fake.Requests()); got != 3 {
+		t.Errorf("sent %d requests, want 3", got)
+	}
+}
+
+func TestRequestPolicyRetryAfter(t *testing.T) {
+	throttled := fakearm.Failure{
+		Status: http.StatusTooManyRequests,
+		Code:   "TooManyRequests",
+		Header: http.Header{"Retry-After": []string{"30"}},
+		Times:  1,
+	}
+
+	// A Retry-After beyond the maximum delay ends retrying
+	fake, clients := newPolicyClients(t, azure.RequestPolicy{MaxRetries: 2, RetryDelay: time.Millisecond, MaxRetryDelay: time.Second})
+	fake.AddFailure(throttled)
+	if _, err := clients.WorkspacesClient.Get(context.Background(), "rg-1", "ws-1", nil); err == nil {
+		t.Error("Get workspace succeeded, want the 429 when Retry-After exceeds the maximum delay")
+	}
+
+	// Ignoring Retry-After retries with the configured backoff instead
+	fake, clients = newPolicyClients(t, azure.RequestPolicy{MaxRetries: 2, RetryDelay: time.Millisecond, MaxRetryDelay: time.Second, IgnoreRetryAfter: true})
+	fake.AddFailure(throttled)
+	start := time.Now()
+	if _, err := clients.WorkspacesClient.Get(context.Background(), "rg-1", "ws-1", nil); err != nil {
+		t.Fatalf("Get workspace error = %v, want a retry ignoring Retry-After", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Get workspace took %v, want Retry-After ignored", elapsed)
+	}
+}
+
+func TestRequestPolicyRateLimit(t *testing.T) {
+	_, clients := newPolicyClients(t, azure.RequestPolicy{RequestsPerSecond: 20, Burst: 2})
+	ctx := context.Background()
+
+	// Two requests fit the burst; the next three wait 50ms each
+	start := time.Now()
+	for range 5 {
+		if _, err := clients.WorkspacesClient.Get(ctx, "rg-1", "ws-1", nil); err != nil {
+			t.Fatalf("Get workspace error = %v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 140*time.Millisecond {
+		t.Errorf("5 requests took %v, want at least 150ms at 20 requests per second with a burst of 2", elapsed)
+	}
+
+	// A request waiting for the limiter gives up with its context
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Millisecond)
+	defer cancel()
+	for range 3 {
+		if _, err := clients.WorkspacesClient.Get(ctx, "rg-1", "ws-1", nil); err != nil {
+			return
+		}
+	}
+	t.Error("requests beyond the rate limit did not stop at the context deadline")
+}
+
+func TestRequestPolicyRateLimitIgnoresCase(t *testing.T) {
+	fake := fakearm.New()
+	t.Cleanup(fake.Close)
+	fake.AddWorkspace("sub-1", "rg-1", armmachinelearning.Workspace{Name: to.Ptr("ws-1")})
+
+	cache := azure.NewClientCacheWithOptions(azure.ClientCacheOptions{
+		Credential:    fake.Credential(),
+		ClientOptions: fake.ClientOptions(),
+		RequestPolicy: azure.RequestPolicy{RequestsPerSecond: 20, Burst: 1},
+	})
+	ctx := context.Background()
+
+	// The second request waits for the first one's token, whichever casing of
+	// the subscription ID it is made with
+	start := time.Now()
+	for _, subscriptionID := range []string{"sub-1", "SUB-1"} {
+		clients, err := cache.Get(ctx, subscriptionID)
+		if err != nil {
+			t.Fatalf("Get(%q) error = %v", subscriptionID, err)
+		}
+		if _, err := clients.WorkspacesClient.Get(ctx, "rg-1", "ws-1", nil); err != nil {
+			t.Fatalf("Get workspace with %q error = %v", subscriptionID, err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 40*time.Millisecond {
+		t.Errorf("2 requests took %v, want at least 50ms with one limiter at 20 requests per second and a burst of 1", elapsed)
+	}
+}
@@ -0,0 +1,76 @@
+package azure
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"net/http"
+	"slices"
+	"sync"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+)
+
+// ARM request headers used to correlate a tool call with Azure activity logs
+const (
+	headerCorrelationID = "x-ms-correlation-request-id"
+	headerRequestID     = "x-ms-request-id"
+)
+
+// RequestTrace collects the ARM requests made with a context under one correlation ID
+type RequestTrace struct {
+	CorrelationID string
+
+	mu         sync.Mutex
+	requestIDs []string
+}
+
+type requestTraceKey struct{}
+
+// WithRequestTrace returns a context whose ARM requests are recorded in the returned trace
+func WithRequestTrace(ctx context.Context) (context.Context, *RequestTrace) {
+	trace := &RequestTrace{CorrelationID: newUUID()}
+	return context.WithValue(ctx, requestTraceKey{}, trace), trace
+}
+
+// RequestIDs returns the ARM request IDs of the responses received so far
+func (t *RequestTrace) RequestIDs() []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return slices.Clone(t.requestIDs)
+}
+
+func (t *RequestTrace) add(requestID string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if !slices.Contains(t.requestIDs, requestID) {
+		t.requestIDs = append(t.requestIDs, requestID)
+	}
+}
+
+// requestTracePolicy tags requests made with a traced context and records their request IDs
+type requestTracePolicy struct{}
+
+func (requestTracePolicy) Do(req *policy.Request) (*http.Response, error) {
+	trace, _ := req.Raw().Context().Value(requestTraceKey{}).(*RequestTrace)
+	if trace == nil {
+		return req.Next()
+	}
+	req.Raw().Header.Set(headerCorrelationID, trace.CorrelationID)
+	resp, err := req.Next()
+	if resp != nil {
+		if requestID := resp.Header.Get(headerRequestID); requestID != "" {
+			trace.add(requestID)
+		}
+	}
+	return resp, err
+}
+
+// newUUID returns a random version 4 UUID
+func newUUID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
@@ -0,0 +1,76 @@
+// Package cache keeps tool results for slow-changing Azure data for a while
+package cache
+
+import (
+	"slices"
+	"sync"
+	"time"
+)
+
+type entry struct {
+	scope     []string
+	value     any
+	expiresAt time.Time
+}
+
+// Cache is an in-memory store of scoped values that expire after a per-entry TTL
+type Cache struct {
+	mu      sync.Mutex
+	entries map[string]entry
+}
+
+// New creates an empty Cache
+func New() *Cache {
+	return &Cache{entries: make(map[string]entry)}
+}
+
+// Get returns the value stored under key, if it has not expired
+func (c *Cache) Get(key string) (any, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if time.Now().After(e.expiresAt) {
+		delete(c.entries, key)
+		return nil, false
+	}
+	return e.value, true
+}
+
+// Set stores value under key for ttl
+func (c *Cache) Set(key string, scope []string, value any, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	for k, e := range c.entries {
+		if now.After(e.expiresAt) {
+			delete(c.entries, k)
+		}
+	}
+	c.entries[key] = entry{scope: slices.Clone(scope), value: value, expiresAt: now.Add(ttl)}
+}
+
+// Invalidate drops every entry whose scope contains or is contained in scope and returns how many
+func (c *Cache) Invalidate(scope ...string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	dropped := 0
+	for k, e := range c.entries {
+		if overlaps(e.scope, scope) {
+			delete(c.entries, k)
+			dropped++
+		}
+	}
+	return dropped
+}
+
+// overlaps reports whether one of a and b is a prefix of the other
+func overlaps(a, b []string) bool {
+	n := min(len(a), len(b))
+	return slices.Equal(a[:n], b[:n])
+}
@@ -0,0 +1,54 @@
+package cache_test
+
+import (
+	"testing"
+	"time"
+
+	"microsoft.com/aml-mcp/internal/cache"
+)
+
+func TestCacheExpiry(t *testing.T) {
+	c := cache.New()
+	c.Set("short", nil, 1, 10*time.Millisecond)
+	c.Set("long", nil, 2, time.Hour)
+
+	if value, ok := c.Get("short"); !ok || value != 1 {
+		t.Errorf("Get(short) = %v, %v, want 1, true", value, ok)
+	}
+	time.Sleep(20 * time.Millisecond)
+	if _, ok := c.Get("short"); ok {
+		t.Error("Get(short) found an expired entry")
+	}
+	if value, ok := c.Get("long"); !ok || value != 2 {
+		t.Errorf("Get(long) = %v, %v, want 2, true", value, ok)
+	}
+	if _, ok := c.Get("missing"); ok {
+		t.Error("Get(missing) found an entry")
+	}
+}
+
+func TestCacheInvalidate(t *testing.T) {
+	c := cache.New()
+	c.Set("subscription", []string{"sub-1"}, 1, time.Hour)
+	c.Set("workspace", []string{"sub-1", "rg-1", "ws-1"}, 2, time.Hour)
+	c.Set("other workspace", []string{"sub-1", "rg-1", "ws-2"}, 3, time.Hour)
+	c.Set("other subscription", []string{"sub-2"}, 4, time.Hour)
+
+	if dropped := c.Invalidate("sub-1", "rg-1", "ws-1"); dropped != 2 {
+		t.Errorf("Invalidate(ws-1) dropped %d entries, want the workspace and its subscription", dropped)
+	}
+	for key, want := range map[string]bool{
+		"subscription":       false,
+		"workspace":          false,
+		"other workspace":    true,
+		"other subscription": true,
+	} {
+		if _, ok := c.Get(key); ok != want {
+			t.Errorf("Get(%s) found = %v, want %v", key, ok, want)
+		}
+	}
+
+	if dropped := c.Invalidate(); dropped != 2 {
+		t.Errorf("Invalidate() dropped %d entries, want the remaining 2", dropped)
+	}
+}
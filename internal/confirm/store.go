@@ -0,0 +1,81 @@
+// Package confirm issues single-use tokens that confirm mutating tool calls
+package confirm
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"sync"
+	"time"
+)
+
+// DefaultTTL is how long an issued token stays valid
+const DefaultTTL = 10 * time.Minute
+
+var (
+	// ErrUnknownToken is returned for tokens that are not currently valid
+	ErrUnknownToken = errors.New("confirm_token is unknown, expired or already used")
+	// ErrTokenMismatch is returned for a token issued for a different request
+	ErrTokenMismatch = errors.New("confirm_token was issued for a different request")
+)
+
+type entry struct {
+	fingerprint string
+	expiresAt   time.Time
+}
+
+// Store holds issued tokens until they are redeemed or expire
+type Store struct {
+	mu     sync.Mutex
+	ttl    time.Duration
+	tokens map[string]entry
+}
+
+// NewStore creates an empty Store whose tokens expire after ttl
+func NewStore(ttl time.Duration) *Store {
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+	return &Store{ttl: ttl, tokens: make(map[string]entry)}
+}
+
+// Issue returns a new token bound to fingerprint and the time it expires
+func (s *Store) Issue(fingerprint string) (string, time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	for token, e := range s.tokens {
+		if now.After(e.expiresAt) {
+			delete(s.tokens, token)
+		}
+	}
+
+	token := newToken()
+	expiresAt := now.Add(s.ttl)
+	s.tokens[token] = entry{fingerprint: fingerprint, expiresAt: expiresAt}
+	return token, expiresAt
+}
+
+// Redeem consumes token if it was issued for fingerprint and has not expired
+func (s *Store) Redeem(token, fingerprint string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.tokens[token]
+	if !ok || time.Now().After(e.expiresAt) {
+		delete(s.tokens, token)
+		return ErrUnknownToken
+	}
+	if e.fingerprint != fingerprint {
+		return ErrTokenMismatch
+	}
+	delete(s.tokens, token)
+	return nil
+}
+
+func newToken() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return "confirm-" + hex.EncodeToString(b)
+}
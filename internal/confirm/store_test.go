@@ -0,0 +1,46 @@
+package confirm_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"microsoft.com/aml-mcp/internal/confirm"
+)
+
+func TestStore(t *testing.T) {
+	s := confirm.NewStore(0)
+
+	token, expiresAt := s.Issue("PUT /workspaces/ws-1")
+	if !strings.HasPrefix(token, "confirm-") {
+		t.Errorf("token = %q, want confirm- prefix", token)
+	}
+	if until := time.Until(expiresAt); until <= 0 || until > confirm.DefaultTTL {
+		t.Errorf("expiresAt is %v away, want within %v", until, confirm.DefaultTTL)
+	}
+
+	if err := s.Redeem(token, "PUT /workspaces/ws-2"); !errors.Is(err, confirm.ErrTokenMismatch) {
+		t.Errorf("Redeem() with another fingerprint error = %v, want %v", err, confirm.ErrTokenMismatch)
+	}
+	if err := s.Redeem(token, "PUT /workspaces/ws-1"); err != nil {
+		t.Fatalf("Redeem() error = %v", err)
+	}
+	if err := s.Redeem(token, "PUT /workspaces/ws-1"); !errors.Is(err, confirm.ErrUnknownToken) {
+		t.Errorf("second Redeem() error = %v, want %v", err, confirm.ErrUnknownToken)
+	}
+	if err := s.Redeem("confirm-unknown", "PUT /workspaces/ws-1"); !errors.Is(err, confirm.ErrUnknownToken) {
+		t.Errorf("Redeem() of an unknown token error = %v, want %v", err, confirm.ErrUnknownToken)
+	}
+}
+
+func TestStoreExpiry(t *testing.T) {
+	s := confirm.NewStore(time.Millisecond)
+
+	token, _ := s.Issue("POST /computes/ci-1/stop")
+	time.Sleep(5 * time.Millisecond)
+
+	if err := s.Redeem(token, "POST /computes/ci-1/stop"); !errors.Is(err, confirm.ErrUnknownToken) {
+		t.Errorf("Redeem() of an expired token error = %v, want %v", err, confirm.ErrUnknownToken)
+	}
+}
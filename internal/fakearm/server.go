@@ -0,0 +1,842 @@
+// Package fakearm provides an in-memory fake of the Azure Resource Manager endpoints the tools use
+package fakearm
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/authorization/armauthorization/v2"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/machinelearning/armmachinelearning"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armsubscriptions"
+	"microsoft.com/aml-mcp/internal/azure"
+)
+
+const (
+	providerNamespace      = "Microsoft.MachineLearningServices"
+	authorizationNamespace = "Microsoft.Authorization"
+	operationsPath         = "/fakearm/operations/"
+)
+
+// Identity claims of the access token issued by Credential
+const (
+	UserName = "test-user@example.com"
+	ObjectID = "00000000-0000-0000-0000-0000000000aa"
+	TenantID = "00000000-0000-0000-0000-0000000000bb"
+)
+
+// Request records a single request received by the fake server
+type Request struct {
+	Method        string
+	Path          string
+	Body          []byte
+	CorrelationID string
+	// TraceParent is the W3C trace context the request was sent in
+	TraceParent string
+}
+
+// Failure makes requests matching Method and PathContains fail with an ARM error
+type Failure struct {
+	// Method is the HTTP method to match; empty matches any
+	Method string
+	// PathContains is a substring of the request path to match; empty matches any
+	PathContains string
+	Status       int
+	Code         string
+	Message      string
+	// Header is added to the error response, e.g. Retry-After
+	Header http.Header
+	// Times is how many matching requests fail; zero fails all of them
+	Times int
+}
+
+// operation tracks a long-running operation started through the fake server
+type operation struct {
+	pendingPolls int
+	apply        func()
+}
+
+// Server is an httptest-backed fake Azure Resource Manager
+type Server struct {
+	srv *httptest.Server
+
+	mu               sync.Mutex
+	subscriptions    map[string]*armsubscriptions.Subscription
+	workspaces       map[string]*armmachinelearning.Workspace
+	computes         map[string]*armmachinelearning.ComputeResource
+	quotas           map[string][]*armmachinelearning.ResourceQuota
+	usages           map[string][]*armmachinelearning.Usage
+	vmSizes          map[string][]*armmachinelearning.VirtualMachineSize
+	privateEndpoints map[string][]*armmachinelearning.PrivateEndpointConnection
+	connections      map[string][]*armmachinelearning.WorkspaceConnection
+	features         map[string][]*armmachinelearning.AmlUserFeature
+	permissions      map[string][]*armauthorization.Permission
+	roleAssignments  map[string]*armauthorization.RoleAssignment
+	roleDefinitions  map[string]*armauthorization.RoleDefinition
+	operations       map[string]*operation
+	nextOperation    int
+	nextRequestID    atomic.Int64
+	pendingPolls     int
+	pageSize         int
+	failures         []*Failure
+	requests         []Request
+}
+
+// New starts a fake ARM server. Callers must Close it when done.
+func New() *Server {
+	s := &Server{
+		subscriptions:    make(map[string]*armsubscriptions.Subscription),
+		workspaces:       make(map[string]*armmachinelearning.Workspace),
+		computes:         make(map[string]*armmachinelearning.ComputeResource),
+		quotas:           make(map[string][]*armmachinelearning.ResourceQuota),
+		usages:           make(map[string][]*armmachinelearning.Usage),
+		vmSizes:          make(map[string][]*armmachinelearning.VirtualMachineSize),
+		privateEndpoints: make(map[string][]*armmachinelearning.PrivateEndpointConnection),
+		connections:      make(map[string][]*armmachinelearning.WorkspaceConnection),
+		features:         make(map[string][]*armmachinelearning.AmlUserFeature),
+		permissions:      make(map[string][]*armauthorization.Permission),
+		roleAssignments:  make(map[string]*armauthorization.RoleAssignment),
+		roleDefinitions:  make(map[string]*armauthorization.RoleDefinition),
+		operations:       make(map[string]*operation),
+	}
+	// Bearer token authentication is only allowed over TLS
+	s.srv = httptest.NewTLSServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// Close shuts down the fake server
+func (s *Server) Close() {
+	s.srv.Close()
+}
+
+// URL returns the base URL of the fake server
+func (s *Server) URL() string {
+	return s.srv.URL
+}
+
+// ClientOptions returns ARM client options that route every request to the fake server
+func (s *Server) ClientOptions() *arm.ClientOptions {
+	return &arm.ClientOptions{
+		ClientOptions: policy.ClientOptions{
+			Cloud: cloud.Configuration{
+				ActiveDirectoryAuthorityHost: s.srv.URL,
+				Services: map[cloud.ServiceName]cloud.ServiceConfiguration{
+					cloud.ResourceManager: {
+						Audience: s.srv.URL,
+						Endpoint: s.srv.URL,
+					},
+				},
+			},
+			Transport: s.srv.Client(),
+			Retry: policy.RetryOptions{
+				MaxRetries: -1,
+			},
+		},
+		DisableRPRegistration: true,
+	}
+}
+
+// Credential returns a credential that always issues a static token
+func (s *Server) Credential() azcore.TokenCredential {
+	return staticCredential{}
+}
+
+// ClientCache returns a client cache wired to the fake server
+func (s *Server) ClientCache() *azure.ClientCache {
+	return azure.NewClientCacheWithOptions(azure.ClientCacheOptions{
+		Credential:    s.Credential(),
+		ClientOptions: s.ClientOptions(),
+	})
+}
+
+// SetPendingPolls sets how many polls a long-running operation reports InProgress for
+func (s *Server) SetPendingPolls(n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pendingPolls = n
+}
+
+// SetPageSize makes lists return at most n items per page; zero returns one page
+func (s *Server) SetPageSize(n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pageSize = n
+}
+
+// AddFailure makes matching requests fail, checking failures in the order they were added
+func (s *Server) AddFailure(f Failure) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures = append(s.failures, &f)
+}
+
+// Requests returns every request received so far, excluding operation polls
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+// WorkspaceID builds the ARM resource ID of a workspace
+func WorkspaceID(subscriptionID, resourceGroup, workspace string) string {
+	return fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/%s/workspaces/%s",
+		subscriptionID, resourceGroup, providerNamespace, workspace)
+}
+
+// ComputeID builds the ARM resource ID of a compute resource
+func ComputeID(subscriptionID, resourceGroup, workspace, compute string) string {
+	return WorkspaceID(subscriptionID, resourceGroup, workspace) + "/computes/" + compute
+}
+
+// AddSubscription seeds a subscription the credential can see
+func (s *Server) AddSubscription(subscriptionID, displayName string, state armsubscriptions.SubscriptionState) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if state == "" {
+		state = armsubscriptions.SubscriptionStateEnabled
+	}
+	id := "/subscriptions/" + subscriptionID
+	s.subscriptions[key(id)] = &armsubscriptions.Subscription{
+		ID:             to.Ptr(id),
+		SubscriptionID: to.Ptr(subscriptionID),
+		DisplayName:    to.Ptr(displayName),
+		State:          to.Ptr(state),
+		TenantID:       to.Ptr(TenantID),
+	}
+}
+
+// AddWorkspace seeds a workspace. Name must be set; ID and Type are filled in.
+func (s *Server) AddWorkspace(subscriptionID, resourceGroup string, workspace armmachinelearning.Workspace) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := WorkspaceID(subscriptionID, resourceGroup, *workspace.Name)
+	workspace.ID = to.Ptr(id)
+	workspace.Type = to.Ptr(providerNamespace + "/workspaces")
+	s.workspaces[key(id)] = &workspace
+}
+
+// AddCompute seeds a compute resource in a workspace. Name must be set; ID and Type are filled in.
+func (s *Server) AddCompute(subscriptionID, resourceGroup, workspace string, compute armmachinelearning.ComputeResource) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := ComputeID(subscriptionID, resourceGroup, workspace, *compute.Name)
+	compute.ID = to.Ptr(id)
+	compute.Type = to.Ptr(providerNamespace + "/workspaces/computes")
+	s.computes[key(id)] = &compute
+}
+
+// SetQuotas seeds the quotas reported for a subscription and location
+func (s *Server) SetQuotas(subscriptionID, location string, quotas ...*armmachinelearning.ResourceQuota) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.quotas[locationKey(subscriptionID, location)] = quotas
+}
+
+// SetUsages seeds the usages reported for a subscription and location
+func (s *Server) SetUsages(subscriptionID, location string, usages ...*armmachinelearning.Usage) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.usages[locationKey(subscriptionID, location)] = usages
+}
+
+// SetVMSizes seeds the VM sizes reported for a subscription and location
+func (s *Server) SetVMSizes(subscriptionID, location string, sizes ...*armmachinelearning.VirtualMachineSize) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.vmSizes[locationKey(subscriptionID, location)] = sizes
+}
+
+// AddPrivateEndpointConnection seeds a private endpoint connection on a workspace
+func (s *Server) AddPrivateEndpointConnection(subscriptionID, resourceGroup, workspace string, conn *armmachinelearning.PrivateEndpointConnection) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	id := key(WorkspaceID(subscriptionID, resourceGroup, workspace))
+	s.privateEndpoints[id] = append(s.privateEndpoints[id], conn)
+}
+
+// AddWorkspaceConnection seeds a connection on a workspace
+func (s *Server) AddWorkspaceConnection(subscriptionID, resourceGroup, workspace string, conn *armmachinelearning.WorkspaceConnection) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	id := key(WorkspaceID(subscriptionID, resourceGroup, workspace))
+	s.connections[id] = append(s.connections[id], conn)
+}
+
+// AddWorkspaceFeature seeds a feature on a workspace
+func (s *Server) AddWorkspaceFeature(subscriptionID, resourceGroup, workspace string, feature *armmachinelearning.AmlUserFeature) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	id := key(WorkspaceID(subscriptionID, resourceGroup, workspace))
+	s.features[id] = append(s.features[id], feature)
+}
+
+// SetPermissions seeds the caller's effective permissions on scope and the resources below it
+func (s *Server) SetPermissions(scope string, permissions ...*armauthorization.Permission) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.permissions[key(scope)] = permissions
+}
+
+// RoleDefinitionID builds the ARM resource ID of a role definition
+func RoleDefinitionID(subscriptionID, name string) string {
+	return fmt.Sprintf("/subscriptions/%s/providers/%s/roleDefinitions/%s", subscriptionID, authorizationNamespace, name)
+}
+
+// RoleAssignmentID builds the ARM resource ID of a role assignment on scope
+func RoleAssignmentID(scope, name string) string {
+	return fmt.Sprintf("%s/providers/%s/roleAssignments/%s", scope, authorizationNamespace, name)
+}
+
+// AddRoleDefinition seeds a role definition with its Name and Properties.RoleName set
+func (s *Server) AddRoleDefinition(definition armauthorization.RoleDefinition) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	definition.Type = to.Ptr(authorizationNamespace + "/roleDefinitions")
+	s.roleDefinitions[key(*definition.Name)] = &definition
+}
+
+// AddRoleAssignment seeds a role assignment with its Name and Properties set on scope
+func (s *Server) AddRoleAssignment(scope string, assignment armauthorization.RoleAssignment) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.putRoleAssignment(scope, &assignment)
+}
+
+// RoleAssignment returns a copy of a seeded or created role assignment
+func (s *Server) RoleAssignment(scope, name string) (armauthorization.RoleAssignment, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	assignment, ok := s.roleAssignments[key(RoleAssignmentID(scope, name))]
+	if !ok {
+		return armauthorization.RoleAssignment{}, false
+	}
+	return *assignment, true
+}
+
+func (s *Server) putRoleAssignment(scope string, assignment *armauthorization.RoleAssignment) {
+	id := RoleAssignmentID(scope, *assignment.Name)
+	assignment.ID = to.Ptr(id)
+	assignment.Type = to.Ptr(authorizationNamespace + "/roleAssignments")
+	assignment.Properties.Scope = to.Ptr(scope)
+	s.roleAssignments[key(id)] = assignment
+}
+
+// Workspace returns a copy of a seeded or created workspace
+func (s *Server) Workspace(subscriptionID, resourceGroup, workspace string) (armmachinelearning.Workspace, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ws, ok := s.workspaces[key(WorkspaceID(subscriptionID, resourceGroup, workspace))]
+	if !ok {
+		return armmachinelearning.Workspace{}, false
+	}
+	return *ws, true
+}
+
+// ComputeState returns the state of a compute instance, or "" if it is not a compute instance
+func (s *Server) ComputeState(subscriptionID, resourceGroup, workspace, compute string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c, ok := s.computes[key(ComputeID(subscriptionID, resourceGroup, workspace, compute))]
+	if !ok {
+		return ""
+	}
+	instance, ok := c.Properties.(*armmachinelearning.ComputeInstance)
+	if !ok || instance.Properties == nil || instance.Properties.State == nil {
+		return ""
+	}
+	return string(*instance.Properties.State)
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("x-ms-request-id", fmt.Sprintf("fake-request-%d", s.nextRequestID.Add(1)))
+
+	if strings.HasPrefix(r.URL.Path, operationsPath) {
+		s.serveOperation(w, strings.TrimPrefix(r.URL.Path, operationsPath))
+		return
+	}
+
+	body, _ := io.ReadAll(r.Body)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.requests = append(s.requests, Request{
+		Method:        r.Method,
+		Path:          r.URL.Path,
+		Body:          body,
+		CorrelationID: r.Header.Get("x-ms-correlation-request-id"),
+		TraceParent:   r.Header.Get("traceparent"),
+	})
+
+	if s.fail(w, r) {
+		return
+	}
+
+	segments := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if i := authorizationProvider(segments); i >= 0 {
+		scope := slices.DeleteFunc(slices.Clone(segments[:i]), func(segment string) bool { return segment == "" })
+		s.serveAuthorization(w, r, body, "/"+strings.Join(scope, "/"), segments[i+2:])
+		return
+	}
+	if len(segments) == 1 && strings.EqualFold(segments[0], "subscriptions") && r.Method == http.MethodGet {
+		value, nextLink := page(s, r, s.subscriptions, "")
+		writeJSON(w, http.StatusOK, armsubscriptions.SubscriptionListResult{Value: value, NextLink: nextLink})
+		return
+	}
+	if len(segments) < 5 || !strings.EqualFold(segments[0], "subscriptions") {
+		writeError(w, http.StatusNotFound, "InvalidResourceType", "Unsupported path "+r.URL.Path)
+		return
+	}
+	subscriptionID := segments[1]
+
+	// Subscription-scoped provider endpoints
+	if strings.EqualFold(segments[2], "providers") && strings.EqualFold(segments[3], providerNamespace) {
+		switch {
+		case len(segments) == 5 && strings.EqualFold(segments[4], "workspaces") && r.Method == http.MethodGet:
+			s.listWorkspaces(w, r, subscriptionID)
+			return
+		case len(segments) == 7 && strings.EqualFold(segments[4], "locations") && r.Method == http.MethodGet:
+			s.serveLocation(w, subscriptionID, segments[5], segments[6])
+			return
+		}
+	}
+
+	// Workspace-scoped endpoints
+	if len(segments) >= 8 && strings.EqualFold(segments[2], "resourceGroups") &&
+		strings.EqualFold(segments[4], "providers") && strings.EqualFold(segments[5], providerNamespace) &&
+		strings.EqualFold(segments[6], "workspaces") {
+		s.serveWorkspace(w, r, body, subscriptionID, segments[3], segments[7], segments[8:])
+		return
+	}
+
+	writeError(w, http.StatusNotFound, "InvalidResourceType", "Unsupported path "+r.URL.Path)
+}
+
+// authorizationProvider returns the index of the Microsoft.Authorization provider segments, or -1
+func authorizationProvider(segments []string) int {
+	for i := 0; i+1 < len(segments); i++ {
+		if strings.EqualFold(segments[i], "providers") && strings.EqualFold(segments[i+1], authorizationNamespace) {
+			return i
+		}
+	}
+	return -1
+}
+
+// serveAuthorization serves the Microsoft.Authorization resources of scope
+func (s *Server) serveAuthorization(w http.ResponseWriter, r *http.Request, body []byte, scope string, rest []string) {
+	switch {
+	case len(rest) == 1 && strings.EqualFold(rest[0], "permissions") && r.Method == http.MethodGet:
+		writeJSON(w, http.StatusOK, armauthorization.PermissionGetResult{Value: s.permissionsOf(scope)})
+	case len(rest) == 1 && strings.EqualFold(rest[0], "roleAssignments") && r.Method == http.MethodGet:
+		s.listRoleAssignments(w, scope)
+	case len(rest) == 2 && strings.EqualFold(rest[0], "roleAssignments"):
+		s.serveRoleAssignment(w, r, body, scope, rest[1])
+	case len(rest) == 1 && strings.EqualFold(rest[0], "roleDefinitions") && r.Method == http.MethodGet:
+		s.listRoleDefinitions(w, r, scope)
+	case len(rest) == 2 && strings.EqualFold(rest[0], "roleDefinitions") && r.Method == http.MethodGet:
+		definition, ok := s.roleDefinitions[key(rest[1])]
+		if !ok {
+			writeError(w, http.StatusNotFound, "RoleDefinitionDoesNotExist", fmt.Sprintf("The specified role definition with ID '%s' does not exist.", rest[1]))
+			return
+		}
+		writeJSON(w, http.StatusOK, withRoleDefinitionID(definition, scope))
+	default:
+		writeError(w, http.StatusNotFound, "InvalidResourceType", "Unsupported path "+r.URL.Path)
+	}
+}
+
+// listRoleAssignments lists the role assignments that apply to scope or resources below it
+func (s *Server) listRoleAssignments(w http.ResponseWriter, scope string) {
+	k := key(scope)
+	ids := make([]string, 0, len(s.roleAssignments))
+	for id, assignment := range s.roleAssignments {
+		if within(k, key(*assignment.Properties.Scope)) || within(key(*assignment.Properties.Scope), k) {
+			ids = append(ids, id)
+		}
+	}
+	slices.Sort(ids)
+	value := make([]*armauthorization.RoleAssignment, 0, len(ids))
+	for _, id := range ids {
+		value = append(value, s.roleAssignments[id])
+	}
+	writeJSON(w, http.StatusOK, armauthorization.RoleAssignmentListResult{Value: value})
+}
+
+func (s *Server) serveRoleAssignment(w http.ResponseWriter, r *http.Request, body []byte, scope, name string) {
+	id := key(RoleAssignmentID(scope, name))
+	existing, exists := s.roleAssignments[id]
+
+	switch r.Method {
+	case http.MethodGet:
+		if !exists {
+			writeError(w, http.StatusNotFound, "RoleAssignmentNotFound", fmt.Sprintf("The role assignment '%s' is not found.", name))
+			return
+		}
+		writeJSON(w, http.StatusOK, existing)
+	case http.MethodPut:
+		var params armauthorization.RoleAssignmentCreateParameters
+		if err := json.Unmarshal(body, &params); err != nil || params.Properties == nil ||
+			params.Properties.PrincipalID == nil || params.Properties.RoleDefinitionID == nil {
+			writeError(w, http.StatusBadRequest, "InvalidRequestContent", "The request must set principalId and roleDefinitionId")
+			return
+		}
+		for otherID, other := range s.roleAssignments {
+			if otherID != id && strings.EqualFold(*other.Properties.Scope, scope) &&
+				strings.EqualFold(*other.Properties.PrincipalID, *params.Properties.PrincipalID) &&
+				strings.EqualFold(lastSegment(*other.Properties.RoleDefinitionID), lastSegment(*params.Properties.RoleDefinitionID)) {
+				writeError(w, http.StatusConflict, "RoleAssignmentExists", "The role assignment already exists.")
+				return
+			}
+		}
+		params.Properties.CreatedOn = to.Ptr(time.Now().UTC())
+		assignment := &armauthorization.RoleAssignment{Name: to.Ptr(name), Properties: params.Properties}
+		s.putRoleAssignment(scope, assignment)
+		status := http.StatusCreated
+		if exists {
+			status = http.StatusOK
+		}
+		writeJSON(w, status, assignment)
+	case http.MethodDelete:
+		if !exists {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		delete(s.roleAssignments, id)
+		writeJSON(w, http.StatusOK, existing)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "MethodNotAllowed", r.Method+" is not supported")
+	}
+}
+
+// listRoleDefinitions lists the seeded role definitions, filtered by role name
+func (s *Server) listRoleDefinitions(w http.ResponseWriter, r *http.Request, scope string) {
+	var roleName string
+	if filter := r.URL.Query().Get("$filter"); filter != "" {
+		name, ok := strings.CutPrefix(filter, "roleName eq '")
+		if !ok || !strings.HasSuffix(name, "'") {
+			writeError(w, http.StatusBadRequest, "InvalidFilter", "Unsupported filter "+filter)
+			return
+		}
+		roleName = strings.TrimSuffix(name, "'")
+	}
+
+	var value []*armauthorization.RoleDefinition
+	for _, name := range slices.Sorted(maps.Keys(s.roleDefinitions)) {
+		definition := s.roleDefinitions[name]
+		if roleName == "" || strings.EqualFold(*definition.Properties.RoleName, roleName) {
+			value = append(value, withRoleDefinitionID(definition, scope))
+		}
+	}
+	writeJSON(w, http.StatusOK, armauthorization.RoleDefinitionListResult{Value: value})
+}
+
+// withRoleDefinitionID returns a copy of definition with its ID in the subscription of scope
+func withRoleDefinitionID(definition *armauthorization.RoleDefinition, scope string) *armauthorization.RoleDefinition {
+	copied := *definition
+	subscriptionID := strings.Split(strings.TrimPrefix(key(scope), "/subscriptions/"), "/")[0]
+	copied.ID = to.Ptr(RoleDefinitionID(subscriptionID, *definition.Name))
+	return &copied
+}
+
+// within reports whether the scope key child is k or below it
+func within(k, child string) bool {
+	return child == k || strings.HasPrefix(child, k+"/")
+}
+
+func lastSegment(id string) string {
+	return id[strings.LastIndex(id, "/")+1:]
+}
+
+// permissionsOf returns the permissions set on scope or its closest parent, or allows everything
+func (s *Server) permissionsOf(scope string) []*armauthorization.Permission {
+	k, best := key(scope), ""
+	for configured := range s.permissions {
+		if within(configured, k) && len(configured) > len(best) {
+			best = configured
+		}
+	}
+	if best == "" {
+		return []*armauthorization.Permission{{Actions: []*string{to.Ptr("*")}}}
+	}
+	return s.permissions[best]
+}
+
+func (s *Server) listWorkspaces(w http.ResponseWriter, r *http.Request, subscriptionID string) {
+	value, nextLink := page(s, r, s.workspaces, key("/subscriptions/"+subscriptionID+"/"))
+	writeJSON(w, http.StatusOK, armmachinelearning.WorkspaceListResult{Value: value, NextLink: nextLink})
+}
+
+// page returns the page of resources under prefix that r asks for and the next link
+func page[T any](s *Server, r *http.Request, resources map[string]*T, prefix string) ([]*T, *string) {
+	ids := make([]string, 0, len(resources))
+	for id := range resources {
+		if strings.HasPrefix(id, prefix) {
+			ids = append(ids, id)
+		}
+	}
+	slices.Sort(ids)
+
+	start, _ := strconv.Atoi(r.URL.Query().Get("$skip"))
+	start = min(max(start, 0), len(ids))
+	end := len(ids)
+	if s.pageSize > 0 {
+		end = min(start+s.pageSize, len(ids))
+	}
+
+	value := make([]*T, 0, end-start)
+	for _, id := range ids[start:end] {
+		value = append(value, resources[id])
+	}
+	if end == len(ids) {
+		return value, nil
+	}
+	next := *r.URL
+	query := next.Query()
+	query.Set("$skip", strconv.Itoa(end))
+	next.RawQuery = query.Encode()
+	next.Scheme, next.Host = "https", r.Host
+	return value, to.Ptr(next.String())
+}
+
+func (s *Server) serveLocation(w http.ResponseWriter, subscriptionID, location, resource string) {
+	lk := locationKey(subscriptionID, location)
+	switch strings.ToLower(resource) {
+	case "quotas":
+		writeJSON(w, http.StatusOK, armmachinelearning.ListWorkspaceQuotas{Value: s.quotas[lk]})
+	case "usages":
+		writeJSON(w, http.StatusOK, armmachinelearning.ListUsagesResult{Value: s.usages[lk]})
+	case "vmsizes":
+		writeJSON(w, http.StatusOK, armmachinelearning.VirtualMachineSizeListResult{Value: s.vmSizes[lk]})
+	default:
+		writeError(w, http.StatusNotFound, "InvalidResourceType", "Unsupported location resource "+resource)
+	}
+}
+
+func (s *Server) serveWorkspace(w http.ResponseWriter, r *http.Request, body []byte, subscriptionID, resourceGroup, workspace string, rest []string) {
+	workspaceID := WorkspaceID(subscriptionID, resourceGroup, workspace)
+	ws, exists := s.workspaces[key(workspaceID)]
+
+	if len(rest) == 0 {
+		switch r.Method {
+		case http.MethodGet:
+			if !exists {
+				writeNotFound(w, "workspaces", workspace)
+				return
+			}
+			writeJSON(w, http.StatusOK, ws)
+		case http.MethodPut:
+			s.createWorkspace(w, body, workspaceID, workspace)
+		default:
+			writeError(w, http.StatusMethodNotAllowed, "MethodNotAllowed", r.Method+" is not supported")
+		}
+		return
+	}
+
+	if !exists {
+		writeNotFound(w, "workspaces", workspace)
+		return
+	}
+
+	switch {
+	case len(rest) == 1 && r.Method == http.MethodGet:
+		s.listWorkspaceChildren(w, r, workspaceID, rest[0])
+	case len(rest) == 2 && strings.EqualFold(rest[0], "computes") && r.Method == http.MethodGet:
+		compute, ok := s.computes[key(workspaceID+"/computes/"+rest[1])]
+		if !ok {
+			writeNotFound(w, "computes", rest[1])
+			return
+		}
+		writeJSON(w, http.StatusOK, compute)
+	case len(rest) == 3 && strings.EqualFold(rest[0], "computes") && r.Method == http.MethodPost:
+		s.computeAction(w, workspaceID, rest[1], rest[2])
+	default:
+		writeError(w, http.StatusNotFound, "InvalidResourceType", "Unsupported path "+r.URL.Path)
+	}
+}
+
+func (s *Server) listWorkspaceChildren(w http.ResponseWriter, r *http.Request, workspaceID, child string) {
+	id := key(workspaceID)
+	switch strings.ToLower(child) {
+	case "computes":
+		value, nextLink := page(s, r, s.computes, id+"/computes/")
+		writeJSON(w, http.StatusOK, armmachinelearning.PaginatedComputeResourcesList{Value: value, NextLink: nextLink})
+	case "privateendpointconnections":
+		writeJSON(w, http.StatusOK, armmachinelearning.PrivateEndpointConnectionListResult{Value: s.privateEndpoints[id]})
+	case "connections":
+		writeJSON(w, http.StatusOK, armmachinelearning.PaginatedWorkspaceConnectionsList{Value: s.connections[id]})
+	case "features":
+		writeJSON(w, http.StatusOK, armmachinelearning.ListAmlUserFeatureResult{Value: s.features[id]})
+	default:
+		writeError(w, http.StatusNotFound, "InvalidResourceType", "Unsupported workspace resource "+child)
+	}
+}
+
+func (s *Server) createWorkspace(w http.ResponseWriter, body []byte, workspaceID, name string) {
+	var ws armmachinelearning.Workspace
+	if err := json.Unmarshal(body, &ws); err != nil {
+		writeError(w, http.StatusBadRequest, "InvalidRequestContent", err.Error())
+		return
+	}
+	if ws.Properties == nil {
+		ws.Properties = &armmachinelearning.WorkspaceProperties{}
+	}
+	ws.ID = to.Ptr(workspaceID)
+	ws.Name = to.Ptr(name)
+	ws.Type = to.Ptr(providerNamespace + "/workspaces")
+	ws.Properties.ProvisioningState = to.Ptr(armmachinelearning.ProvisioningStateCreating)
+	s.workspaces[key(workspaceID)] = &ws
+
+	s.startOperation(w, func() {
+		ws.Properties.ProvisioningState = to.Ptr(armmachinelearning.ProvisioningStateSucceeded)
+	})
+}
+
+func (s *Server) computeAction(w http.ResponseWriter, workspaceID, name, action string) {
+	compute, ok := s.computes[key(workspaceID+"/computes/"+name)]
+	if !ok {
+		writeNotFound(w, "computes", name)
+		return
+	}
+
+	var state armmachinelearning.ComputeInstanceState
+	switch strings.ToLower(action) {
+	case "start":
+		state = armmachinelearning.ComputeInstanceStateRunning
+	case "stop":
+		state = armmachinelearning.ComputeInstanceStateStopped
+	default:
+		writeError(w, http.StatusNotFound, "InvalidResourceType", "Unsupported compute action "+action)
+		return
+	}
+
+	s.startOperation(w, func() {
+		if instance, ok := compute.Properties.(*armmachinelearning.ComputeInstance); ok {
+			if instance.Properties == nil {
+				instance.Properties = &armmachinelearning.ComputeInstanceProperties{}
+			}
+			instance.Properties.State = to.Ptr(state)
+		}
+	})
+}
+
+// startOperation registers a long-running operation and writes its 202 response; callers hold s.mu
+func (s *Server) startOperation(w http.ResponseWriter, apply func()) {
+	s.nextOperation++
+	id := fmt.Sprintf("op-%d", s.nextOperation)
+	s.operations[id] = &operation{pendingPolls: s.pendingPolls, apply: apply}
+
+	w.Header().Set("Azure-AsyncOperation", s.srv.URL+operationsPath+id)
+	writeJSON(w, http.StatusAccepted, nil)
+}
+
+func (s *Server) serveOperation(w http.ResponseWriter, id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	op, ok := s.operations[id]
+	if !ok {
+		writeNotFound(w, "operations", id)
+		return
+	}
+
+	if op.pendingPolls > 0 {
+		op.pendingPolls--
+		writeJSON(w, http.StatusOK, map[string]string{"name": id, "status": "InProgress"})
+		return
+	}
+
+	if op.apply != nil {
+		op.apply()
+		op.apply = nil
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"name": id, "status": "Succeeded"})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if v != nil {
+		_ = json.NewEncoder(w).Encode(v)
+	}
+}
+
+// fail writes the error of the first failure matching r, if any
+func (s *Server) fail(w http.ResponseWriter, r *http.Request) bool {
+	for i, f := range s.failures {
+		if (f.Method != "" && f.Method != r.Method) || !strings.Contains(r.URL.Path, f.PathContains) {
+			continue
+		}
+		if f.Times > 0 {
+			if f.Times--; f.Times == 0 {
+				s.failures = append(s.failures[:i], s.failures[i+1:]...)
+			}
+		}
+		for name, values := range f.Header {
+			for _, value := range values {
+				w.Header().Add(name, value)
+			}
+		}
+		writeError(w, f.Status, f.Code, f.Message)
+		return true
+	}
+	return false
+}
+
+func writeError(w http.ResponseWriter, status int, code, message string) {
+	writeJSON(w, status, map[string]any{
+		"error": map[string]string{
+			"code":    code,
+			"message": message,
+		},
+	})
+}
+
+func writeNotFound(w http.ResponseWriter, resourceType, name string) {
+	writeError(w, http.StatusNotFound, "ResourceNotFound",
+		fmt.Sprintf("The Resource '%s/%s/%s' was not found.", providerNamespace, resourceType, name))
+}
+
+func key(id string) string {
+	return strings.ToLower(id)
+}
+
+func locationKey(subscriptionID, location string) string {
+	return strings.ToLower(subscriptionID + "/" + location)
+}
+
+// staticCredential issues a fixed, unsigned JWT with the UserName, ObjectID and TenantID claims
+type staticCredential struct{}
+
+func (staticCredential) GetToken(ctx context.Context, options policy.TokenRequestOptions) (azcore.AccessToken, error) {
+	return azcore.AccessToken{Token: token, ExpiresOn: time.Now().Add(time.Hour)}, nil
+}
+
+var token = func() string {
+	encode := func(v any) string {
+		data, _ := json.Marshal(v)
+		return base64.RawURLEncoding.EncodeToString(data)
+	}
+	header := encode(map[string]string{"alg": "none", "typ": "JWT"})
+	claims := encode(map[string]string{"oid": ObjectID, "tid": TenantID, "upn": UserName})
+	return header + "." + claims + ".fake-signature"
+}()
@@ -0,0 +1,149 @@
+package fakearm_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/machinelearning/armmachinelearning"
+	"microsoft.com/aml-mcp/internal/azure"
+	"microsoft.com/aml-mcp/internal/fakearm"
+)
+
+const (
+	testSubscriptionID = "00000000-0000-0000-0000-000000000001"
+	testResourceGroup  = "test-rg"
+)
+
+func newClients(t *testing.T, fake *fakearm.Server) *azure.ClientSet {
+	t.Helper()
+
+	clients, err := fake.ClientCache().Get(context.Background(), testSubscriptionID)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	return clients
+}
+
+func TestPaging(t *testing.T) {
+	fake := fakearm.New()
+	defer fake.Close()
+	fake.SetPageSize(2)
+	for _, name := range []string{"ws-c", "ws-a", "ws-b"} {
+		fake.AddWorkspace(testSubscriptionID, testResourceGroup, armmachinelearning.Workspace{Name: to.Ptr(name)})
+	}
+
+	pager := newClients(t, fake).WorkspacesClient.NewListBySubscriptionPager(nil)
+	var pages int
+	var names []string
+	for pager.More() {
+		page, err := pager.NextPage(context.Background())
+		if err != nil {
+			t.Fatalf("NextPage() error = %v", err)
+		}
+		pages++
+		for _, ws := range page.Value {
+			names = append(names, *ws.Name)
+		}
+	}
+
+	if pages != 2 {
+		t.Errorf("pages = %d, want 2", pages)
+	}
+	if got := strings.Join(names, ","); got != "ws-a,ws-b,ws-c" {
+		t.Errorf("names = %s, want ws-a,ws-b,ws-c", got)
+	}
+	if requests := fake.Requests(); len(requests) != 2 {
+		t.Errorf("requests = %d, want 2", len(requests))
+	}
+}
+
+func TestFailure(t *testing.T) {
+	fake := fakearm.New()
+	defer fake.Close()
+	fake.AddWorkspace(testSubscriptionID, testResourceGroup, armmachinelearning.Workspace{Name: to.Ptr("ws-1")})
+	fake.AddFailure(fakearm.Failure{
+		Method:       http.MethodGet,
+		PathContains: "/workspaces/ws-1",
+		Status:       http.StatusTooManyRequests,
+		Code:         "TooManyRequests",
+		Message:      "slow down",
+		Header:       http.Header{"Retry-After": {"5"}},
+		Times:        1,
+	})
+	clients := newClients(t, fake)
+
+	_, err := clients.WorkspacesClient.Get(context.Background(), testResourceGroup, "ws-1", nil)
+	var respErr *azcore.ResponseError
+	if !errors.As(err, &respErr) {
+		t.Fatalf("Get() error = %v, want a ResponseError", err)
+	}
+	if respErr.StatusCode != http.StatusTooManyRequests || respErr.ErrorCode != "TooManyRequests" {
+		t.Errorf("error = %d %s, want 429 TooManyRequests", respErr.StatusCode, respErr.ErrorCode)
+	}
+	if got := respErr.RawResponse.Header.Get("Retry-After"); got != "5" {
+		t.Errorf("Retry-After = %q, want 5", got)
+	}
+
+	if _, err := clients.WorkspacesClient.Get(context.Background(), testResourceGroup, "ws-1", nil); err != nil {
+		t.Errorf("Get() after the failure was used up error = %v", err)
+	}
+	if requests := fake.Requests(); len(requests) != 2 {
+		t.Errorf("requests = %d, want the failed request recorded too", len(requests))
+	}
+}
+
+func TestLongRunningOperation(t *testing.T) {
+	fake := fakearm.New()
+	defer fake.Close()
+	fake.SetPendingPolls(2)
+	fake.AddWorkspace(testSubscriptionID, testResourceGroup, armmachinelearning.Workspace{Name: to.Ptr("ws-1")})
+	fake.AddCompute(testSubscriptionID, testResourceGroup, "ws-1", armmachinelearning.ComputeResource{
+		Name: to.Ptr("ci-1"),
+		Properties: &armmachinelearning.ComputeInstance{
+			ComputeType: to.Ptr(armmachinelearning.ComputeTypeComputeInstance),
+			Properties: &armmachinelearning.ComputeInstanceProperties{
+				State: to.Ptr(armmachinelearning.ComputeInstanceStateStopped),
+			},
+		},
+	})
+
+	ctx := context.Background()
+	poller, err := newClients(t, fake).ComputeClient.BeginStart(ctx, testResourceGroup, "ws-1", "ci-1", nil)
+	if err != nil {
+		t.Fatalf("BeginStart() error = %v", err)
+	}
+	if state := fake.ComputeState(testSubscriptionID, testResourceGroup, "ws-1", "ci-1"); state != "Stopped" {
+		t.Errorf("state before polling = %s, want Stopped", state)
+	}
+
+	var polls int
+	for !poller.Done() {
+		if _, err := poller.Poll(ctx); err != nil {
+			t.Fatalf("Poll() error = %v", err)
+		}
+		polls++
+		if !poller.Done() {
+			if state := fake.ComputeState(testSubscriptionID, testResourceGroup, "ws-1", "ci-1"); state != "Stopped" {
+				t.Errorf("state while in progress = %s, want Stopped", state)
+			}
+		}
+	}
+	if _, err := poller.Result(ctx); err != nil {
+		t.Fatalf("Result() error = %v", err)
+	}
+
+	if polls != 3 {
+		t.Errorf("polls = %d, want 3", polls)
+	}
+	if state := fake.ComputeState(testSubscriptionID, testResourceGroup, "ws-1", "ci-1"); state != "Running" {
+		t.Errorf("state = %s, want Running", state)
+	}
+	if requests := fake.Requests(); len(requests) != 1 || requests[0].Method != http.MethodPost {
+		t.Errorf("requests = %+v, want only the start request", requests)
+	}
+}
@@ -0,0 +1,114 @@
+package helpers
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ResourceID is a parsed Azure Resource Manager resource ID
+type ResourceID struct {
+	SubscriptionID string
+	ResourceGroup  string
+	// Provider is the resource provider namespace, e.g. Microsoft.MachineLearningServices
+	Provider string
+	// Resources are the resource type and name pairs under the provider, outermost first
+	Resources []ResourceSegment
+}
+
+// ResourceSegment is one type/name pair of a resource ID
+type ResourceSegment struct {
+	Type string
+	Name string
+}
+
+// ParseResourceID parses an ARM resource ID
+func ParseResourceID(id string) (*ResourceID, error) {
+	trimmed := strings.Trim(strings.TrimSpace(id), "/")
+	if trimmed == "" {
+		return nil, fmt.Errorf("resource ID is empty")
+	}
+	parts := strings.Split(trimmed, "/")
+	for _, part := range parts {
+		if part == "" {
+			return nil, fmt.Errorf("resource ID %q has an empty segment", id)
+		}
+	}
+
+	if len(parts) < 2 || !strings.EqualFold(parts[0], "subscriptions") {
+		return nil, fmt.Errorf("resource ID %q does not start with /subscriptions/{id}", id)
+	}
+	parsed := &ResourceID{SubscriptionID: parts[1]}
+	parts = parts[2:]
+
+	if len(parts) >= 2 && strings.EqualFold(parts[0], "resourceGroups") {
+		parsed.ResourceGroup = parts[1]
+		parts = parts[2:]
+	}
+
+	if len(parts) == 0 {
+		return parsed, nil
+	}
+	if len(parts) < 4 || !strings.EqualFold(parts[0], "providers") {
+		return nil, fmt.Errorf("resource ID %q has no /providers/{namespace}/{type}/{name} section", id)
+	}
+	parsed.Provider = parts[1]
+	parts = parts[2:]
+
+	if len(parts)%2 != 0 {
+		return nil, fmt.Errorf("resource ID %q has a resource type without a name", id)
+	}
+	for i := 0; i < len(parts); i += 2 {
+		parsed.Resources = append(parsed.Resources, ResourceSegment{Type: parts[i], Name: parts[i+1]})
+	}
+	return parsed, nil
+}
+
+// ResourceType returns the full type of the resource the ID refers to
+func (r *ResourceID) ResourceType() string {
+	if r.Provider == "" {
+		return ""
+	}
+	types := []string{r.Provider}
+	for _, segment := range r.Resources {
+		types = append(types, segment.Type)
+	}
+	return strings.Join(types, "/")
+}
+
+// Name returns the name of the resource the ID refers to
+func (r *ResourceID) Name() string {
+	if len(r.Resources) == 0 {
+		return ""
+	}
+	return r.Resources[len(r.Resources)-1].Name
+}
+
+// NameOf returns the name of the resource or parent of the given full type, or ""
+func (r *ResourceID) NameOf(resourceType string) string {
+	typeParts := strings.Split(resourceType, "/")
+	if len(typeParts) < 2 || len(typeParts)-1 > len(r.Resources) || !strings.EqualFold(typeParts[0], r.Provider) {
+		return ""
+	}
+	for i, typePart := range typeParts[1:] {
+		if !strings.EqualFold(typePart, r.Resources[i].Type) {
+			return ""
+		}
+	}
+	return r.Resources[len(typeParts)-2].Name
+}
+
+// String formats the ID in its canonical form
+func (r *ResourceID) String() string {
+	var b strings.Builder
+	b.WriteString("/subscriptions/" + r.SubscriptionID)
+	if r.ResourceGroup != "" {
+		b.WriteString("/resourceGroups/" + r.ResourceGroup)
+	}
+	if r.Provider != "" {
+		b.WriteString("/providers/" + r.Provider)
+		for _, segment := range r.Resources {
+			b.WriteString("/" + segment.Type + "/" + segment.Name)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,146 @@
+package helpers_test
+
+import (
+	"reflect"
+	"testing"
+
+	"microsoft.com/aml-mcp/internal/helpers"
+)
+
+const (
+	workspaceID = "/subscriptions/sub-1/resourceGroups/rg-1/providers/Microsoft.MachineLearningServices/workspaces/ws-1"
+	computeID   = workspaceID + "/computes/ci-1"
+)
+
+func TestParseResourceID(t *testing.T) {
+	tests := []struct {
+		name     string
+		id       string
+		expected *helpers.ResourceID
+	}{
+		{
+			name:     "subscription",
+			id:       "/subscriptions/sub-1",
+			expected: &helpers.ResourceID{SubscriptionID: "sub-1"},
+		},
+		{
+			name:     "resource group",
+			id:       "/subscriptions/sub-1/resourceGroups/rg-1",
+			expected: &helpers.ResourceID{SubscriptionID: "sub-1", ResourceGroup: "rg-1"},
+		},
+		{
+			name: "workspace",
+			id:   workspaceID,
+			expected: &helpers.ResourceID{
+				SubscriptionID: "sub-1",
+				ResourceGroup:  "rg-1",
+				Provider:       "Microsoft.MachineLearningServices",
+				Resources:      []helpers.ResourceSegment{{Type: "workspaces", Name: "ws-1"}},
+			},
+		},
+		{
+			name: "nested online deployment",
+			id:   workspaceID + "/onlineEndpoints/ep-1/deployments/blue",
+			expected: &helpers.ResourceID{
+				SubscriptionID: "sub-1",
+				ResourceGroup:  "rg-1",
+				Provider:       "Microsoft.MachineLearningServices",
+				Resources: []helpers.ResourceSegment{
+					{Type: "workspaces", Name: "ws-1"},
+					{Type: "onlineEndpoints", Name: "ep-1"},
+					{Type: "deployments", Name: "blue"},
+				},
+			},
+		},
+		{
+			name: "lower-case keys and trailing slash",
+			id:   "subscriptions/sub-1/resourcegroups/rg-1/PROVIDERS/Microsoft.MachineLearningServices/workspaces/ws-1/",
+			expected: &helpers.ResourceID{
+				SubscriptionID: "sub-1",
+				ResourceGroup:  "rg-1",
+				Provider:       "Microsoft.MachineLearningServices",
+				Resources:      []helpers.ResourceSegment{{Type: "workspaces", Name: "ws-1"}},
+			},
+		},
+		{
+			name: "subscription-level provider resource",
+			id:   "/subscriptions/sub-1/providers/Microsoft.Authorization/roleDefinitions/role-1",
+			expected: &helpers.ResourceID{
+				SubscriptionID: "sub-1",
+				Provider:       "Microsoft.Authorization",
+				Resources:      []helpers.ResourceSegment{{Type: "roleDefinitions", Name: "role-1"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := helpers.ParseResourceID(tt.id)
+			if err != nil {
+				t.Fatalf("ParseResourceID() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("ParseResourceID() = %+v, want %+v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseResourceIDErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		id   string
+	}{
+		{"empty", ""},
+		{"no subscription", "/resourceGroups/rg-1"},
+		{"subscription without id", "/subscriptions"},
+		{"empty segment", "/subscriptions//resourceGroups/rg-1"},
+		{"missing providers", "/subscriptions/sub-1/resourceGroups/rg-1/workspaces/ws-1"},
+		{"provider without resource", "/subscriptions/sub-1/resourceGroups/rg-1/providers/Microsoft.MachineLearningServices"},
+		{"type without name", workspaceID + "/computes"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, err := helpers.ParseResourceID(tt.id); err == nil {
+				t.Errorf("ParseResourceID(%q) = %+v, want an error", tt.id, got)
+			}
+		})
+	}
+}
+
+func TestResourceIDAccessors(t *testing.T) {
+	id, err := helpers.ParseResourceID(computeID)
+	if err != nil {
+		t.Fatalf("ParseResourceID() error = %v", err)
+	}
+
+	if got := id.ResourceType(); got != "Microsoft.MachineLearningServices/workspaces/computes" {
+		t.Errorf("ResourceType() = %q", got)
+	}
+	if got := id.Name(); got != "ci-1" {
+		t.Errorf("Name() = %q, want ci-1", got)
+	}
+	if got := id.String(); got != computeID {
+		t.Errorf("String() = %q, want %q", got, computeID)
+	}
+
+	tests := []struct {
+		resourceType string
+		expected     string
+	}{
+		{"Microsoft.MachineLearningServices/workspaces", "ws-1"},
+		{"microsoft.machinelearningservices/Workspaces/Computes", "ci-1"},
+		{"Microsoft.MachineLearningServices/workspaces/onlineEndpoints", ""},
+		{"Microsoft.MachineLearningServices/workspaces/computes/nodes", ""},
+		{"Microsoft.Storage/storageAccounts", ""},
+		{"Microsoft.MachineLearningServices", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.resourceType, func(t *testing.T) {
+			if got := id.NameOf(tt.resourceType); got != tt.expected {
+				t.Errorf("NameOf(%q) = %q, want %q", tt.resourceType, got, tt.expected)
+			}
+		})
+	}
+}
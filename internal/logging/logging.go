@@ -0,0 +1,92 @@
+// Package logging sets up the server's structured logger
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+// Supported log formats
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// Options configure the logger. Every field is optional.
+type Options struct {
+	// Level is the minimum level logged: debug, info (default), warn or error
+	Level string `json:"level"`
+	// Format is text (default) or json
+	Format string `json:"format"`
+	// File is a path to append logs to instead of stderr
+	File string `json:"file"`
+}
+
+// New creates a logger from options and the io.Closer of its log file
+func New(options Options) (*slog.Logger, io.Closer, error) {
+	level, err := ParseLevel(options.Level)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var (
+		out    io.Writer = os.Stderr
+		closer io.Closer = nopCloser{}
+	)
+	if options.File != "" {
+		file, err := os.OpenFile(options.File, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to open log file: %v", err)
+		}
+		out, closer = file, file
+	}
+
+	handlerOptions := &slog.HandlerOptions{Level: level}
+	var handler slog.Handler
+	switch strings.ToLower(options.Format) {
+	case "", FormatText:
+		handler = slog.NewTextHandler(out, handlerOptions)
+	case FormatJSON:
+		handler = slog.NewJSONHandler(out, handlerOptions)
+	default:
+		closer.Close()
+		return nil, nil, fmt.Errorf("unsupported log format %q (expected %s or %s)", options.Format, FormatText, FormatJSON)
+	}
+	return slog.New(handler), closer, nil
+}
+
+// ParseLevel parses a level name, defaulting to info when name is empty
+func ParseLevel(name string) (slog.Level, error) {
+	if name == "" {
+		return slog.LevelInfo, nil
+	}
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(name)); err != nil {
+		return 0, fmt.Errorf("unsupported log level %q (expected debug, info, warn or error)", name)
+	}
+	return level, nil
+}
+
+// nopCloser is returned when logging to stderr, which must stay open
+type nopCloser struct{}
+
+func (nopCloser) Close() error { return nil }
+
+type loggerKey struct{}
+
+// WithLogger returns a context carrying logger
+func WithLogger(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, logger)
+}
+
+// FromContext returns the logger carried by ctx, or the default logger
+func FromContext(ctx context.Context) *slog.Logger {
+	if logger, ok := ctx.Value(loggerKey{}).(*slog.Logger); ok {
+		return logger
+	}
+	return slog.Default()
+}
@@ -0,0 +1,88 @@
+package logging_test
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"microsoft.com/aml-mcp/internal/logging"
+)
+
+func TestNewWritesToFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "server.log")
+
+	logger, closer, err := logging.New(logging.Options{Level: "warn", Format: "json", File: path})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	logger.Info("not logged")
+	logger.Warn("logged", "tool", "start_compute")
+	if err := closer.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read log file: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("log file has %d lines, want 1:\n%s", len(lines), data)
+	}
+	var entry map[string]any
+	if err := json.Unmarshal([]byte(lines[0]), &entry); err != nil {
+		t.Fatalf("log line is not JSON: %v", err)
+	}
+	if entry["msg"] != "logged" || entry["tool"] != "start_compute" || entry["level"] != "WARN" {
+		t.Errorf("log entry = %v", entry)
+	}
+}
+
+func TestNewErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		options logging.Options
+	}{
+		{"level", logging.Options{Level: "verbose"}},
+		{"format", logging.Options{Format: "xml"}},
+		{"file", logging.Options{File: filepath.Join(t.TempDir(), "missing", "server.log")}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := logging.New(tt.options); err == nil {
+				t.Errorf("New(%+v) succeeded, want an error", tt.options)
+			}
+		})
+	}
+}
+
+func TestParseLevel(t *testing.T) {
+	tests := map[string]slog.Level{
+		"":      slog.LevelInfo,
+		"debug": slog.LevelDebug,
+		"INFO":  slog.LevelInfo,
+		"warn":  slog.LevelWarn,
+		"error": slog.LevelError,
+	}
+	for name, want := range tests {
+		got, err := logging.ParseLevel(name)
+		if err != nil || got != want {
+			t.Errorf("ParseLevel(%q) = %v, %v, want %v", name, got, err, want)
+		}
+	}
+}
+
+func TestFromContext(t *testing.T) {
+	if logging.FromContext(context.Background()) != slog.Default() {
+		t.Error("FromContext() without a logger did not return the default logger")
+	}
+
+	logger := slog.Default().With("tool", "get_workspace")
+	if logging.FromContext(logging.WithLogger(context.Background(), logger)) != logger {
+		t.Error("FromContext() did not return the logger set with WithLogger")
+	}
+}
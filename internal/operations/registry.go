@@ -0,0 +1,144 @@
+// Package operations tracks long-running Azure operations started by tools
+package operations
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Status is the lifecycle state of a tracked operation
+type Status string
+
+const (
+	StatusInProgress Status = "InProgress"
+	StatusSucceeded  Status = "Succeeded"
+	StatusFailed     Status = "Failed"
+	StatusCanceled   Status = "Canceled"
+)
+
+// Terminal reports whether the operation has finished
+func (s Status) Terminal() bool {
+	return s == StatusSucceeded || s == StatusFailed || s == StatusCanceled
+}
+
+// Kinds of operation, named after the tool that starts them
+const (
+	KindCreateWorkspace = "create_workspace"
+	KindStartCompute    = "start_compute"
+	KindStopCompute     = "stop_compute"
+)
+
+// retention is how long finished operations are kept before being pruned
+const retention = time.Hour
+
+// Operation is a long-running Azure operation started by a tool
+type Operation struct {
+	ID             string
+	Kind           string
+	SubscriptionID string
+	ResourceGroup  string
+	Workspace      string
+	// Target is the name of the resource the operation acts on
+	Target string
+	// ResumeToken is the SDK poller resume token used to poll the operation
+	ResumeToken string
+	Status      Status
+	Error       string
+	StartedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// Registry is an in-process store of tracked operations
+type Registry struct {
+	mu         sync.Mutex
+	operations map[string]*Operation
+	now        func() time.Time
+}
+
+// NewRegistry creates an empty Registry
+func NewRegistry() *Registry {
+	return &Registry{
+		operations: make(map[string]*Operation),
+		now:        time.Now,
+	}
+}
+
+// Add stores a new operation, assigning its ID and timestamps, and returns the stored copy
+func (r *Registry) Add(op Operation) Operation {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.prune()
+
+	now := r.now()
+	op.ID = newID()
+	op.StartedAt = now
+	op.UpdatedAt = now
+	if op.Status == "" {
+		op.Status = StatusInProgress
+	}
+	r.operations[op.ID] = &op
+	return op
+}
+
+// Get returns a copy of the operation with the given ID
+func (r *Registry) Get(id string) (Operation, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	op, ok := r.operations[id]
+	if !ok {
+		return Operation{}, false
+	}
+	return *op, true
+}
+
+// Update applies fn to an unfinished operation and returns the updated copy
+func (r *Registry) Update(id string, fn func(op *Operation)) (Operation, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	op, ok := r.operations[id]
+	if !ok {
+		return Operation{}, false
+	}
+	if !op.Status.Terminal() {
+		fn(op)
+		op.UpdatedAt = r.now()
+	}
+	return *op, true
+}
+
+// List returns every tracked operation, oldest first
+func (r *Registry) List() []Operation {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	ops := make([]Operation, 0, len(r.operations))
+	for _, op := range r.operations {
+		ops = append(ops, *op)
+	}
+	sort.Slice(ops, func(i, j int) bool {
+		return ops[i].StartedAt.Before(ops[j].StartedAt)
+	})
+	return ops
+}
+
+// prune drops finished operations older than the retention period. Callers hold r.mu.
+func (r *Registry) prune() {
+	cutoff := r.now().Add(-retention)
+	for id, op := range r.operations {
+		if op.Status.Terminal() && op.UpdatedAt.Before(cutoff) {
+			delete(r.operations, id)
+		}
+	}
+}
+
+func newID() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return "op-" + hex.EncodeToString(b)
+}
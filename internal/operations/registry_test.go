@@ -0,0 +1,76 @@
+package operations_test
+
+import (
+	"strings"
+	"testing"
+
+	"microsoft.com/aml-mcp/internal/operations"
+)
+
+func TestStatusTerminal(t *testing.T) {
+	tests := []struct {
+		status   operations.Status
+		expected bool
+	}{
+		{operations.StatusInProgress, false},
+		{operations.StatusSucceeded, true},
+		{operations.StatusFailed, true},
+		{operations.StatusCanceled, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.status), func(t *testing.T) {
+			if got := tt.status.Terminal(); got != tt.expected {
+				t.Errorf("Terminal() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	r := operations.NewRegistry()
+
+	op := r.Add(operations.Operation{
+		Kind:        operations.KindStartCompute,
+		Target:      "ci-1",
+		ResumeToken: "token",
+	})
+	if !strings.HasPrefix(op.ID, "op-") {
+		t.Errorf("ID = %q, want op- prefix", op.ID)
+	}
+	if op.Status != operations.StatusInProgress {
+		t.Errorf("Status = %q, want InProgress", op.Status)
+	}
+	if op.StartedAt.IsZero() {
+		t.Error("StartedAt was not set")
+	}
+
+	got, ok := r.Get(op.ID)
+	if !ok || got.Target != "ci-1" {
+		t.Fatalf("Get() = %+v, %v", got, ok)
+	}
+	if _, ok := r.Get("op-missing"); ok {
+		t.Error("Get() found an unknown operation")
+	}
+
+	updated, ok := r.Update(op.ID, func(op *operations.Operation) {
+		op.Status = operations.StatusSucceeded
+	})
+	if !ok || updated.Status != operations.StatusSucceeded {
+		t.Fatalf("Update() = %+v, %v", updated, ok)
+	}
+
+	// Finished operations are immutable
+	updated, _ = r.Update(op.ID, func(op *operations.Operation) {
+		op.Status = operations.StatusCanceled
+	})
+	if updated.Status != operations.StatusSucceeded {
+		t.Errorf("Update() changed a finished operation to %q", updated.Status)
+	}
+
+	second := r.Add(operations.Operation{Kind: operations.KindStopCompute})
+	list := r.List()
+	if len(list) != 2 || list[0].ID != op.ID || list[1].ID != second.ID {
+		t.Errorf("List() = %+v, want both operations oldest first", list)
+	}
+}
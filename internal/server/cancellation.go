@@ -0,0 +1,92 @@
+package server
+
+import (
+	"context"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// cancelKeyField is the _meta field the before-call hook passes the cancellation key to the middleware in
+const cancelKeyField = "aml-mcp/cancelKey"
+
+// Cancellation cancels the context of an in-flight tool call when the client cancels it
+type Cancellation struct {
+	mu       sync.Mutex
+	inFlight map[string]context.CancelFunc
+}
+
+// NewCancellation creates a Cancellation with no calls in flight
+func NewCancellation() *Cancellation {
+	return &Cancellation{inFlight: make(map[string]context.CancelFunc)}
+}
+
+// RegisterHooks adds the hook that records each tool call's request ID
+func (c *Cancellation) RegisterHooks(hooks *server.Hooks) {
+	hooks.AddBeforeCallTool(func(ctx context.Context, id any, request *mcp.CallToolRequest) {
+		if request.Params.Meta == nil {
+			request.Params.Meta = &mcp.Meta{}
+		}
+		if request.Params.Meta.AdditionalFields == nil {
+			request.Params.Meta.AdditionalFields = make(map[string]any)
+		}
+		request.Params.Meta.AdditionalFields[cancelKeyField] = cancelKey(ctx, id)
+	})
+}
+
+// Middleware gives each tool call a context that HandleNotification can cancel
+func (c *Cancellation) Middleware(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var key string
+		if request.Params.Meta != nil {
+			key, _ = request.Params.Meta.AdditionalFields[cancelKeyField].(string)
+		}
+		if key == "" {
+			return next(ctx, request)
+		}
+
+		ctx, cancel := context.WithCancel(ctx)
+		c.mu.Lock()
+		c.inFlight[key] = cancel
+		c.mu.Unlock()
+
+		defer func() {
+			c.mu.Lock()
+			delete(c.inFlight, key)
+			c.mu.Unlock()
+			cancel()
+		}()
+
+		return next(ctx, request)
+	}
+}
+
+// HandleNotification handles notifications/cancelled by cancelling the matching tool call
+func (c *Cancellation) HandleNotification(ctx context.Context, notification mcp.JSONRPCNotification) {
+	id, ok := notification.Params.AdditionalFields["requestId"]
+	if !ok {
+		return
+	}
+	key := cancelKey(ctx, id)
+
+	c.mu.Lock()
+	cancel, ok := c.inFlight[key]
+	c.mu.Unlock()
+
+	if ok {
+		cancel()
+	}
+}
+
+// cancelKey identifies a request within its client session
+func cancelKey(ctx context.Context, id any) string {
+	var sessionID string
+	if session := server.ClientSessionFromContext(ctx); session != nil {
+		sessionID = session.SessionID()
+	}
+	if requestID, ok := id.(mcp.RequestId); ok {
+		return sessionID + "/" + requestID.String()
+	}
+	return sessionID + "/" + mcp.NewRequestId(id).String()
+}
@@ -0,0 +1,89 @@
+package server_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	mcpserver "github.com/mark3labs/mcp-go/server"
+	"microsoft.com/aml-mcp/internal/server"
+)
+
+type testSession struct {
+	id            string
+	notifications chan mcp.JSONRPCNotification
+}
+
+func (s *testSession) SessionID() string                                   { return s.id }
+func (s *testSession) NotificationChannel() chan<- mcp.JSONRPCNotification { return s.notifications }
+func (s *testSession) Initialize()                                         {}
+func (s *testSession) Initialized() bool                                   { return true }
+
+// newCancellableServer returns an MCP server with cancellation wired in and a
+// "block" tool that waits until its context is cancelled
+func newCancellableServer() *mcpserver.MCPServer {
+	hooks := &mcpserver.Hooks{}
+	cancellation := server.NewCancellation()
+	cancellation.RegisterHooks(hooks)
+
+	s := mcpserver.NewMCPServer("test", "1.0.0",
+		mcpserver.WithHooks(hooks),
+		mcpserver.WithToolHandlerMiddleware(cancellation.Middleware),
+	)
+	s.AddNotificationHandler("notifications/cancelled", cancellation.HandleNotification)
+	s.AddTool(mcp.NewTool("block"), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		select {
+		case <-ctx.Done():
+			return mcp.NewToolResultError(ctx.Err().Error()), nil
+		case <-time.After(5 * time.Second):
+			return mcp.NewToolResultText("not cancelled"), nil
+		}
+	})
+	return s
+}
+
+func TestCancellation(t *testing.T) {
+	s := newCancellableServer()
+	session := &testSession{id: "session-1", notifications: make(chan mcp.JSONRPCNotification, 10)}
+	other := &testSession{id: "session-2", notifications: make(chan mcp.JSONRPCNotification, 10)}
+	ctx := s.WithContext(context.Background(), session)
+
+	done := make(chan mcp.JSONRPCMessage, 1)
+	go func() {
+		done <- s.HandleMessage(ctx, []byte(`{"jsonrpc":"2.0","id":7,"method":"tools/call","params":{"name":"block"}}`))
+	}()
+
+	// Give the call time to start, then check a cancellation for the same
+	// request ID from another session is ignored
+	time.Sleep(50 * time.Millisecond)
+	s.HandleMessage(s.WithContext(context.Background(), other),
+		[]byte(`{"jsonrpc":"2.0","method":"notifications/cancelled","params":{"requestId":7}}`))
+	select {
+	case <-done:
+		t.Fatal("tool call was cancelled by another session")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	s.HandleMessage(ctx, []byte(`{"jsonrpc":"2.0","method":"notifications/cancelled","params":{"requestId":7,"reason":"user cancelled"}}`))
+
+	select {
+	case response := <-done:
+		result := response.(mcp.JSONRPCResponse).Result.(mcp.CallToolResult)
+		if !result.IsError {
+			t.Errorf("cancelled tool call returned %+v, want an error", result)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("tool call was not cancelled")
+	}
+}
+
+func TestCancellationOfFinishedCall(t *testing.T) {
+	s := newCancellableServer()
+	ctx := s.WithContext(context.Background(), &testSession{id: "session-1", notifications: make(chan mcp.JSONRPCNotification, 10)})
+
+	// Cancelling an unknown request must be a harmless no-op
+	if response := s.HandleMessage(ctx, []byte(`{"jsonrpc":"2.0","method":"notifications/cancelled","params":{"requestId":"missing"}}`)); response != nil {
+		t.Errorf("notification returned %+v, want no response", response)
+	}
+}
@@ -0,0 +1,233 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"microsoft.com/aml-mcp/internal/azure"
+	"microsoft.com/aml-mcp/internal/logging"
+	"microsoft.com/aml-mcp/internal/session"
+	"microsoft.com/aml-mcp/internal/telemetry"
+)
+
+// Environment variables that set the default workspace
+const (
+	EnvSubscriptionID = "AZURE_SUBSCRIPTION_ID"
+	EnvResourceGroup  = "AZURE_RESOURCE_GROUP"
+	EnvWorkspace      = "AZUREML_WORKSPACE_NAME"
+)
+
+// Standard OpenTelemetry variables naming where traces are exported to
+const (
+	EnvOTLPEndpoint       = "OTEL_EXPORTER_OTLP_ENDPOINT"
+	EnvOTLPTracesEndpoint = "OTEL_EXPORTER_OTLP_TRACES_ENDPOINT"
+)
+
+// configFile is the JSON config file format. Every field is optional.
+type configFile struct {
+	Transport        string             `json:"transport"`
+	Address          string             `json:"address"`
+	BasePath         string             `json:"basePath"`
+	ShutdownTimeout  string             `json:"shutdownTimeout"`
+	Defaults         session.Workspace  `json:"defaults"`
+	Subscriptions    []string           `json:"subscriptions"`
+	ReadOnly         bool               `json:"readOnly"`
+	AllowTools       []string           `json:"allowTools"`
+	DenyTools        []string           `json:"denyTools"`
+	SkipConfirmation bool               `json:"skipConfirmation"`
+	AuditLog         string             `json:"auditLog"`
+	Logging          logging.Options    `json:"logging"`
+	Tracing          telemetry.Options  `json:"tracing"`
+	Cloud            azure.CloudOptions `json:"cloud"`
+	Auth             azure.AuthOptions  `json:"auth"`
+	RequestPolicy    requestPolicyFile  `json:"requestPolicy"`
+	CacheTTLs        map[string]string  `json:"cacheTTLs"`
+}
+
+// requestPolicyFile is the requestPolicy section of the config file
+type requestPolicyFile struct {
+	// MaxRetries is a pointer so an explicit 0 is told apart from leaving it out
+	MaxRetries        *int32  `json:"maxRetries"`
+	RetryDelay        string  `json:"retryDelay"`
+	MaxRetryDelay     string  `json:"maxRetryDelay"`
+	TryTimeout        string  `json:"tryTimeout"`
+	IgnoreRetryAfter  bool    `json:"ignoreRetryAfter"`
+	RequestsPerSecond float64 `json:"requestsPerSecond"`
+	Burst             int     `json:"burst"`
+	ToolTimeout       string  `json:"toolTimeout"`
+}
+
+// LoadConfigFile applies the settings in the JSON config file at path to config
+func LoadConfigFile(path string, config *Config) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %v", err)
+	}
+
+	var file configFile
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&file); err != nil {
+		return fmt.Errorf("failed to parse config file %s: %v", path, err)
+	}
+
+	if file.Transport != "" {
+		config.Transport = file.Transport
+	}
+	if file.Address != "" {
+		config.Address = file.Address
+	}
+	if file.BasePath != "" {
+		config.BasePath = file.BasePath
+	}
+	if err := parseDuration(file.ShutdownTimeout, "shutdownTimeout", path, &config.ShutdownTimeout); err != nil {
+		return err
+	}
+	config.Defaults = config.Defaults.Merge(file.Defaults)
+	if len(file.Subscriptions) > 0 {
+		config.Subscriptions = file.Subscriptions
+	}
+	if file.ReadOnly {
+		config.ReadOnly = true
+	}
+	if len(file.AllowTools) > 0 {
+		config.AllowTools = file.AllowTools
+	}
+	if len(file.DenyTools) > 0 {
+		config.DenyTools = file.DenyTools
+	}
+	if file.AuditLog != "" {
+		config.AuditLog = file.AuditLog
+	}
+	if file.SkipConfirmation {
+		config.SkipConfirmation = true
+	}
+	if file.Cloud.Name != "" {
+		config.Cloud.Name = file.Cloud.Name
+	}
+	if file.Cloud.ResourceManagerEndpoint != "" {
+		config.Cloud.ResourceManagerEndpoint = file.Cloud.ResourceManagerEndpoint
+	}
+	if file.Cloud.ResourceManagerAudience != "" {
+		config.Cloud.ResourceManagerAudience = file.Cloud.ResourceManagerAudience
+	}
+	if file.Cloud.AuthorityHost != "" {
+		config.Cloud.AuthorityHost = file.Cloud.AuthorityHost
+	}
+	if len(file.Auth.Modes) > 0 {
+		config.Auth.Modes = file.Auth.Modes
+	}
+	if file.Auth.TenantID != "" {
+		config.Auth.TenantID = file.Auth.TenantID
+	}
+	if file.Auth.ClientID != "" {
+		config.Auth.ClientID = file.Auth.ClientID
+	}
+	if file.Auth.CertificatePath != "" {
+		config.Auth.CertificatePath = file.Auth.CertificatePath
+	}
+	if file.Auth.RedirectURL != "" {
+		config.Auth.RedirectURL = file.Auth.RedirectURL
+	}
+	if len(file.Auth.SubscriptionTenants) > 0 {
+		config.Auth.SubscriptionTenants = file.Auth.SubscriptionTenants
+	}
+	if err := file.RequestPolicy.apply(path, config); err != nil {
+		return err
+	}
+	for tool, value := range file.CacheTTLs {
+		var ttl time.Duration
+		if err := parseDuration(value, "cacheTTLs."+tool, path, &ttl); err != nil {
+			return err
+		}
+		if config.CacheTTLs == nil {
+			config.CacheTTLs = make(map[string]time.Duration)
+		}
+		config.CacheTTLs[tool] = ttl
+	}
+	if file.Logging.Level != "" {
+		config.Logging.Level = file.Logging.Level
+	}
+	if file.Logging.Format != "" {
+		config.Logging.Format = file.Logging.Format
+	}
+	if file.Logging.File != "" {
+		config.Logging.File = file.Logging.File
+	}
+	if file.Tracing.Endpoint != "" {
+		config.Tracing.Endpoint = file.Tracing.Endpoint
+	}
+	if len(file.Tracing.Headers) > 0 {
+		config.Tracing.Headers = file.Tracing.Headers
+	}
+	return nil
+}
+
+// apply sets the request policy settings the file gives on config
+func (p requestPolicyFile) apply(path string, config *Config) error {
+	policy := &config.RequestPolicy
+	if p.MaxRetries != nil {
+		policy.MaxRetries = *p.MaxRetries
+		// A zero RequestPolicy.MaxRetries keeps the SDK default
+		if policy.MaxRetries == 0 {
+			policy.MaxRetries = -1
+		}
+	}
+	if p.IgnoreRetryAfter {
+		policy.IgnoreRetryAfter = true
+	}
+	if p.RequestsPerSecond != 0 {
+		policy.RequestsPerSecond = p.RequestsPerSecond
+	}
+	if p.Burst != 0 {
+		policy.Burst = p.Burst
+	}
+	for _, d := range []struct {
+		value, name string
+		dst         *time.Duration
+	}{
+		{p.RetryDelay, "requestPolicy.retryDelay", &policy.RetryDelay},
+		{p.MaxRetryDelay, "requestPolicy.maxRetryDelay", &policy.MaxRetryDelay},
+		{p.TryTimeout, "requestPolicy.tryTimeout", &policy.TryTimeout},
+		{p.ToolTimeout, "requestPolicy.toolTimeout", &config.ToolTimeout},
+	} {
+		if err := parseDuration(d.value, d.name, path, d.dst); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parseDuration parses the duration setting name into dst unless value is empty
+func parseDuration(value, name, path string, dst *time.Duration) error {
+	if value == "" {
+		return nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return fmt.Errorf("invalid %s in config file %s: %v", name, path, err)
+	}
+	*dst = d
+	return nil
+}
+
+// ApplyEnvironment applies the default workspace and OTLP environment variables to config
+func ApplyEnvironment(config *Config) {
+	config.Defaults.SubscriptionID = envOr(EnvSubscriptionID, config.Defaults.SubscriptionID)
+	config.Defaults.ResourceGroup = envOr(EnvResourceGroup, config.Defaults.ResourceGroup)
+	config.Defaults.Workspace = envOr(EnvWorkspace, config.Defaults.Workspace)
+	if base := os.Getenv(EnvOTLPEndpoint); base != "" {
+		config.Tracing.Endpoint = telemetry.TracesEndpoint(base)
+	}
+	config.Tracing.Endpoint = envOr(EnvOTLPTracesEndpoint, config.Tracing.Endpoint)
+}
+
+func envOr(name, fallback string) string {
+	if value := os.Getenv(name); value != "" {
+		return value
+	}
+	return fallback
+}
@@ -0,0 +1,201 @@
+package server_test
+
+import (
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"microsoft.com/aml-mcp/internal/azure"
+	"microsoft.com/aml-mcp/internal/logging"
+	"microsoft.com/aml-mcp/internal/server"
+	"microsoft.com/aml-mcp/internal/session"
+	"microsoft.com/aml-mcp/internal/telemetry"
+)
+
+func writeConfigFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("failed to write config file: %v", err)
+	}
+	return path
+}
+
+func TestLoadConfigFile(t *testing.T) {
+	path := writeConfigFile(t, `{
+		"transport": "http",
+		"shutdownTimeout": "30s",
+		"defaults": {"subscriptionId": "sub-1", "resourceGroup": "rg-1"},
+		"readOnly": true,
+		"denyTools": ["network", "list_usage"],
+		"logging": {"level": "debug", "format": "json"},
+		"tracing": {"endpoint": "http://localhost:4318", "headers": {"api-key": "secret"}},
+		"requestPolicy": {"maxRetries": 5, "retryDelay": "2s", "requestsPerSecond": 4, "toolTimeout": "5m"},
+		"cacheTTLs": {"list_vm_sizes": "2h", "list_quotas": "0s"},
+		"subscriptions": ["sub-1", "sub-2"],
+		"cloud": {"name": "AzureChina", "resourceManagerAudience": "https://management.core.chinacloudapi.cn/"},
+		"auth": {"modes": ["managed-identity", "cli"], "clientId": "client-1", "subscriptionTenants": {"sub-2": "tenant-2"}}
+	}`)
+
+	config := server.Config{
+		Address:  server.DefaultAddress,
+		Defaults: session.Workspace{Workspace: "ws-1"},
+	}
+	if err := server.LoadConfigFile(path, &config); err != nil {
+		t.Fatalf("LoadConfigFile() error = %v", err)
+	}
+
+	if config.Transport != server.TransportHTTP {
+		t.Errorf("Transport = %q, want %q", config.Transport, server.TransportHTTP)
+	}
+	if config.Address != server.DefaultAddress {
+		t.Errorf("Address = %q, want unchanged %q", config.Address, server.DefaultAddress)
+	}
+	if config.ShutdownTimeout != 30*time.Second {
+		t.Errorf("ShutdownTimeout = %v, want 30s", config.ShutdownTimeout)
+	}
+	want := session.Workspace{SubscriptionID: "sub-1", ResourceGroup: "rg-1", Workspace: "ws-1"}
+	if config.Defaults != want {
+		t.Errorf("Defaults = %+v, want %+v", config.Defaults, want)
+	}
+	if !config.ReadOnly {
+		t.Error("ReadOnly = false, want true")
+	}
+	if !slices.Equal(config.DenyTools, []string{"network", "list_usage"}) {
+		t.Errorf("DenyTools = %v, want [network list_usage]", config.DenyTools)
+	}
+	if config.AllowTools != nil {
+		t.Errorf("AllowTools = %v, want unchanged nil", config.AllowTools)
+	}
+	if want := (logging.Options{Level: "debug", Format: "json"}); config.Logging != want {
+		t.Errorf("Logging = %+v, want %+v", config.Logging, want)
+	}
+	if config.Tracing.Endpoint != "http://localhost:4318" || config.Tracing.Headers["api-key"] != "secret" {
+		t.Errorf("Tracing = %+v, want the collector endpoint and its header", config.Tracing)
+	}
+	if want := (azure.RequestPolicy{MaxRetries: 5, RetryDelay: 2 * time.Second, RequestsPerSecond: 4}); config.RequestPolicy != want {
+		t.Errorf("RequestPolicy = %+v, want %+v", config.RequestPolicy, want)
+	}
+	if config.ToolTimeout != 5*time.Minute {
+		t.Errorf("ToolTimeout = %v, want 5m", config.ToolTimeout)
+	}
+	if want := map[string]time.Duration{"list_vm_sizes": 2 * time.Hour, "list_quotas": 0}; !maps.Equal(config.CacheTTLs, want) {
+		t.Errorf("CacheTTLs = %v, want %v", config.CacheTTLs, want)
+	}
+	if !slices.Equal(config.Subscriptions, []string{"sub-1", "sub-2"}) {
+		t.Errorf("Subscriptions = %v, want [sub-1 sub-2]", config.Subscriptions)
+	}
+	if want := (azure.CloudOptions{Name: "AzureChina", ResourceManagerAudience: "https://management.core.chinacloudapi.cn/"}); config.Cloud != want {
+		t.Errorf("Cloud = %+v, want %+v", config.Cloud, want)
+	}
+	if !slices.Equal(config.Auth.Modes, []string{"managed-identity", "cli"}) || config.Auth.ClientID != "client-1" {
+		t.Errorf("Auth = %+v, want modes [managed-identity cli] and client ID client-1", config.Auth)
+	}
+	if want := map[string]string{"sub-2": "tenant-2"}; !maps.Equal(config.Auth.SubscriptionTenants, want) {
+		t.Errorf("Auth.SubscriptionTenants = %v, want %v", config.Auth.SubscriptionTenants, want)
+	}
+}
+
+func TestLoadConfigFileMaxRetries(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    int32
+	}{
+		{"left out", `{"requestPolicy": {}}`, 2},
+		// An explicit 0 turns retries off rather than keeping the default
+		{"zero", `{"requestPolicy": {"maxRetries": 0}}`, -1},
+		{"set", `{"requestPolicy": {"maxRetries": 5}}`, 5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := server.Config{RequestPolicy: azure.RequestPolicy{MaxRetries: 2}}
+			if err := server.LoadConfigFile(writeConfigFile(t, tt.content), &config); err != nil {
+				t.Fatalf("LoadConfigFile() error = %v", err)
+			}
+			if config.RequestPolicy.MaxRetries != tt.want {
+				t.Errorf("MaxRetries = %d, want %d", config.RequestPolicy.MaxRetries, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadConfigFileErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		contains string
+	}{
+		{"unknown field", `{"transprot": "http"}`, "transprot"},
+		{"invalid duration", `{"shutdownTimeout": "soon"}`, "shutdownTimeout"},
+		{"invalid request policy duration", `{"requestPolicy": {"tryTimeout": "1"}}`, "requestPolicy.tryTimeout"},
+		{"invalid json", `{`, "failed to parse"},
+		// Secrets are only read from the environment
+		{"client secret", `{"auth": {"clientSecret": "s3cret"}}`, "clientSecret"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var config server.Config
+			err := server.LoadConfigFile(writeConfigFile(t, tt.content), &config)
+			if err == nil || !strings.Contains(err.Error(), tt.contains) {
+				t.Errorf("LoadConfigFile() error = %v, want one mentioning %q", err, tt.contains)
+			}
+		})
+	}
+
+	var config server.Config
+	if err := server.LoadConfigFile(filepath.Join(t.TempDir(), "missing.json"), &config); err == nil {
+		t.Error("LoadConfigFile() with a missing file returned no error")
+	}
+}
+
+func TestApplyEnvironment(t *testing.T) {
+	t.Setenv(server.EnvSubscriptionID, "env-sub")
+	t.Setenv(server.EnvResourceGroup, "")
+	t.Setenv(server.EnvWorkspace, "env-ws")
+	t.Setenv(server.EnvOTLPEndpoint, "http://collector:4318")
+
+	config := server.Config{
+		Defaults: session.Workspace{SubscriptionID: "file-sub", ResourceGroup: "file-rg"},
+		Tracing:  telemetry.Options{Endpoint: "http://localhost:4318"},
+	}
+	server.ApplyEnvironment(&config)
+
+	want := session.Workspace{SubscriptionID: "env-sub", ResourceGroup: "file-rg", Workspace: "env-ws"}
+	if config.Defaults != want {
+		t.Errorf("Defaults = %+v, want %+v", config.Defaults, want)
+	}
+	if config.Tracing.Endpoint != "http://collector:4318/v1/traces" {
+		t.Errorf("Tracing.Endpoint = %q, want the traces path of the environment's endpoint", config.Tracing.Endpoint)
+	}
+}
+
+func TestApplyEnvironmentOTLPEndpoint(t *testing.T) {
+	tests := []struct {
+		name     string
+		base     string
+		traces   string
+		wantPath string
+	}{
+		// The base URL's path is a prefix the traces path is added to
+		{name: "base path", base: "http://gw/otlp/", wantPath: "http://gw/otlp/v1/traces"},
+		{name: "traces endpoint", base: "http://gw/otlp", traces: "http://gw/spans", wantPath: "http://gw/spans"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(server.EnvOTLPEndpoint, tt.base)
+			t.Setenv(server.EnvOTLPTracesEndpoint, tt.traces)
+
+			config := server.Config{Tracing: telemetry.Options{Endpoint: "http://localhost:4318"}}
+			server.ApplyEnvironment(&config)
+			if config.Tracing.Endpoint != tt.wantPath {
+				t.Errorf("Tracing.Endpoint = %q, want %q", config.Tracing.Endpoint, tt.wantPath)
+			}
+		})
+	}
+}
@@ -1,54 +1,391 @@
 package server
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"log/slog"
+	"maps"
+	"net/http"
+	"os"
+	"strings"
+	"time"
 
+	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
+	"microsoft.com/aml-mcp/internal/audit"
+	"microsoft.com/aml-mcp/internal/azure"
+	"microsoft.com/aml-mcp/internal/cache"
+	"microsoft.com/aml-mcp/internal/confirm"
+	"microsoft.com/aml-mcp/internal/logging"
+	"microsoft.com/aml-mcp/internal/operations"
+	"microsoft.com/aml-mcp/internal/session"
+	"microsoft.com/aml-mcp/internal/telemetry"
 	"microsoft.com/aml-mcp/internal/tools"
 )
 
+// Supported transports for Serve
+const (
+	// TransportStdio serves a single client over stdin/stdout
+	TransportStdio = "stdio"
+	// TransportSSE serves clients over the legacy HTTP+SSE transport
+	TransportSSE = "sse"
+	// TransportHTTP serves clients over the streamable HTTP transport
+	TransportHTTP = "http"
+)
+
+const (
+	// DefaultAddress is the listen address used by the HTTP transports when none is configured
+	DefaultAddress = "localhost:8080"
+	// DefaultShutdownTimeout bounds how long the HTTP transports wait for in-flight requests on shutdown
+	DefaultShutdownTimeout = 10 * time.Second
+)
+
 // Config holds the configuration for the MCP server
 type Config struct {
 	Name    string
 	Version string
+
+	// Transport selects how clients connect: stdio (default), sse or http
+	Transport string
+	// Address is the host:port the sse and http transports listen on
+	Address string
+	// BasePath is the URL path prefix the sse and http transports are served under
+	BasePath string
+	// ShutdownTimeout bounds graceful shutdown of the sse and http transports
+	ShutdownTimeout time.Duration
+
+	// Defaults are the workspace tools use when a call and the session leave it out
+	Defaults session.Workspace
+	// Subscriptions are the subscriptions get_inventory covers by default
+	Subscriptions []string
+
+	// ReadOnly registers only tools that do not change Azure resources
+	ReadOnly bool
+	// AllowTools, when not empty, registers only the listed tools or categories
+	AllowTools []string
+	// DenyTools removes the listed tools or categories
+	DenyTools []string
+	// SkipConfirmation lets mutating tools act without asking the user to confirm
+	SkipConfirmation bool
+
+	// AuditLog is where calls of mutating tools are recorded, as accepted by audit.Open
+	AuditLog string
+
+	// Cloud selects the Azure cloud the server signs in to and manages resources in
+	Cloud azure.CloudOptions
+	// Auth selects how the server signs in to Azure
+	Auth azure.AuthOptions
+
+	// RequestPolicy sets retries, timeouts and rate limits of ARM requests
+	RequestPolicy azure.RequestPolicy
+	// ToolTimeout bounds each tool call; zero means no limit
+	ToolTimeout time.Duration
+
+	// CacheTTLs overrides tools.DefaultCacheTTLs by tool name; zero turns caching off
+	CacheTTLs map[string]time.Duration
+
+	// Logging configures the server's logs, which the caller sets up
+	Logging logging.Options
+	// Tracing selects the OTLP endpoint traces are exported to
+	Tracing telemetry.Options
 }
 
 // MCPServer wraps the underlying MCP server with our tools
 type MCPServer struct {
 	server *server.MCPServer
+	config Config
+	audit  *audit.Log
+	// tracing exports spans until Serve returns
+	tracing *telemetry.Provider
+	// err is a configuration error found by New, reported by Serve
+	err error
 }
 
 // New creates a new MCP server with all Azure ML tools registered
 func New(config Config) *MCPServer {
+	hooks := &server.Hooks{}
+	cancellation := NewCancellation()
+	cancellation.RegisterHooks(hooks)
+
+	// Active workspaces are per client session and forgotten when it ends
+	sessions := session.NewStore()
+	hooks.AddOnUnregisterSession(func(ctx context.Context, s server.ClientSession) {
+		sessions.Delete(s.SessionID())
+	})
+
 	s := server.NewMCPServer(
 		config.Name,
 		config.Version,
 		server.WithToolCapabilities(false),
+		server.WithResourceCapabilities(false, false),
+		server.WithPromptCapabilities(false),
 		server.WithRecovery(),
+		server.WithHooks(hooks),
+		server.WithElicitation(),
+		server.WithLogging(),
+		server.WithToolHandlerMiddleware(cancellation.Middleware),
+		server.WithToolHandlerMiddleware(toolTimeout(config.ToolTimeout)),
 	)
+	s.AddNotificationHandler("notifications/cancelled", cancellation.HandleNotification)
+
+	// One client cache is shared by every tool; setting errors are reported by Serve
+	cloudConfig, cloudErr := config.Cloud.Configuration()
+	authErr := config.Auth.Validate()
+
+	// An invalid endpoint is reported by Serve
+	tracing, tracingErr := telemetry.New(config.Tracing, config.Version)
+	if tracingErr != nil {
+		tracing = telemetry.Disabled()
+	}
+	clients := azure.NewClientCacheWithOptions(azure.ClientCacheOptions{
+		Cloud: cloudConfig,
+		Auth:  config.Auth,
+		DeviceCodePrompt: func(ctx context.Context, message string) error {
+			return promptDeviceCode(ctx, s, message)
+		},
+		RequestPolicy:  config.RequestPolicy,
+		TracerProvider: tracing,
+	})
+
+	// All tool sets share operations, sessions and defaults
+	toolOptions := []tools.Option{
+		tools.WithOperations(operations.NewRegistry()),
+		tools.WithSessions(sessions),
+		tools.WithDefaults(config.Defaults),
+		tools.WithSubscriptions(config.Subscriptions),
+	}
+	if !config.SkipConfirmation {
+		toolOptions = append(toolOptions, tools.WithConfirmations(confirm.NewStore(confirm.DefaultTTL)))
+	}
+
+	// Calls are traced, logged and audited before the cache can answer them
 
-	// Register all tool categories
-	workspaceTools := tools.NewWorkspaceTools()
-	workspaceTools.AddToServer(s)
+	// Every tool call is a span, the parent of the spans of its ARM requests
+	traced := tools.Traced(s, tracing.Tracer(tools.TracerName), toolOptions...)
 
-	computeTools := tools.NewComputeTools()
-	computeTools.AddToServer(s)
+	// Every tool call is logged, with the tool and the workspace it targets
+	logged := tools.Logged(traced, slog.Default(), toolOptions...)
 
-	monitoringTools := tools.NewMonitoringTools()
-	monitoringTools.AddToServer(s)
+	// A log that cannot be opened is reported by Serve
+	auditLog, err := audit.Open(config.AuditLog)
+	if err != nil {
+		auditLog = audit.NewLog(nil)
+	}
+	audited := tools.Audited(logged, auditLog, clients)
+
+	// Apply the configured cache TTLs over the defaults
+	ttls := maps.Clone(tools.DefaultCacheTTLs)
+	maps.Copy(ttls, config.CacheTTLs)
+	cached := tools.Cached(audited, cache.New(), ttls, toolOptions...)
+
+	// Register all tool categories the policy allows
+	policy := newToolPolicy(config)
+
+	workspaceTools := tools.NewWorkspaceTools(clients, toolOptions...)
+	workspaceTools.AddToServer(policy.registrar(cached, tools.CategoryWorkspace))
+	workspaceTools.AddResourcesToServer(policy.resourceRegistrar(s, tools.CategoryWorkspace))
+
+	computeTools := tools.NewComputeTools(clients, toolOptions...)
+	computeTools.AddToServer(policy.registrar(cached, tools.CategoryCompute))
+	computeTools.AddResourcesToServer(policy.resourceRegistrar(s, tools.CategoryCompute))
+
+	monitoringTools := tools.NewMonitoringTools(clients, toolOptions...)
+	monitoringTools.AddToServer(policy.registrar(cached, tools.CategoryMonitoring))
+
+	networkTools := tools.NewNetworkTools(clients, toolOptions...)
+	networkTools.AddToServer(policy.registrar(cached, tools.CategoryNetwork))
+
+	contextTools := tools.NewContextTools(clients, toolOptions...)
+	contextTools.AddToServer(policy.registrar(cached, tools.CategoryContext))
+
+	operationTools := tools.NewOperationTools(clients, toolOptions...)
+	operationTools.AddToServer(policy.registrar(cached, tools.CategoryOperations))
+
+	auditTools := tools.NewAuditTools(auditLog)
+	auditTools.AddToServer(policy.registrar(cached, tools.CategoryAudit))
+
+	authTools := tools.NewAuthTools(clients, toolOptions...)
+	authTools.AddToServer(policy.registrar(cached, tools.CategoryAuth))
 
-	networkTools := tools.NewNetworkTools()
-	networkTools.AddToServer(s)
+	accessTools := tools.NewAccessTools(clients, toolOptions...)
+	accessTools.AddToServer(policy.registrar(cached, tools.CategoryAccess))
 
-	return &MCPServer{server: s}
+	// Prompts only mention the tools the policy registered
+	prompts := tools.NewPrompts(toolOptions...)
+	prompts.AddToServer(s)
+
+	policy.warnUnmatched()
+
+	return &MCPServer{
+		server:  s,
+		config:  config,
+		audit:   auditLog,
+		tracing: tracing,
+		err:     errors.Join(cloudErr, authErr, tracingErr, err),
+	}
+}
+
+// HandleMessage processes a single JSON-RPC message in-process
+func (ms *MCPServer) HandleMessage(ctx context.Context, message json.RawMessage) mcp.JSONRPCMessage {
+	return ms.server.HandleMessage(ctx, message)
 }
 
-// Serve starts the MCP server using stdio
-func (ms *MCPServer) Serve() error {
-	fmt.Println("Starting Azure Machine Learning MCP Server...")
+// Serve runs the MCP server on the configured transport until ctx is cancelled or the transport fails
+func (ms *MCPServer) Serve(ctx context.Context) error {
+	if ms.err != nil {
+		return ms.err
+	}
+	defer ms.audit.Close()
+	defer ms.shutdownTracing()
+
+	slog.Info("Starting Azure Machine Learning MCP Server", "version", ms.config.Version, "transport", ms.transport())
+
+	switch ms.transport() {
+	case TransportStdio:
+		return ms.serveStdio(ctx)
+	case TransportSSE:
+		return ms.serveHTTP(ctx, ms.newSSEServer())
+	case TransportHTTP:
+		return ms.serveHTTP(ctx, ms.newStreamableHTTPServer())
+	default:
+		return fmt.Errorf("unsupported transport %q (expected %s, %s or %s)",
+			ms.config.Transport, TransportStdio, TransportSSE, TransportHTTP)
+	}
+}
 
-	if err := server.ServeStdio(ms.server); err != nil {
+func (ms *MCPServer) serveStdio(ctx context.Context) error {
+	stdioServer := server.NewStdioServer(ms.server)
+	stdioServer.SetErrorLogger(slog.NewLogLogger(slog.Default().Handler(), slog.LevelError))
+	err := stdioServer.Listen(ctx, os.Stdin, os.Stdout)
+	if err != nil && !errors.Is(err, context.Canceled) {
 		return fmt.Errorf("server error: %v", err)
 	}
 	return nil
 }
+
+// httpTransport is implemented by the mcp-go SSE and streamable HTTP servers
+type httpTransport interface {
+	Start(addr string) error
+	Shutdown(ctx context.Context) error
+}
+
+func (ms *MCPServer) serveHTTP(ctx context.Context, transport httpTransport) error {
+	address := ms.address()
+	slog.Info("Listening for connections", "transport", ms.config.Transport, "address", address, "basePath", ms.basePath())
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- transport.Start(address)
+	}()
+
+	select {
+	case err := <-errCh:
+		if errors.Is(err, http.ErrServerClosed) {
+			return nil
+		}
+		return fmt.Errorf("server error: %v", err)
+	case <-ctx.Done():
+	}
+
+	timeout := ms.config.ShutdownTimeout
+	if timeout <= 0 {
+		timeout = DefaultShutdownTimeout
+	}
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	slog.Info("Shutting down Azure Machine Learning MCP Server")
+	if err := transport.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("shutdown error: %v", err)
+	}
+	return nil
+}
+
+func (ms *MCPServer) newSSEServer() *server.SSEServer {
+	httpServer := &http.Server{Addr: ms.address()}
+	sseServer := server.NewSSEServer(ms.server,
+		server.WithStaticBasePath(ms.basePath()),
+		server.WithHTTPServer(httpServer),
+	)
+	httpServer.Handler = sseServer
+	return sseServer
+}
+
+func (ms *MCPServer) newStreamableHTTPServer() *server.StreamableHTTPServer {
+	endpoint := ms.basePath() + "/mcp"
+	mux := http.NewServeMux()
+	httpServer := &http.Server{Addr: ms.address(), Handler: mux}
+	streamableServer := server.NewStreamableHTTPServer(ms.server,
+		server.WithEndpointPath(endpoint),
+		server.WithStreamableHTTPServer(httpServer),
+	)
+	mux.Handle(endpoint, streamableServer)
+	return streamableServer
+}
+
+// transport returns the configured transport, defaulting to stdio
+func (ms *MCPServer) transport() string {
+	if ms.config.Transport == "" {
+		return TransportStdio
+	}
+	return ms.config.Transport
+}
+
+func (ms *MCPServer) address() string {
+	if ms.config.Address == "" {
+		return DefaultAddress
+	}
+	return ms.config.Address
+}
+
+// basePath normalises the configured base path to either "" or "/segment[/segment...]"
+func (ms *MCPServer) basePath() string {
+	path := strings.Trim(ms.config.BasePath, "/")
+	if path == "" {
+		return ""
+	}
+	return "/" + path
+}
+
+// shutdownTracing exports the spans still buffered, giving up after the shutdown timeout
+func (ms *MCPServer) shutdownTracing() {
+	timeout := ms.config.ShutdownTimeout
+	if timeout <= 0 {
+		timeout = DefaultShutdownTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	if err := ms.tracing.Shutdown(ctx); err != nil {
+		slog.Warn("Failed to shut down tracing", "error", err)
+	}
+}
+
+// toolTimeout returns middleware that bounds each tool call by a non-zero timeout
+func toolTimeout(timeout time.Duration) server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		if timeout <= 0 {
+			return next
+		}
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			ctx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+			return next(ctx, request)
+		}
+	}
+}
+
+// promptDeviceCode logs device code sign-in instructions and sends them to the client as an alert
+func promptDeviceCode(ctx context.Context, s *server.MCPServer, message string) error {
+	logging.FromContext(ctx).Warn("Azure sign-in required", "instructions", message)
+
+	if server.ClientSessionFromContext(ctx) == nil {
+		return nil
+	}
+	notification := mcp.NewLoggingMessageNotification(mcp.LoggingLevelAlert, "azure-auth", message)
+	if err := s.SendLogMessageToClient(ctx, notification); err != nil {
+		logging.FromContext(ctx).Debug("Could not send sign-in instructions to the client", "error", err)
+	}
+	return nil
+}
@@ -1,9 +1,19 @@
 package server_test
 
 import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
 	"testing"
+	"time"
 
+	"microsoft.com/aml-mcp/internal/azure"
 	"microsoft.com/aml-mcp/internal/server"
+	"microsoft.com/aml-mcp/internal/telemetry"
 )
 
 func TestNew(t *testing.T) {
@@ -69,9 +79,171 @@ func TestServerInterface(t *testing.T) {
 	s := server.New(config)
 
 	// Test that the server has the Serve method
-	// Note: We can't actually call Serve() in tests as it would block
-	// and requires stdio setup
+	// Note: Serve() on stdio would block on the test's stdin, so the
+	// transport tests below exercise the HTTP transports instead
 	if s == nil {
 		t.Fatal("Expected server to be non-nil")
 	}
 }
+
+// freeAddress returns a loopback address with a port that is free at the time of the call
+func freeAddress(t *testing.T) string {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to reserve a port: %v", err)
+	}
+	addr := l.Addr().String()
+	_ = l.Close()
+	return addr
+}
+
+// startServer runs Serve in the background and returns a function that stops
+// it and reports the error Serve returned
+func startServer(t *testing.T, config server.Config) func() error {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- server.New(config).Serve(ctx)
+	}()
+	return func() error {
+		cancel()
+		select {
+		case err := <-errCh:
+			return err
+		case <-time.After(5 * time.Second):
+			t.Fatal("Serve() did not return after cancellation")
+			return nil
+		}
+	}
+}
+
+// waitFor retries fn until it succeeds or the deadline passes
+func waitFor(t *testing.T, fn func() error) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		err := fn()
+		if err == nil {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for server: %v", err)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+func TestServeStreamableHTTP(t *testing.T) {
+	addr := freeAddress(t)
+	stop := startServer(t, server.Config{
+		Name:      "Test Server",
+		Version:   "1.0.0",
+		Transport: server.TransportHTTP,
+		Address:   addr,
+		BasePath:  "/aml/",
+	})
+
+	initialize := `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-03-26","capabilities":{},"clientInfo":{"name":"test","version":"1.0.0"}}}`
+	var body []byte
+	waitFor(t, func() error {
+		resp, err := http.Post("http://"+addr+"/aml/mcp", "application/json", strings.NewReader(initialize))
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("status %d", resp.StatusCode)
+		}
+		body, err = io.ReadAll(resp.Body)
+		return err
+	})
+
+	if !strings.Contains(string(body), "Test Server") {
+		t.Errorf("initialize response %s does not contain server name", body)
+	}
+
+	if err := stop(); err != nil {
+		t.Errorf("Serve() returned %v after graceful shutdown", err)
+	}
+}
+
+func TestServeSSE(t *testing.T) {
+	addr := freeAddress(t)
+	stop := startServer(t, server.Config{
+		Name:      "Test Server",
+		Version:   "1.0.0",
+		Transport: server.TransportSSE,
+		Address:   addr,
+		BasePath:  "aml",
+	})
+
+	var endpointEvent string
+	waitFor(t, func() error {
+		resp, err := http.Get("http://" + addr + "/aml/sse")
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("status %d", resp.StatusCode)
+		}
+		// The first event tells the client where to post messages
+		reader := bufio.NewReader(resp.Body)
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				return err
+			}
+			if strings.HasPrefix(line, "data:") {
+				endpointEvent = line
+				return nil
+			}
+		}
+	})
+
+	if !strings.Contains(endpointEvent, "/aml/message") {
+		t.Errorf("endpoint event %q does not point at /aml/message", endpointEvent)
+	}
+
+	if err := stop(); err != nil {
+		t.Errorf("Serve() returned %v after graceful shutdown", err)
+	}
+}
+
+func TestServeUnsupportedTransport(t *testing.T) {
+	s := server.New(server.Config{
+		Name:      "Test Server",
+		Version:   "1.0.0",
+		Transport: "carrier-pigeon",
+	})
+
+	err := s.Serve(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "unsupported transport") {
+		t.Errorf("Serve() error = %v, want unsupported transport error", err)
+	}
+}
+
+func TestServeInvalidSettings(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   server.Config
+		contains string
+	}{
+		{"unknown cloud", server.Config{Cloud: azure.CloudOptions{Name: "AzureMoon"}}, `unknown Azure cloud "AzureMoon"`},
+		{"unknown auth mode", server.Config{Auth: azure.AuthOptions{Modes: []string{"password"}}}, `unknown auth mode "password"`},
+		{"invalid OTLP endpoint", server.Config{Tracing: telemetry.Options{Endpoint: "localhost:4318"}}, `invalid OTLP endpoint "localhost:4318"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.config.Name = "Test Server"
+			tt.config.Version = "1.0.0"
+			err := server.New(tt.config).Serve(context.Background())
+			if err == nil || !strings.Contains(err.Error(), tt.contains) {
+				t.Errorf("Serve() error = %v, want one containing %q", err, tt.contains)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package server
+
+import (
+	"log/slog"
+	"slices"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"microsoft.com/aml-mcp/internal/tools"
+)
+
+// toolPolicy decides which tools are registered from the ReadOnly, AllowTools and DenyTools settings
+type toolPolicy struct {
+	readOnly bool
+	allow    []string
+	deny     []string
+	// seen records every tool and category offered
+	seen map[string]bool
+}
+
+func newToolPolicy(config Config) *toolPolicy {
+	return &toolPolicy{
+		readOnly: config.ReadOnly,
+		allow:    config.AllowTools,
+		deny:     config.DenyTools,
+		seen:     make(map[string]bool),
+	}
+}
+
+// allows reports whether a tool in the given category may be registered
+func (p *toolPolicy) allows(tool mcp.Tool, category string) bool {
+	p.seen[tool.Name] = true
+	p.seen[category] = true
+
+	if p.readOnly && (tool.Annotations.ReadOnlyHint == nil || !*tool.Annotations.ReadOnlyHint) {
+		return false
+	}
+	if slices.Contains(p.deny, tool.Name) || slices.Contains(p.deny, category) {
+		return false
+	}
+	if len(p.allow) > 0 && !slices.Contains(p.allow, tool.Name) && !slices.Contains(p.allow, category) {
+		return false
+	}
+	return true
+}
+
+// allowsResources reports whether the MCP resources of a category may be registered
+func (p *toolPolicy) allowsResources(category string) bool {
+	if slices.Contains(p.deny, category) {
+		return false
+	}
+	return len(p.allow) == 0 || slices.Contains(p.allow, category)
+}
+
+// registrar returns a tools.Registrar that adds the allowed tools of one category to next
+func (p *toolPolicy) registrar(next tools.Registrar, category string) tools.Registrar {
+	return &policyRegistrar{next: next, policy: p, category: category}
+}
+
+// resourceRegistrar returns a tools.ResourceRegistrar that adds the allowed resources of one category to next
+func (p *toolPolicy) resourceRegistrar(next tools.ResourceRegistrar, category string) tools.ResourceRegistrar {
+	if !p.allowsResources(category) {
+		return discardResources{}
+	}
+	return next
+}
+
+// warnUnmatched logs allow and deny entries that matched no tool or category
+func (p *toolPolicy) warnUnmatched() {
+	for _, entry := range append(slices.Clone(p.allow), p.deny...) {
+		if !p.seen[entry] {
+			slog.Warn("Tool or category in the allow/deny lists does not exist", "entry", entry)
+		}
+	}
+}
+
+type policyRegistrar struct {
+	next     tools.Registrar
+	policy   *toolPolicy
+	category string
+}
+
+func (r *policyRegistrar) AddTool(tool mcp.Tool, handler server.ToolHandlerFunc) {
+	if r.policy.allows(tool, r.category) {
+		r.next.AddTool(tool, handler)
+	}
+}
+
+// discardResources is the tools.ResourceRegistrar of a denied category
+type discardResources struct{}
+
+func (discardResources) AddResource(mcp.Resource, server.ResourceHandlerFunc) {}
+
+func (discardResources) AddResourceTemplate(mcp.ResourceTemplate, server.ResourceTemplateHandlerFunc) {
+}
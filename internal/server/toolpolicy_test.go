@@ -0,0 +1,161 @@
+package server_test
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"testing"
+
+	"microsoft.com/aml-mcp/internal/server"
+)
+
+// toolNames returns the names of the tools the server lists, sorted
+func toolNames(t *testing.T, s *server.MCPServer) []string {
+	t.Helper()
+
+	response := s.HandleMessage(context.Background(), []byte(`{"jsonrpc":"2.0","id":1,"method":"tools/list"}`))
+	data, err := json.Marshal(response)
+	if err != nil {
+		t.Fatalf("failed to marshal tools/list response: %v", err)
+	}
+	var decoded struct {
+		Result struct {
+			Tools []struct {
+				Name string `json:"name"`
+			} `json:"tools"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("failed to decode tools/list response %s: %v", data, err)
+	}
+
+	var names []string
+	for _, tool := range decoded.Result.Tools {
+		names = append(names, tool.Name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+func TestToolPolicy(t *testing.T) {
+	mutating := []string{"create_workspace", "start_compute", "stop_compute"}
+
+	tests := []struct {
+		name    string
+		config  server.Config
+		include []string
+		exclude []string
+	}{
+		{
+			name:    "everything by default",
+			include: append([]string{"get_workspace", "list_quotas", "set_active_workspace", "get_operation_status"}, mutating...),
+		},
+		{
+			name:    "read-only",
+			config:  server.Config{ReadOnly: true},
+			include: []string{"get_workspace", "list_compute", "list_quotas", "list_private_endpoints", "set_active_workspace", "get_operation_status"},
+			exclude: mutating,
+		},
+		{
+			name:    "allow by category and name",
+			config:  server.Config{AllowTools: []string{"monitoring", "get_workspace"}},
+			include: []string{"get_workspace", "list_quotas", "list_usage", "list_vm_sizes"},
+			exclude: append([]string{"list_workspaces_by_subscription", "list_compute", "set_active_workspace"}, mutating...),
+		},
+		{
+			name:    "deny by category and name",
+			config:  server.Config{DenyTools: []string{"compute", "create_workspace"}},
+			include: []string{"get_workspace", "list_quotas"},
+			exclude: append([]string{"list_compute", "get_compute"}, mutating...),
+		},
+		{
+			name:    "deny wins over allow",
+			config:  server.Config{AllowTools: []string{"compute"}, DenyTools: []string{"stop_compute"}},
+			include: []string{"list_compute", "get_compute", "start_compute"},
+			exclude: []string{"stop_compute", "get_workspace"},
+		},
+		{
+			name:    "read-only with allow list",
+			config:  server.Config{ReadOnly: true, AllowTools: []string{"compute"}},
+			include: []string{"list_compute", "get_compute"},
+			exclude: []string{"start_compute", "stop_compute", "get_workspace"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.config.Name = "Test Server"
+			tt.config.Version = "1.0.0"
+			names := toolNames(t, server.New(tt.config))
+
+			for _, name := range tt.include {
+				if !slices.Contains(names, name) {
+					t.Errorf("tool %s is not registered (tools: %v)", name, names)
+				}
+			}
+			for _, name := range tt.exclude {
+				if slices.Contains(names, name) {
+					t.Errorf("tool %s is registered (tools: %v)", name, names)
+				}
+			}
+		})
+	}
+}
+
+// resourceTemplates returns the URI templates of the resources the server lists
+func resourceTemplates(t *testing.T, s *server.MCPServer) []string {
+	t.Helper()
+
+	response := s.HandleMessage(context.Background(), []byte(`{"jsonrpc":"2.0","id":1,"method":"resources/templates/list"}`))
+	data, err := json.Marshal(response)
+	if err != nil {
+		t.Fatalf("failed to marshal resources/templates/list response: %v", err)
+	}
+	var decoded struct {
+		Result struct {
+			ResourceTemplates []struct {
+				URITemplate string `json:"uriTemplate"`
+			} `json:"resourceTemplates"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("failed to decode resources/templates/list response %s: %v", data, err)
+	}
+
+	var templates []string
+	for _, template := range decoded.Result.ResourceTemplates {
+		templates = append(templates, template.URITemplate)
+	}
+	return templates
+}
+
+func TestResourcePolicy(t *testing.T) {
+	const computeTemplate = "aml://subscriptions/{subscriptionId}/resourceGroups/{resourceGroup}/workspaces/{workspace}/computes/{compute}"
+	const workspaceTemplate = "aml://subscriptions/{subscriptionId}/resourceGroups/{resourceGroup}/workspaces/{workspace}"
+
+	tests := []struct {
+		name          string
+		config        server.Config
+		wantCompute   bool
+		wantWorkspace bool
+	}{
+		{"everything by default", server.Config{}, true, true},
+		{"read-only", server.Config{ReadOnly: true}, true, true},
+		{"deny category", server.Config{DenyTools: []string{"compute"}}, false, true},
+		{"allow category", server.Config{AllowTools: []string{"compute"}}, true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.config.Name = "Test Server"
+			tt.config.Version = "1.0.0"
+			templates := resourceTemplates(t, server.New(tt.config))
+
+			if got := slices.Contains(templates, computeTemplate); got != tt.wantCompute {
+				t.Errorf("compute template registered = %v, want %v (templates: %v)", got, tt.wantCompute, templates)
+			}
+			if got := slices.Contains(templates, workspaceTemplate); got != tt.wantWorkspace {
+				t.Errorf("workspace template registered = %v, want %v (templates: %v)", got, tt.wantWorkspace, templates)
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+// Package session holds per-client state such as the active workspace
+package session
+
+import "sync"
+
+// Workspace identifies the subscription, resource group and workspace tools act on
+type Workspace struct {
+	SubscriptionID string `json:"subscriptionId,omitempty"`
+	ResourceGroup  string `json:"resourceGroup,omitempty"`
+	Workspace      string `json:"workspace,omitempty"`
+}
+
+// Merge returns w with every non-empty field of override applied on top
+func (w Workspace) Merge(override Workspace) Workspace {
+	if override.SubscriptionID != "" {
+		w.SubscriptionID = override.SubscriptionID
+	}
+	if override.ResourceGroup != "" {
+		w.ResourceGroup = override.ResourceGroup
+	}
+	if override.Workspace != "" {
+		w.Workspace = override.Workspace
+	}
+	return w
+}
+
+// IsZero reports whether no field is set
+func (w Workspace) IsZero() bool {
+	return w == Workspace{}
+}
+
+// Store holds the active workspace of each client session
+type Store struct {
+	mu         sync.RWMutex
+	workspaces map[string]Workspace
+}
+
+// NewStore creates an empty Store
+func NewStore() *Store {
+	return &Store{workspaces: make(map[string]Workspace)}
+}
+
+// Get returns the active workspace of a session, which is empty if none has been set
+func (s *Store) Get(sessionID string) Workspace {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.workspaces[sessionID]
+}
+
+// Set replaces the active workspace of a session
+func (s *Store) Set(sessionID string, workspace Workspace) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if workspace.IsZero() {
+		delete(s.workspaces, sessionID)
+		return
+	}
+	s.workspaces[sessionID] = workspace
+}
+
+// Delete forgets a session, typically when its client disconnects
+func (s *Store) Delete(sessionID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.workspaces, sessionID)
+}
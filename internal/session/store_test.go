@@ -0,0 +1,56 @@
+package session_test
+
+import (
+	"testing"
+
+	"microsoft.com/aml-mcp/internal/session"
+)
+
+func TestWorkspaceMerge(t *testing.T) {
+	base := session.Workspace{SubscriptionID: "sub-1", ResourceGroup: "rg-1", Workspace: "ws-1"}
+
+	tests := []struct {
+		name     string
+		override session.Workspace
+		expected session.Workspace
+	}{
+		{"empty override", session.Workspace{}, base},
+		{"workspace only", session.Workspace{Workspace: "ws-2"}, session.Workspace{SubscriptionID: "sub-1", ResourceGroup: "rg-1", Workspace: "ws-2"}},
+		{"everything", session.Workspace{SubscriptionID: "sub-2", ResourceGroup: "rg-2", Workspace: "ws-2"}, session.Workspace{SubscriptionID: "sub-2", ResourceGroup: "rg-2", Workspace: "ws-2"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := base.Merge(tt.override); got != tt.expected {
+				t.Errorf("Merge() = %+v, want %+v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestStore(t *testing.T) {
+	s := session.NewStore()
+
+	if got := s.Get("a"); !got.IsZero() {
+		t.Errorf("Get() on empty store = %+v, want zero", got)
+	}
+
+	s.Set("a", session.Workspace{Workspace: "ws-a"})
+	s.Set("b", session.Workspace{Workspace: "ws-b"})
+	if got := s.Get("a").Workspace; got != "ws-a" {
+		t.Errorf("Get(a).Workspace = %q, want ws-a", got)
+	}
+	if got := s.Get("b").Workspace; got != "ws-b" {
+		t.Errorf("Get(b).Workspace = %q, want ws-b", got)
+	}
+
+	s.Delete("a")
+	if got := s.Get("a"); !got.IsZero() {
+		t.Errorf("Get(a) after Delete = %+v, want zero", got)
+	}
+
+	s.Set("b", session.Workspace{})
+	if got := s.Get("b"); !got.IsZero() {
+		t.Errorf("Get(b) after clearing = %+v, want zero", got)
+	}
+}
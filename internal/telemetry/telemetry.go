@@ -0,0 +1,114 @@
+// Package telemetry sets up the OpenTelemetry tracer provider, exporting spans over OTLP/HTTP
+package telemetry
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// ServiceName identifies the server's spans in the tracing backend
+const ServiceName = "aml-mcp-server"
+
+// tracesPath is where an OTLP/HTTP collector receives spans
+const tracesPath = "/v1/traces"
+
+// Options configure tracing. Tracing is off unless Endpoint is set.
+type Options struct {
+	// Endpoint is the OTLP/HTTP endpoint spans are exported to
+	Endpoint string `json:"endpoint"`
+	// Headers are sent with every export request, e.g. for authentication
+	Headers map[string]string `json:"headers"`
+}
+
+// Enabled reports whether options turn tracing on
+func (o Options) Enabled() bool {
+	return o.Endpoint != ""
+}
+
+// Provider is the tracer provider spans are created with
+type Provider struct {
+	trace.TracerProvider
+	sdk *sdktrace.TracerProvider
+}
+
+// New creates the tracer provider for options
+func New(options Options, version string) (*Provider, error) {
+	if !options.Enabled() {
+		return Disabled(), nil
+	}
+	endpoint, err := endpointURL(options.Endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	exporterOptions := []otlptracehttp.Option{otlptracehttp.WithEndpointURL(endpoint)}
+	if len(options.Headers) > 0 {
+		exporterOptions = append(exporterOptions, otlptracehttp.WithHeaders(options.Headers))
+	}
+	// The exporter connects lazily
+	exporter, err := otlptracehttp.New(context.Background(), exporterOptions...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP exporter: %v", err)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(
+		attribute.String("service.name", ServiceName),
+		attribute.String("service.version", version),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe the service: %v", err)
+	}
+
+	sdk := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+	return &Provider{TracerProvider: sdk, sdk: sdk}, nil
+}
+
+// Disabled returns a provider that records nothing
+func Disabled() *Provider {
+	return &Provider{TracerProvider: noop.NewTracerProvider()}
+}
+
+// Shutdown exports the spans still buffered and stops the provider
+func (p *Provider) Shutdown(ctx context.Context) error {
+	if p.sdk == nil {
+		return nil
+	}
+	if err := p.sdk.Shutdown(ctx); err != nil {
+		return fmt.Errorf("failed to export traces: %v", err)
+	}
+	return nil
+}
+
+// TracesEndpoint returns the traces endpoint of an OTLP/HTTP collector base URL
+func TracesEndpoint(base string) string {
+	u, err := url.Parse(base)
+	if err != nil {
+		return base
+	}
+	u.Path = strings.TrimSuffix(u.Path, "/") + tracesPath
+	return u.String()
+}
+
+// endpointURL validates endpoint, adding the OTLP traces path when it has none
+func endpointURL(endpoint string) (string, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", fmt.Errorf("invalid OTLP endpoint %q (expected an http or https URL such as http://localhost:4318)", endpoint)
+	}
+	if strings.Trim(u.Path, "/") == "" {
+		u.Path = tracesPath
+	}
+	return u.String(), nil
+}
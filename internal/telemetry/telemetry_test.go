@@ -0,0 +1,137 @@
+package telemetry_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	"google.golang.org/protobuf/proto"
+	"microsoft.com/aml-mcp/internal/telemetry"
+)
+
+// collector is a local OTLP/HTTP collector recording the spans exported to it
+type collector struct {
+	*httptest.Server
+
+	mu      sync.Mutex
+	paths   []string
+	headers []http.Header
+	spans   []string
+	service string
+}
+
+func newCollector(t *testing.T) *collector {
+	t.Helper()
+	c := &collector{}
+	c.Server = httptest.NewServer(http.HandlerFunc(c.export))
+	t.Cleanup(c.Close)
+	return c
+}
+
+func (c *collector) export(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	var request coltracepb.ExportTraceServiceRequest
+	if err == nil {
+		err = proto.Unmarshal(body, &request)
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.paths = append(c.paths, r.URL.Path)
+	c.headers = append(c.headers, r.Header.Clone())
+	for _, resourceSpans := range request.ResourceSpans {
+		for _, attr := range resourceSpans.GetResource().GetAttributes() {
+			if attr.Key == "service.name" {
+				c.service = attr.GetValue().GetStringValue()
+			}
+		}
+		for _, scopeSpans := range resourceSpans.ScopeSpans {
+			for _, span := range scopeSpans.Spans {
+				c.spans = append(c.spans, span.Name)
+			}
+		}
+	}
+	w.Header().Set("Content-Type", "application/x-protobuf")
+	response, _ := proto.Marshal(&coltracepb.ExportTraceServiceResponse{})
+	_, _ = w.Write(response)
+}
+
+func TestExport(t *testing.T) {
+	c := newCollector(t)
+
+	provider, err := telemetry.New(telemetry.Options{
+		Endpoint: c.URL,
+		Headers:  map[string]string{"Authorization": "Bearer collector-token"},
+	}, "1.2.3")
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	_, span := provider.Tracer("test").Start(context.Background(), "tools/call get_workspace")
+	span.End()
+
+	// Spans are batched until the provider shuts down
+	if err := provider.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown() error = %v", err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.spans) != 1 || c.spans[0] != "tools/call get_workspace" {
+		t.Fatalf("collector received spans %v, want the tool call", c.spans)
+	}
+	if c.paths[0] != "/v1/traces" {
+		t.Errorf("export path = %q, want /v1/traces", c.paths[0])
+	}
+	if got := c.headers[0].Get("Authorization"); got != "Bearer collector-token" {
+		t.Errorf("Authorization header = %q, want the configured header", got)
+	}
+	if c.service != telemetry.ServiceName {
+		t.Errorf("service.name = %q, want %q", c.service, telemetry.ServiceName)
+	}
+}
+
+func TestDisabled(t *testing.T) {
+	provider, err := telemetry.New(telemetry.Options{}, "1.2.3")
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	_, span := provider.Tracer("test").Start(context.Background(), "tools/call get_workspace")
+	if span.IsRecording() {
+		t.Error("span is recorded with tracing off")
+	}
+	if err := provider.Shutdown(context.Background()); err != nil {
+		t.Errorf("Shutdown() error = %v", err)
+	}
+}
+
+func TestInvalidEndpoint(t *testing.T) {
+	for _, endpoint := range []string{"localhost:4318", "grpc://localhost:4317", "http://"} {
+		_, err := telemetry.New(telemetry.Options{Endpoint: endpoint}, "1.2.3")
+		if err == nil || !strings.Contains(err.Error(), "invalid OTLP endpoint") {
+			t.Errorf("New(%q) error = %v, want an invalid endpoint error", endpoint, err)
+		}
+	}
+}
+
+func TestTracesEndpoint(t *testing.T) {
+	tests := map[string]string{
+		"http://localhost:4318": "http://localhost:4318/v1/traces",
+		"https://gw/otlp":       "https://gw/otlp/v1/traces",
+		"https://gw/otlp/?x=1":  "https://gw/otlp/v1/traces?x=1",
+		"localhost:4318":        "localhost:4318",
+	}
+	for base, want := range tests {
+		if got := telemetry.TracesEndpoint(base); got != want {
+			t.Errorf("TracesEndpoint(%q) = %q, want %q", base, got, want)
+		}
+	}
+}
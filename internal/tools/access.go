@@ -0,0 +1,537 @@
+package tools
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/authorization/armauthorization/v2"
+	"github.com/mark3labs/mcp-go/mcp"
+	"microsoft.com/aml-mcp/internal/azure"
+	"microsoft.com/aml-mcp/internal/operations"
+)
+
+// Arguments of the role assignment tools
+const (
+	argPrincipalID      = "principal_id"
+	argPrincipalType    = "principal_type"
+	argRole             = "role"
+	argRoleAssignmentID = "role_assignment_id"
+)
+
+// workspaceRoles are the built-in roles commonly assigned on Azure ML workspaces
+var workspaceRoles = []struct {
+	name string
+	id   string
+}{
+	{"AzureML Data Scientist", "f6c7c914-8db3-469d-8ca1-694a8f32e121"},
+	{"AzureML Compute Operator", "e503ece1-11d0-4e8e-8e2c-7a6c3bf38815"},
+	{"AzureML Registry User", "1823dd4f-9b8c-4ab6-ab4e-7397a3684615"},
+	{"AzureML Metrics Writer (preview)", "635dd51f-9968-44d3-b7fb-6d9a6bd613ae"},
+	{"Azure Machine Learning Workspace Connection Secrets Reader", "ea01e6af-a1c1-4350-9563-ad00f8c72ec5"},
+	{"Azure AI Developer", "64702f94-c441-49e6-a78b-ef80e0188fee"},
+	{"Reader", "acdd72a7-3385-48ef-bd42-f606fba81ae7"},
+	{"Contributor", "b24988ac-6180-42a0-ab88-20f7382dd24c"},
+	{"Owner", "8e3af657-a8ff-443c-a75c-2fe8c4bcb635"},
+}
+
+var (
+	guidPattern    = regexp.MustCompile(`^[0-9a-fA-F]{8}-([0-9a-fA-F]{4}-){3}[0-9a-fA-F]{12}$`)
+	errUnknownRole = fmt.Errorf("%w: unknown role", errInvalidArgument)
+)
+
+// AccessTools contains the tools for managing workspace role assignments
+type AccessTools struct {
+	clients *azure.ClientCache
+	shared
+}
+
+// NewAccessTools creates a new AccessTools instance backed by a shared client cache
+func NewAccessTools(clients *azure.ClientCache, opts ...Option) *AccessTools {
+	return &AccessTools{clients: clients, shared: newShared(opts)}
+}
+
+// AddToServer registers all access tools with the MCP server
+func (at *AccessTools) AddToServer(s Registrar) {
+	at.addListRoleAssignmentsTool(s)
+	at.addGrantRoleTool(s)
+	at.addRevokeRoleTool(s)
+}
+
+func (at *AccessTools) addListRoleAssignmentsTool(s Registrar) {
+	tool := mcp.NewTool("list_workspace_role_assignments",
+		mcp.WithDescription("List who has access to an Azure ML workspace: the role assignments on the workspace, those inherited from its resource group and subscription, and those on resources in it. "+
+			"Principals are reported by object ID."),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOutputSchema[RoleAssignmentList](),
+		mcp.WithString(argResourceID,
+			mcp.Description("Full ARM resource ID of the workspace, as returned by get_workspace. Takes precedence over subscription_id, resource_group_name and workspace_name"),
+		),
+		mcp.WithString(argSubscriptionID,
+			mcp.Description("Azure subscription ID. Defaults to the active workspace's subscription"),
+		),
+		mcp.WithString(argResourceGroup,
+			mcp.Description("Resource group name. Defaults to the active workspace's resource group"),
+		),
+		mcp.WithString(argWorkspace,
+			mcp.Description("Workspace name. Defaults to the active workspace"),
+		),
+		mcp.WithString(argPrincipalID,
+			mcp.Description("Only list the role assignments of this user, group or service principal object ID"),
+		),
+	)
+
+	s.AddTool(tool, at.handleListRoleAssignments)
+}
+
+func (at *AccessTools) addGrantRoleTool(s Registrar) {
+	tool := mcp.NewTool("grant_workspace_role",
+		mcp.WithDescription("Grant a user, group or service principal a role on an Azure ML workspace by creating a role assignment scoped to the workspace. "+
+			"Granting a role the principal already has on the workspace changes nothing. "+
+			"Dry runs and confirmation prompts still read the role definitions from Azure to resolve role."),
+		// Widens what the principal may do, which is hard to undo safely
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOutputSchema[RoleAssignmentChange](),
+		mcp.WithString(argResourceID,
+			mcp.Description("Full ARM resource ID of the workspace, as returned by get_workspace. Takes precedence over subscription_id, resource_group_name and workspace_name"),
+		),
+		mcp.WithString(argSubscriptionID,
+			mcp.Description("Azure subscription ID. Defaults to the active workspace's subscription"),
+		),
+		mcp.WithString(argResourceGroup,
+			mcp.Description("Resource group name. Defaults to the active workspace's resource group"),
+		),
+		mcp.WithString(argWorkspace,
+			mcp.Description("Workspace name. Defaults to the active workspace"),
+		),
+		mcp.WithString(argPrincipalID,
+			mcp.Required(),
+			mcp.Description("Object ID of the user, group or service principal"),
+		),
+		mcp.WithString(argPrincipalType,
+			mcp.Description("Type of the principal. Setting it avoids failures for principals created moments ago"),
+			mcp.Enum(string(armauthorization.PrincipalTypeUser), string(armauthorization.PrincipalTypeGroup), string(armauthorization.PrincipalTypeServicePrincipal)),
+		),
+		mcp.WithString(argRole,
+			mcp.Required(),
+			mcp.Description(roleDescription),
+		),
+		mcp.WithBoolean(argDryRun,
+			mcp.Description(dryRunDescription),
+		),
+		mcp.WithString(argConfirmToken,
+			mcp.Description(confirmTokenDescription),
+		),
+	)
+
+	s.AddTool(tool, at.handleGrantRole)
+}
+
+func (at *AccessTools) addRevokeRoleTool(s Registrar) {
+	tool := mcp.NewTool("revoke_workspace_role",
+		mcp.WithDescription("Revoke a role assignment on an Azure ML workspace, identified by role_assignment_id from list_workspace_role_assignments or by principal_id and role. "+
+			"Only assignments scoped to the workspace itself can be revoked; inherited ones must be removed where they were made. "+
+			"Given principal_id and role, dry runs and confirmation prompts still read the role definitions and the workspace's role assignments from Azure to find the assignment."),
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithOutputSchema[RoleAssignmentChange](),
+		mcp.WithString(argResourceID,
+			mcp.Description("Full ARM resource ID of the workspace, as returned by get_workspace. Takes precedence over subscription_id, resource_group_name and workspace_name"),
+		),
+		mcp.WithString(argSubscriptionID,
+			mcp.Description("Azure subscription ID. Defaults to the active workspace's subscription"),
+		),
+		mcp.WithString(argResourceGroup,
+			mcp.Description("Resource group name. Defaults to the active workspace's resource group"),
+		),
+		mcp.WithString(argWorkspace,
+			mcp.Description("Workspace name. Defaults to the active workspace"),
+		),
+		mcp.WithString(argRoleAssignmentID,
+			mcp.Description("Name or full ARM ID of the role assignment to revoke, as returned by list_workspace_role_assignments"),
+		),
+		mcp.WithString(argPrincipalID,
+			mcp.Description("Object ID of the principal whose role to revoke, when role_assignment_id is not given"),
+		),
+		mcp.WithString(argRole,
+			mcp.Description("Role to revoke from principal_id, when role_assignment_id is not given. "+roleDescription),
+		),
+		mcp.WithBoolean(argDryRun,
+			mcp.Description(dryRunDescription),
+		),
+		mcp.WithString(argConfirmToken,
+			mcp.Description(confirmTokenDescription),
+		),
+	)
+
+	s.AddTool(tool, at.handleRevokeRole)
+}
+
+// roleDescription describes the role argument
+var roleDescription = "Role name, role definition GUID or role definition ID. Built-in roles for workspaces include " +
+	roleNameList() + "; custom roles are looked up by name"
+
+// workspaceScope resolves the workspace arguments of a call
+func (at *AccessTools) workspaceScope(ctx context.Context, request mcp.CallToolRequest) (subscriptionID string, scope permissionScope, err error) {
+	if subscriptionID, err = at.scopeArgument(ctx, request, argSubscriptionID); err != nil {
+		return "", scope, err
+	}
+	if scope.ResourceGroup, err = at.scopeArgument(ctx, request, argResourceGroup); err != nil {
+		return "", scope, err
+	}
+	if scope.Workspace, err = at.scopeArgument(ctx, request, argWorkspace); err != nil {
+		return "", scope, err
+	}
+	return subscriptionID, scope, nil
+}
+
+func (at *AccessTools) handleListRoleAssignments(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	subscriptionID, scope, err := at.workspaceScope(ctx, request)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	principalID := request.GetString(argPrincipalID, "")
+
+	clients, err := at.clients.Get(ctx, subscriptionID)
+	if err != nil {
+		return azureError(at.clients, "Failed to sign in to Azure", err), nil
+	}
+
+	workspaceID := scope.resourceID(subscriptionID)
+	assignments, err := listRoleAssignments(ctx, clients, workspaceID)
+	if err != nil {
+		return azureError(at.clients, "Failed to list role assignments", err), nil
+	}
+
+	names := roleNames(ctx, clients, assignments)
+	result := RoleAssignmentList{Scope: workspaceID, RoleAssignments: []RoleAssignment{}}
+	lines := make([]string, 0, len(assignments))
+	for _, a := range assignments {
+		assignment := newRoleAssignment(a, workspaceID, names)
+		if principalID != "" && !strings.EqualFold(assignment.PrincipalID, principalID) {
+			continue
+		}
+		result.RoleAssignments = append(result.RoleAssignments, assignment)
+
+		where := "on the workspace"
+		if assignment.Inherited {
+			where = "inherited from " + assignment.Scope
+		} else if !strings.EqualFold(assignment.Scope, workspaceID) {
+			where = "on " + assignment.Scope
+		}
+		lines = append(lines, fmt.Sprintf("Role: %s, Principal: %s (%s), %s, ID: %s",
+			orNA(assignment.RoleName), assignment.PrincipalID, orNA(assignment.PrincipalType), where, assignment.Name))
+	}
+	result.Count = len(result.RoleAssignments)
+
+	if result.Count == 0 {
+		return mcp.NewToolResultStructured(result, fmt.Sprintf("No role assignments found for %s.", scope.describe())), nil
+	}
+	return mcp.NewToolResultStructured(result, fmt.Sprintf("Found %d role assignments for %s:\n%s",
+		result.Count, scope.describe(), strings.Join(lines, "\n"))), nil
+}
+
+func (at *AccessTools) handleGrantRole(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	subscriptionID, scope, err := at.workspaceScope(ctx, request)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	principalID, err := request.RequireString(argPrincipalID)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	role, err := request.RequireString(argRole)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	workspaceID := scope.resourceID(subscriptionID)
+	roleID, roleName, err := resolveRole(ctx, at.clients, subscriptionID, workspaceID, role)
+	if err != nil {
+		return azureError(at.clients, "Failed to look up role", err), nil
+	}
+
+	name := roleAssignmentName(workspaceID, principalID, roleID)
+	parameters := armauthorization.RoleAssignmentCreateParameters{
+		Properties: &armauthorization.RoleAssignmentProperties{
+			PrincipalID:      to.Ptr(principalID),
+			RoleDefinitionID: to.Ptr(roleID),
+		},
+	}
+	if principalType := request.GetString(argPrincipalType, ""); principalType != "" {
+		parameters.Properties.PrincipalType = to.Ptr(armauthorization.PrincipalType(principalType))
+	}
+
+	clients, err := at.clients.Get(ctx, subscriptionID)
+	if err != nil {
+		return azureError(at.clients, "Failed to sign in to Azure", err), nil
+	}
+	if denied := preflight(ctx, request, at.clients, clients, scope); denied != nil {
+		return denied, nil
+	}
+
+	plan, failed := at.planMutation(ctx, request, at.clients, subscriptionID,
+		fmt.Sprintf("Grant role '%s' on workspace '%s' to principal '%s'", orNA(roleName), scope.Workspace, principalID),
+		func(clients *azure.ClientSet) error {
+			_, err := clients.RoleAssignmentsClient.Create(ctx, extensionScope(workspaceID), name, parameters, nil)
+			return err
+		})
+	if failed != nil {
+		return failed, nil
+	}
+	if plan != nil {
+		return mcp.NewToolResultStructured(RoleAssignmentChange{Status: plan.status(), Plan: plan}, plan.text()), nil
+	}
+
+	resp, err := clients.RoleAssignmentsClient.Create(ctx, extensionScope(workspaceID), name, parameters, nil)
+	if details, ok := azure.ParseResponseError(err); ok && strings.EqualFold(details.Code, "RoleAssignmentExists") {
+		// Assigned before, outside this server, under another name
+		return mcp.NewToolResultStructured(RoleAssignmentChange{Status: string(operations.StatusSucceeded)},
+			fmt.Sprintf("Principal '%s' already has role '%s' on workspace '%s'.", principalID, orNA(roleName), scope.Workspace)), nil
+	}
+	if err != nil {
+		return azureError(at.clients, "Failed to grant role", err), nil
+	}
+
+	assignment := newRoleAssignment(&resp.RoleAssignment, workspaceID, map[string]string{roleGUID(roleID): roleName})
+	return mcp.NewToolResultStructured(RoleAssignmentChange{Status: string(operations.StatusSucceeded), RoleAssignment: &assignment},
+		fmt.Sprintf("Granted role '%s' on workspace '%s' to principal '%s'. Role assignment: %s",
+			orNA(roleName), scope.Workspace, principalID, assignment.Name)), nil
+}
+
+func (at *AccessTools) handleRevokeRole(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	subscriptionID, scope, err := at.workspaceScope(ctx, request)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	workspaceID := scope.resourceID(subscriptionID)
+
+	var name string
+	if id := request.GetString(argRoleAssignmentID, ""); id != "" {
+		name = id
+		if strings.Contains(id, "/") {
+			prefix := extensionID(workspaceID, "roleAssignments", "")
+			if !strings.HasPrefix(strings.ToLower(id), strings.ToLower(prefix)) {
+				return mcp.NewToolResultError(fmt.Sprintf("role assignment %s is not scoped to %s: revoke it at the scope it was made at", id, scope.describe())), nil
+			}
+			name = id[len(prefix):]
+		}
+		if !guidPattern.MatchString(name) {
+			return toolErrorResult("Invalid role assignment", ToolError{
+				Code:    ErrorInvalidArgument,
+				Message: fmt.Sprintf("role assignment %q is neither a GUID nor the ID of an assignment on %s", id, scope.describe()),
+				Hint:    "list_workspace_role_assignments shows the names and IDs of the role assignments that exist.",
+			}), nil
+		}
+	} else {
+		principalID := request.GetString(argPrincipalID, "")
+		role := request.GetString(argRole, "")
+		if principalID == "" || role == "" {
+			return mcp.NewToolResultError(fmt.Sprintf("%s, or %s and %s, are required", argRoleAssignmentID, argPrincipalID, argRole)), nil
+		}
+		var result *mcp.CallToolResult
+		if name, result = at.findRoleAssignment(ctx, subscriptionID, scope, principalID, role); result != nil {
+			return result, nil
+		}
+	}
+
+	clients, err := at.clients.Get(ctx, subscriptionID)
+	if err != nil {
+		return azureError(at.clients, "Failed to sign in to Azure", err), nil
+	}
+	if denied := preflight(ctx, request, at.clients, clients, scope); denied != nil {
+		return denied, nil
+	}
+
+	plan, failed := at.planMutation(ctx, request, at.clients, subscriptionID,
+		fmt.Sprintf("Revoke role assignment '%s' on workspace '%s'", name, scope.Workspace),
+		func(clients *azure.ClientSet) error {
+			_, err := clients.RoleAssignmentsClient.Delete(ctx, extensionScope(workspaceID), name, nil)
+			return err
+		})
+	if failed != nil {
+		return failed, nil
+	}
+	if plan != nil {
+		return mcp.NewToolResultStructured(RoleAssignmentChange{Status: plan.status(), Plan: plan}, plan.text()), nil
+	}
+
+	resp, err := clients.RoleAssignmentsClient.Delete(ctx, extensionScope(workspaceID), name, nil)
+	if err != nil {
+		return azureError(at.clients, "Failed to revoke role", err), nil
+	}
+	// Deleting an assignment that does not exist succeeds with no content
+	if resp.ID == nil {
+		return toolErrorResult("Failed to revoke role", ToolError{
+			Code:    ErrorNotFound,
+			Message: fmt.Sprintf("there is no role assignment '%s' on %s", name, scope.describe()),
+			Hint:    "list_workspace_role_assignments shows the role assignments that exist.",
+		}), nil
+	}
+
+	assignment := newRoleAssignment(&resp.RoleAssignment, workspaceID, roleNames(ctx, clients, []*armauthorization.RoleAssignment{&resp.RoleAssignment}))
+	return mcp.NewToolResultStructured(RoleAssignmentChange{Status: string(operations.StatusSucceeded), RoleAssignment: &assignment},
+		fmt.Sprintf("Revoked role '%s' on workspace '%s' from principal '%s'.", orNA(assignment.RoleName), scope.Workspace, assignment.PrincipalID)), nil
+}
+
+// findRoleAssignment returns the name of the assignment of role to principalID on the workspace
+func (at *AccessTools) findRoleAssignment(ctx context.Context, subscriptionID string, scope permissionScope, principalID, role string) (string, *mcp.CallToolResult) {
+	workspaceID := scope.resourceID(subscriptionID)
+	roleID, roleName, err := resolveRole(ctx, at.clients, subscriptionID, workspaceID, role)
+	if err != nil {
+		return "", azureError(at.clients, "Failed to look up role", err)
+	}
+
+	clients, err := at.clients.Get(ctx, subscriptionID)
+	if err != nil {
+		return "", azureError(at.clients, "Failed to sign in to Azure", err)
+	}
+	assignments, err := listRoleAssignments(ctx, clients, workspaceID)
+	if err != nil {
+		return "", azureError(at.clients, "Failed to list role assignments", err)
+	}
+
+	var elsewhere []string
+	for _, a := range assignments {
+		if a.Name == nil || a.Properties == nil || !strings.EqualFold(valueOf(a.Properties.PrincipalID), principalID) ||
+			!strings.EqualFold(roleGUID(valueOf(a.Properties.RoleDefinitionID)), roleGUID(roleID)) {
+			continue
+		}
+		if strings.EqualFold(valueOf(a.Properties.Scope), workspaceID) {
+			return *a.Name, nil
+		}
+		elsewhere = append(elsewhere, valueOf(a.Properties.Scope))
+	}
+	if len(elsewhere) > 0 {
+		return "", mcp.NewToolResultError(fmt.Sprintf("principal '%s' has role '%s' through an assignment on %s, not on the workspace: revoke it there",
+			principalID, orNA(roleName), strings.Join(elsewhere, ", ")))
+	}
+	return "", toolErrorResult("Failed to revoke role", ToolError{
+		Code:    ErrorNotFound,
+		Message: fmt.Sprintf("principal '%s' has no role '%s' on %s", principalID, orNA(roleName), scope.describe()),
+		Hint:    "list_workspace_role_assignments shows the role assignments that exist.",
+	})
+}
+
+// listRoleAssignments lists the role assignments that apply to scope
+func listRoleAssignments(ctx context.Context, clients *azure.ClientSet, scope string) ([]*armauthorization.RoleAssignment, error) {
+	var assignments []*armauthorization.RoleAssignment
+	pager := clients.RoleAssignmentsClient.NewListForScopePager(extensionScope(scope), nil)
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		assignments = append(assignments, page.Value...)
+	}
+	return assignments, nil
+}
+
+// resolveRole returns the role definition ID and, when known, the name of a role name, GUID or ID
+func resolveRole(ctx context.Context, cache *azure.ClientCache, subscriptionID, scope, role string) (id, name string, err error) {
+	switch {
+	case strings.Contains(role, "/"):
+		return role, builtInRoleName(roleGUID(role)), nil
+	case guidPattern.MatchString(role):
+		return roleDefinitionID(subscriptionID, role), builtInRoleName(role), nil
+	}
+	for _, builtIn := range workspaceRoles {
+		if strings.EqualFold(builtIn.name, role) {
+			return roleDefinitionID(subscriptionID, builtIn.id), builtIn.name, nil
+		}
+	}
+
+	clients, err := cache.Get(ctx, subscriptionID)
+	if err != nil {
+		return "", "", err
+	}
+	filter := fmt.Sprintf("roleName eq '%s'", strings.ReplaceAll(role, "'", "''"))
+	pager := clients.RoleDefinitionsClient.NewListPager(extensionScope(scope), &armauthorization.RoleDefinitionsClientListOptions{Filter: &filter})
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return "", "", err
+		}
+		for _, definition := range page.Value {
+			if definition.ID != nil && definition.Properties != nil {
+				return *definition.ID, valueOf(definition.Properties.RoleName), nil
+			}
+		}
+	}
+	return "", "", fmt.Errorf("%w %q: pass a role definition ID, or one of the built-in roles %s", errUnknownRole, role, roleNameList())
+}
+
+// roleNames maps the role definition GUIDs of assignments to role names
+func roleNames(ctx context.Context, clients *azure.ClientSet, assignments []*armauthorization.RoleAssignment) map[string]string {
+	names := make(map[string]string)
+	for _, a := range assignments {
+		if a.Properties == nil || a.Properties.RoleDefinitionID == nil {
+			continue
+		}
+		id := *a.Properties.RoleDefinitionID
+		guid := roleGUID(id)
+		if _, ok := names[guid]; ok {
+			continue
+		}
+		if name := builtInRoleName(guid); name != "" {
+			names[guid] = name
+			continue
+		}
+		names[guid] = ""
+		if resp, err := clients.RoleDefinitionsClient.GetByID(ctx, extensionScope(id), nil); err == nil && resp.Properties != nil {
+			names[guid] = valueOf(resp.Properties.RoleName)
+		}
+	}
+	return names
+}
+
+func builtInRoleName(guid string) string {
+	for _, role := range workspaceRoles {
+		if strings.EqualFold(role.id, guid) {
+			return role.name
+		}
+	}
+	return ""
+}
+
+func roleNameList() string {
+	names := make([]string, 0, len(workspaceRoles))
+	for _, role := range workspaceRoles {
+		names = append(names, "'"+role.name+"'")
+	}
+	return strings.Join(names, ", ")
+}
+
+// roleGUID returns the lower-cased GUID a role definition ID ends with
+func roleGUID(id string) string {
+	return strings.ToLower(id[strings.LastIndex(id, "/")+1:])
+}
+
+// roleDefinitionID builds the ID of a role definition in a subscription
+func roleDefinitionID(subscriptionID, guid string) string {
+	return fmt.Sprintf("/subscriptions/%s/providers/Microsoft.Authorization/roleDefinitions/%s", subscriptionID, guid)
+}
+
+// extensionID builds the ID of a Microsoft.Authorization resource on scope
+func extensionID(scope, resourceType, name string) string {
+	return fmt.Sprintf("%s/providers/Microsoft.Authorization/%s/%s", scope, resourceType, name)
+}
+
+// extensionScope adapts a resource ID to the scope parameters of the authorization clients
+func extensionScope(id string) string {
+	return strings.TrimPrefix(id, "/")
+}
+
+// roleAssignmentName derives a stable GUID naming the assignment of a role to a principal on scope
+func roleAssignmentName(scope, principalID, roleID string) string {
+	sum := sha256.Sum256([]byte(strings.ToLower(scope + "\n" + principalID + "\n" + roleGUID(roleID))))
+	// Set the version 5 and RFC 4122 variant bits
+	sum[6] = sum[6]&0x0f | 0x50
+	sum[8] = sum[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", sum[0:4], sum[4:6], sum[6:8], sum[8:10], sum[10:16])
+}
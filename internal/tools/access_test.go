@@ -0,0 +1,248 @@
+package tools_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/authorization/armauthorization/v2"
+	"github.com/mark3labs/mcp-go/server"
+	"microsoft.com/aml-mcp/internal/confirm"
+	"microsoft.com/aml-mcp/internal/fakearm"
+	"microsoft.com/aml-mcp/internal/tools"
+)
+
+const (
+	dataScientistRoleID = "f6c7c914-8db3-469d-8ca1-694a8f32e121"
+	readerRoleID        = "acdd72a7-3385-48ef-bd42-f606fba81ae7"
+	customRoleID        = "11111111-2222-3333-4444-555555555555"
+	testUserID          = "00000000-0000-0000-0000-0000000000c1"
+	testGroupID         = "00000000-0000-0000-0000-0000000000c2"
+
+	// Names of the role assignments newAccessServer seeds
+	workspaceAssignment      = "00000000-0000-0000-0000-0000000000a1"
+	resourceGroupAssignment  = "00000000-0000-0000-0000-0000000000a2"
+	otherWorkspaceAssignment = "00000000-0000-0000-0000-0000000000a3"
+)
+
+var (
+	testWorkspaceID     = fakearm.WorkspaceID(testSubscriptionID, testResourceGroup, testWorkspace)
+	testResourceGroupID = "/subscriptions/" + testSubscriptionID + "/resourceGroups/" + testResourceGroup
+)
+
+// newAccessServer starts a fake ARM server with workspace role assignments and a custom role
+func newAccessServer(t *testing.T, opts ...tools.Option) (*fakearm.Server, *server.MCPServer) {
+	t.Helper()
+
+	fake := newFakeARM(t)
+	fake.AddRoleDefinition(armauthorization.RoleDefinition{
+		Name:       to.Ptr(customRoleID),
+		Properties: &armauthorization.RoleDefinitionProperties{RoleName: to.Ptr("ML Viewer"), RoleType: to.Ptr("CustomRole")},
+	})
+	fake.AddRoleAssignment(testWorkspaceID, armauthorization.RoleAssignment{
+		Name: to.Ptr(workspaceAssignment),
+		Properties: &armauthorization.RoleAssignmentProperties{
+			PrincipalID:      to.Ptr(testUserID),
+			PrincipalType:    to.Ptr(armauthorization.PrincipalTypeUser),
+			RoleDefinitionID: to.Ptr(fakearm.RoleDefinitionID(testSubscriptionID, dataScientistRoleID)),
+		},
+	})
+	fake.AddRoleAssignment(testResourceGroupID, armauthorization.RoleAssignment{
+		Name: to.Ptr(resourceGroupAssignment),
+		Properties: &armauthorization.RoleAssignmentProperties{
+			PrincipalID:      to.Ptr(testGroupID),
+			PrincipalType:    to.Ptr(armauthorization.PrincipalTypeGroup),
+			RoleDefinitionID: to.Ptr(fakearm.RoleDefinitionID(testSubscriptionID, readerRoleID)),
+		},
+	})
+	fake.AddRoleAssignment(fakearm.WorkspaceID(testSubscriptionID, testResourceGroup, "other-ws"), armauthorization.RoleAssignment{
+		Name: to.Ptr(otherWorkspaceAssignment),
+		Properties: &armauthorization.RoleAssignmentProperties{
+			PrincipalID:      to.Ptr(testUserID),
+			RoleDefinitionID: to.Ptr(fakearm.RoleDefinitionID(testSubscriptionID, customRoleID)),
+		},
+	})
+
+	s := server.NewMCPServer("test", "1.0.0")
+	tools.NewAccessTools(fake.ClientCache(), opts...).AddToServer(s)
+	return fake, s
+}
+
+func TestListWorkspaceRoleAssignments(t *testing.T) {
+	_, s := newAccessServer(t)
+
+	result := callTool(t, s, "list_workspace_role_assignments", map[string]any{"resource_id": testWorkspaceID})
+	if result.IsError {
+		t.Fatalf("list_workspace_role_assignments failed: %s", resultText(result))
+	}
+	list := structuredAs[tools.RoleAssignmentList](t, result)
+	if list.Scope != testWorkspaceID || list.Count != 2 {
+		t.Fatalf("list = %+v, want the workspace's own and inherited assignments", list)
+	}
+	byName := make(map[string]tools.RoleAssignment)
+	for _, assignment := range list.RoleAssignments {
+		byName[assignment.Name] = assignment
+	}
+	if own := byName[workspaceAssignment]; own.RoleName != "AzureML Data Scientist" || own.Inherited || own.PrincipalType != "User" {
+		t.Errorf("workspace assignment = %+v, want a direct AzureML Data Scientist assignment", own)
+	}
+	if inherited := byName[resourceGroupAssignment]; inherited.RoleName != "Reader" || !inherited.Inherited {
+		t.Errorf("resource group assignment = %+v, want an inherited Reader assignment", inherited)
+	}
+	if text := resultText(result); !strings.Contains(text, "inherited from "+testResourceGroupID) {
+		t.Errorf("text = %q, want the inherited assignment's scope", text)
+	}
+
+	filtered := structuredAs[tools.RoleAssignmentList](t, callTool(t, s, "list_workspace_role_assignments", workspaceArgs(map[string]any{"principal_id": testGroupID})))
+	if filtered.Count != 1 || filtered.RoleAssignments[0].PrincipalID != testGroupID {
+		t.Errorf("filtered list = %+v, want only the group's assignment", filtered)
+	}
+}
+
+func TestGrantWorkspaceRole(t *testing.T) {
+	fake, s := newAccessServer(t)
+
+	tests := []struct {
+		name     string
+		role     string
+		wantRole string
+	}{
+		{name: "built-in role", role: "azureml compute operator", wantRole: "e503ece1-11d0-4e8e-8e2c-7a6c3bf38815"},
+		{name: "custom role", role: "ML Viewer", wantRole: customRoleID},
+		{name: "role definition ID", role: fakearm.RoleDefinitionID(testSubscriptionID, readerRoleID), wantRole: readerRoleID},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := workspaceArgs(map[string]any{"principal_id": testGroupID, "principal_type": "Group", "role": tt.role})
+			var names []string
+			// Granting twice assigns the role once
+			for range 2 {
+				result := callTool(t, s, "grant_workspace_role", args)
+				if result.IsError {
+					t.Fatalf("grant_workspace_role failed: %s", resultText(result))
+				}
+				change := structuredAs[tools.RoleAssignmentChange](t, result)
+				if change.Status != "Succeeded" || change.RoleAssignment == nil {
+					t.Fatalf("change = %+v, want a created role assignment", change)
+				}
+				names = append(names, change.RoleAssignment.Name)
+			}
+			if names[0] != names[1] {
+				t.Errorf("repeated grants created assignments %v, want one", names)
+			}
+
+			assignment, ok := fake.RoleAssignment(testWorkspaceID, names[0])
+			if !ok {
+				t.Fatalf("role assignment %s was not created on the workspace", names[0])
+			}
+			if got := *assignment.Properties.RoleDefinitionID; !strings.HasSuffix(got, "/"+tt.wantRole) {
+				t.Errorf("role definition = %s, want %s", got, tt.wantRole)
+			}
+			if *assignment.Properties.PrincipalType != armauthorization.PrincipalTypeGroup {
+				t.Errorf("principal type = %s, want Group", *assignment.Properties.PrincipalType)
+			}
+		})
+	}
+
+	// The Data Scientist assignment was made outside the server, under another name
+	result := callTool(t, s, "grant_workspace_role", workspaceArgs(map[string]any{"principal_id": testUserID, "role": "AzureML Data Scientist"}))
+	if result.IsError || !strings.Contains(resultText(result), "already has role 'AzureML Data Scientist'") {
+		t.Errorf("granting an existing role = %s, want it reported as already assigned", resultText(result))
+	}
+
+	result = callTool(t, s, "grant_workspace_role", workspaceArgs(map[string]any{"principal_id": testUserID, "role": "Data Wizard"}))
+	if !result.IsError || !strings.Contains(resultText(result), "'AzureML Data Scientist'") {
+		t.Errorf("granting an unknown role = %s, want an error listing the built-in roles", resultText(result))
+	}
+	if got := toolError(t, result); got.Code != tools.ErrorInvalidArgument {
+		t.Errorf("granting an unknown role error code = %s, want %s", got.Code, tools.ErrorInvalidArgument)
+	}
+}
+
+func TestGrantWorkspaceRoleDryRun(t *testing.T) {
+	fake, s := newAccessServer(t)
+
+	result := callTool(t, s, "grant_workspace_role", workspaceArgs(map[string]any{"principal_id": testGroupID, "role": "ML Viewer", "dry_run": true}))
+	change := structuredAs[tools.RoleAssignmentChange](t, result)
+	if change.Status != tools.StatusDryRun || change.Plan == nil || change.Plan.Request.Method != "PUT" {
+		t.Fatalf("grant_workspace_role dry run = %+v, want a planned PUT", change)
+	}
+	if body, _ := json.Marshal(change.Plan.Request.Body); !strings.Contains(string(body), customRoleID) {
+		t.Errorf("planned body = %s, want the resolved custom role", body)
+	}
+	// Resolving the role reads from Azure, but nothing is written
+	for _, request := range fake.Requests() {
+		if request.Method != "GET" {
+			t.Errorf("dry run sent %s %s", request.Method, request.Path)
+		}
+	}
+}
+
+func TestRevokeWorkspaceRole(t *testing.T) {
+	fake, s := newAccessServer(t)
+
+	result := callTool(t, s, "revoke_workspace_role", workspaceArgs(map[string]any{"principal_id": testUserID, "role": "AzureML Data Scientist"}))
+	if result.IsError {
+		t.Fatalf("revoke_workspace_role failed: %s", resultText(result))
+	}
+	change := structuredAs[tools.RoleAssignmentChange](t, result)
+	if change.RoleAssignment == nil || change.RoleAssignment.Name != workspaceAssignment {
+		t.Errorf("change = %+v, want the workspace's Data Scientist assignment", change)
+	}
+	if _, ok := fake.RoleAssignment(testWorkspaceID, workspaceAssignment); ok {
+		t.Error("role assignment still exists")
+	}
+
+	got := toolError(t, callTool(t, s, "revoke_workspace_role", workspaceArgs(map[string]any{"role_assignment_id": workspaceAssignment})))
+	if got.Code != tools.ErrorNotFound {
+		t.Errorf("revoking a deleted assignment = %+v, want NotFound", got)
+	}
+
+	// Role assignment names are GUIDs
+	for _, id := range []string{"not-a-guid", fakearm.RoleAssignmentID(testWorkspaceID, "x/"+workspaceAssignment)} {
+		got := toolError(t, callTool(t, s, "revoke_workspace_role", workspaceArgs(map[string]any{"role_assignment_id": id})))
+		if got.Code != tools.ErrorInvalidArgument {
+			t.Errorf("revoking %q = %+v, want InvalidArgument", id, got)
+		}
+	}
+
+	// Inherited assignments are left to the scope they were made at
+	for _, args := range []map[string]any{
+		{"principal_id": testGroupID, "role": "Reader"},
+		{"role_assignment_id": fakearm.RoleAssignmentID(testResourceGroupID, resourceGroupAssignment)},
+	} {
+		result := callTool(t, s, "revoke_workspace_role", workspaceArgs(args))
+		if !result.IsError || !strings.Contains(resultText(result), "revoke it") {
+			t.Errorf("revoking %v = %s, want it refused", args, resultText(result))
+		}
+	}
+	if _, ok := fake.RoleAssignment(testResourceGroupID, resourceGroupAssignment); !ok {
+		t.Error("inherited role assignment was deleted")
+	}
+}
+
+func TestRevokeWorkspaceRoleNeedsConfirmation(t *testing.T) {
+	fake, s := newAccessServer(t, tools.WithConfirmations(confirm.NewStore(confirm.DefaultTTL)))
+	args := workspaceArgs(map[string]any{"role_assignment_id": fakearm.RoleAssignmentID(testWorkspaceID, workspaceAssignment)})
+
+	pending := structuredAs[tools.RoleAssignmentChange](t, callTool(t, s, "revoke_workspace_role", args))
+	if pending.Status != tools.StatusConfirmationRequired || pending.Plan == nil || pending.Plan.Request.Method != "DELETE" {
+		t.Fatalf("revoke_workspace_role = %+v, want a DELETE awaiting confirmation", pending)
+	}
+	if url := pending.Plan.Request.URL; !strings.Contains(url, "/workspaces/"+testWorkspace+"/providers/Microsoft.Authorization/roleAssignments/"+workspaceAssignment+"?") ||
+		strings.Contains(url, "//subscriptions") {
+		t.Errorf("request URL = %s, want the assignment on the workspace", url)
+	}
+	if _, ok := fake.RoleAssignment(testWorkspaceID, workspaceAssignment); !ok {
+		t.Fatal("role assignment was deleted before confirmation")
+	}
+
+	args["confirm_token"] = pending.Plan.ConfirmToken
+	if result := callTool(t, s, "revoke_workspace_role", args); result.IsError {
+		t.Fatalf("confirmed revoke_workspace_role failed: %s", resultText(result))
+	}
+	if _, ok := fake.RoleAssignment(testWorkspaceID, workspaceAssignment); ok {
+		t.Error("role assignment still exists after confirmation")
+	}
+}
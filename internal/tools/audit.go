@@ -0,0 +1,174 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"microsoft.com/aml-mcp/internal/audit"
+	"microsoft.com/aml-mcp/internal/azure"
+)
+
+// DefaultAuditLimit is how many events list_audit_events returns when no limit is given
+const DefaultAuditLimit = 50
+
+// Audited returns a Registrar that records every call of a mutating tool in log before registering it with r
+func Audited(r Registrar, log *audit.Log, clients *azure.ClientCache) Registrar {
+	return &auditRegistrar{next: r, log: log, clients: clients}
+}
+
+type auditRegistrar struct {
+	next    Registrar
+	log     *audit.Log
+	clients *azure.ClientCache
+}
+
+func (a *auditRegistrar) AddTool(tool mcp.Tool, handler server.ToolHandlerFunc) {
+	if readOnly := tool.Annotations.ReadOnlyHint; readOnly != nil && *readOnly {
+		a.next.AddTool(tool, handler)
+		return
+	}
+
+	a.next.AddTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		ctx, trace := azure.WithRequestTrace(ctx)
+		start := time.Now()
+		caller, known := a.clients.Identity(ctx)
+		result, err := handler(ctx, request)
+		if !known {
+			// The handler may have acquired the first credential, and ctx may be done
+			caller, known = a.clients.Identity(context.WithoutCancel(ctx))
+		}
+
+		event := audit.Event{
+			Time:          start.UTC(),
+			Tool:          request.Params.Name,
+			Arguments:     audit.Redact(request.GetArguments()),
+			SessionID:     sessionIDFromContext(ctx),
+			CorrelationID: trace.CorrelationID,
+			RequestIDs:    trace.RequestIDs(),
+			DurationMs:    time.Since(start).Milliseconds(),
+		}
+		if known {
+			event.Caller = audit.Caller(caller)
+		}
+		event.Outcome, event.Error = auditOutcome(result, err)
+		a.log.Record(event)
+
+		return result, err
+	})
+}
+
+// auditOutcome returns the outcome and error message of a call
+func auditOutcome(result *mcp.CallToolResult, err error) (string, string) {
+	if err != nil {
+		return audit.OutcomeFailed, err.Error()
+	}
+	if result == nil {
+		return audit.OutcomeSucceeded, ""
+	}
+	if result.IsError {
+		return audit.OutcomeFailed, resultText(result)
+	}
+
+	var structured struct {
+		Status string `json:"status"`
+	}
+	if data, err := json.Marshal(result.StructuredContent); err == nil {
+		_ = json.Unmarshal(data, &structured)
+	}
+	if structured.Status != "" {
+		return structured.Status, ""
+	}
+	return audit.OutcomeSucceeded, ""
+}
+
+func resultText(result *mcp.CallToolResult) string {
+	var parts []string
+	for _, content := range result.Content {
+		if text, ok := mcp.AsTextContent(content); ok {
+			parts = append(parts, text.Text)
+		}
+	}
+	return strings.Join(parts, "\n")
+}
+
+// AuditTools contains the tools for querying the audit log
+type AuditTools struct {
+	log *audit.Log
+}
+
+// NewAuditTools creates a new AuditTools instance reading from log
+func NewAuditTools(log *audit.Log) *AuditTools {
+	return &AuditTools{log: log}
+}
+
+// AddToServer registers all audit tools with the MCP server
+func (at *AuditTools) AddToServer(s Registrar) {
+	at.addListAuditEventsTool(s)
+}
+
+func (at *AuditTools) addListAuditEventsTool(s Registrar) {
+	tool := mcp.NewTool("list_audit_events",
+		mcp.WithDescription("List recorded calls of tools that change Azure resources, newest first"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(false),
+		mcp.WithOutputSchema[AuditEventList](),
+		mcp.WithString("tool",
+			mcp.Description("Only list calls of this tool, e.g. stop_compute"),
+		),
+		mcp.WithString("outcome",
+			mcp.Description("Only list calls with this outcome, e.g. Succeeded, Failed or DryRun"),
+		),
+		mcp.WithString("since",
+			mcp.Description("Only list calls made at or after this time (RFC 3339, e.g. 2025-01-31T09:00:00Z)"),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description("Maximum number of events to return (default 50)"),
+		),
+	)
+
+	s.AddTool(tool, at.handleListAuditEvents)
+}
+
+func (at *AuditTools) handleListAuditEvents(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	filter := audit.Filter{
+		Tool:    request.GetString("tool", ""),
+		Outcome: request.GetString("outcome", ""),
+		Limit:   request.GetInt("limit", DefaultAuditLimit),
+	}
+	if since := request.GetString("since", ""); since != "" {
+		t, err := time.Parse(time.RFC3339, since)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("since must be an RFC 3339 time: %v", err)), nil
+		}
+		filter.Since = t
+	}
+
+	events, err := at.log.Query(filter)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	result := AuditEventList{Count: len(events), Events: events}
+	if result.Count == 0 {
+		return mcp.NewToolResultStructured(result, "No audit events found."), nil
+	}
+
+	lines := make([]string, 0, len(events))
+	for _, event := range events {
+		caller := orNA(event.Caller.Name)
+		if event.Caller.Name == "" && event.Caller.ObjectID != "" {
+			caller = event.Caller.ObjectID
+		}
+		line := fmt.Sprintf("%s %s: %s by %s", event.Time.Format("2006-01-02 15:04:05"), event.Tool, event.Outcome, caller)
+		if event.Error != "" {
+			line += " (" + event.Error + ")"
+		}
+		lines = append(lines, line)
+	}
+	return mcp.NewToolResultStructured(result, fmt.Sprintf("Found %d audit events:\n%s", result.Count, strings.Join(lines, "\n"))), nil
+}
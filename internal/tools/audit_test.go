@@ -0,0 +1,120 @@
+package tools_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/mark3labs/mcp-go/mcp"
+	"microsoft.com/aml-mcp/internal/azure"
+
+	"github.com/mark3labs/mcp-go/server"
+	"microsoft.com/aml-mcp/internal/audit"
+	"microsoft.com/aml-mcp/internal/fakearm"
+	"microsoft.com/aml-mcp/internal/tools"
+)
+
+func TestAuditedTools(t *testing.T) {
+	fake := newFakeARM(t)
+	clients := fake.ClientCache()
+	log := audit.NewLog(nil)
+
+	s := server.NewMCPServer("test", "1.0.0")
+	audited := tools.Audited(s, log, clients)
+	tools.NewWorkspaceTools(clients).AddToServer(audited)
+	tools.NewComputeTools(clients).AddToServer(audited)
+	tools.NewAuditTools(log).AddToServer(audited)
+
+	callTool(t, s, "get_workspace", workspaceArgs(nil))
+	callTool(t, s, "start_compute", workspaceArgs(map[string]any{"compute_name": testCompute, "confirm_token": "secret"}))
+	callTool(t, s, "stop_compute", workspaceArgs(map[string]any{"compute_name": "missing"}))
+	callTool(t, s, "stop_compute", workspaceArgs(map[string]any{"compute_name": testCompute, "dry_run": true}))
+
+	list := structuredAs[tools.AuditEventList](t, callTool(t, s, "list_audit_events", nil))
+	if list.Count != 3 {
+		t.Fatalf("list_audit_events returned %d events, want 3 mutating calls: %+v", list.Count, list.Events)
+	}
+
+	dryRun, failed, started := list.Events[0], list.Events[1], list.Events[2]
+	if started.Tool != "start_compute" || started.Outcome != "Succeeded" {
+		t.Errorf("oldest event = %s %s, want start_compute Succeeded", started.Tool, started.Outcome)
+	}
+	if started.Caller.Name != fakearm.UserName || started.Caller.ObjectID != fakearm.ObjectID {
+		t.Errorf("caller = %+v, want the fake credential's identity", started.Caller)
+	}
+	if started.Arguments["compute_name"] != testCompute || started.Arguments["confirm_token"] != audit.Redacted {
+		t.Errorf("arguments = %v, want compute_name kept and confirm_token redacted", started.Arguments)
+	}
+	if started.CorrelationID == "" || len(started.RequestIDs) == 0 {
+		t.Errorf("event has correlation ID %q and request IDs %v, want both", started.CorrelationID, started.RequestIDs)
+	}
+	for _, request := range fake.Requests() {
+		if request.Method == "POST" && request.CorrelationID != started.CorrelationID && request.CorrelationID != failed.CorrelationID {
+			t.Errorf("request %s has correlation ID %q, want one of the audited calls", request.Path, request.CorrelationID)
+		}
+	}
+
+	if failed.Outcome != audit.OutcomeFailed || failed.Error == "" {
+		t.Errorf("failed stop_compute event = %s %q, want Failed with an error", failed.Outcome, failed.Error)
+	}
+	if dryRun.Outcome != tools.StatusDryRun {
+		t.Errorf("dry run outcome = %q, want %q", dryRun.Outcome, tools.StatusDryRun)
+	}
+
+	filtered := structuredAs[tools.AuditEventList](t, callTool(t, s, "list_audit_events", map[string]any{"tool": "stop_compute", "outcome": "failed"}))
+	if filtered.Count != 1 || filtered.Events[0].Error != failed.Error {
+		t.Errorf("filtered events = %+v, want the failed stop_compute", filtered.Events)
+	}
+
+	result := callTool(t, s, "list_audit_events", map[string]any{"since": "yesterday"})
+	if !result.IsError {
+		t.Error("list_audit_events accepted an invalid since")
+	}
+}
+
+// cancellableCredential fails token requests whose context is done, as the
+// azidentity credentials do
+type cancellableCredential struct {
+	azcore.TokenCredential
+}
+
+func (c cancellableCredential) GetToken(ctx context.Context, options policy.TokenRequestOptions) (azcore.AccessToken, error) {
+	if err := ctx.Err(); err != nil {
+		return azcore.AccessToken{}, err
+	}
+	return c.TokenCredential.GetToken(ctx, options)
+}
+
+func TestAuditedCancelledCall(t *testing.T) {
+	fake := newFakeARM(t)
+	clients := azure.NewClientCacheWithOptions(azure.ClientCacheOptions{
+		Credential:    cancellableCredential{fake.Credential()},
+		ClientOptions: fake.ClientOptions(),
+	})
+	log := audit.NewLog(nil)
+	s := server.NewMCPServer("test", "1.0.0")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	// The handler signs in, then runs out of time
+	tools.Audited(s, log, clients).AddTool(mcp.NewTool("slow_change"), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if _, err := clients.Get(ctx, testSubscriptionID); err != nil {
+			return nil, err
+		}
+		cancel()
+		return mcp.NewToolResultError(ctx.Err().Error()), nil
+	})
+	callToolInContext(ctx, t, s, "slow_change", nil)
+
+	events, err := log.Query(audit.Filter{})
+	if err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+	if len(events) != 1 {
+		t.Fatalf("recorded %d events, want 1", len(events))
+	}
+	if events[0].Caller.ObjectID != fakearm.ObjectID {
+		t.Errorf("caller = %+v, want the credential's identity after the call was cancelled", events[0].Caller)
+	}
+}
@@ -0,0 +1,182 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"microsoft.com/aml-mcp/internal/azure"
+)
+
+// AuthTools contains the tools for the server's Azure sign-in and permissions
+type AuthTools struct {
+	clients *azure.ClientCache
+	shared
+}
+
+// NewAuthTools creates a new AuthTools instance backed by a shared client cache
+func NewAuthTools(clients *azure.ClientCache, opts ...Option) *AuthTools {
+	return &AuthTools{clients: clients, shared: newShared(opts)}
+}
+
+// AddToServer registers all auth tools with the MCP server
+func (at *AuthTools) AddToServer(s Registrar) {
+	at.addAuthStatusTool(s)
+	at.addReauthenticateTool(s)
+	at.addCheckPermissionsTool(s)
+}
+
+func (at *AuthTools) addAuthStatusTool(s Registrar) {
+	tool := mcp.NewTool("auth_status",
+		mcp.WithDescription("Show which Azure identity the server signed in as: the credential type, tenant, object ID, user principal name or app ID, and when the access token expires. "+
+			"Use it to find out which identity lacks a role after an AuthorizationFailed error. Signs in first if the server has not yet."),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOutputSchema[AuthStatus](),
+		mcp.WithString(argSubscriptionID,
+			mcp.Description("Azure subscription ID. The token is requested from the tenant configured for this subscription, if any"),
+		),
+	)
+
+	s.AddTool(tool, at.handleAuthStatus)
+}
+
+func (at *AuthTools) addReauthenticateTool(s Registrar) {
+	tool := mcp.NewTool("reauthenticate",
+		mcp.WithDescription("Discard the server's cached Azure credential, clients and tool responses and sign in again, trying the configured credential types in order. "+
+			"Use it after signing in to the Azure CLI as another account, or after role assignments changed."),
+		// May start an interactive sign-in, so read-only mode leaves it out
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOutputSchema[AuthStatus](),
+	)
+
+	s.AddTool(tool, at.handleReauthenticate)
+}
+
+func (at *AuthTools) addCheckPermissionsTool(s Registrar) {
+	tool := mcp.NewTool("check_permissions",
+		mcp.WithDescription("Check the signed-in identity's effective Azure RBAC permissions on a resource group, workspace or compute resource, "+
+			"and report which of the server's mutating tools (such as start_compute, stop_compute and create_workspace) it is allowed to call there. "+
+			"Use it before changing anything to avoid AuthorizationFailed errors."),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOutputSchema[PermissionCheck](),
+		mcp.WithString(argSubscriptionID,
+			mcp.Description("Azure subscription ID"),
+		),
+		mcp.WithString(argResourceGroup,
+			mcp.Description("Resource group name"),
+		),
+		mcp.WithString(argWorkspace,
+			mcp.Description("Workspace name. Leave out, with no active workspace, to check the resource group"),
+		),
+		mcp.WithString(argCompute,
+			mcp.Description("Compute resource name, to check a compute resource in the workspace"),
+		),
+		mcp.WithString(argResourceID,
+			mcp.Description("ARM resource ID of the workspace or compute resource, instead of the separate arguments"),
+		),
+	)
+
+	s.AddTool(tool, at.handleCheckPermissions)
+}
+
+func (at *AuthTools) handleAuthStatus(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	subscriptionID := request.GetString(argSubscriptionID, "")
+	status, err := at.clients.TokenStatus(ctx, subscriptionID)
+	if err != nil {
+		return azureError(at.clients, "Failed to get an Azure access token", err), nil
+	}
+	result := newAuthStatus(status, subscriptionID)
+	return mcp.NewToolResultStructured(result, authStatusText(result, "Azure sign-in:")), nil
+}
+
+func (at *AuthTools) handleReauthenticate(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	at.clients.Invalidate()
+	status, err := at.clients.TokenStatus(ctx, "")
+	if err != nil {
+		return azureError(at.clients, "Failed to sign in to Azure", err), nil
+	}
+	result := newAuthStatus(status, "")
+	return mcp.NewToolResultStructured(result, authStatusText(result, "Signed in to Azure again:")), nil
+}
+
+func (at *AuthTools) handleCheckPermissions(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	subscriptionID, err := at.scopeArgument(ctx, request, argSubscriptionID)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	resourceGroupName, err := at.scopeArgument(ctx, request, argResourceGroup)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	// Check the narrowest scope the call names
+	scope := permissionScope{ResourceGroup: resourceGroupName}
+	scope.Workspace, _ = at.scopeArgument(ctx, request, argWorkspace)
+	if scope.Workspace != "" {
+		scope.Compute, _ = at.scopeArgument(ctx, request, argCompute)
+	}
+
+	clients, err := at.clients.Get(ctx, subscriptionID)
+	if err != nil {
+		return azureError(at.clients, "Failed to sign in to Azure", err), nil
+	}
+
+	permissions, err := listPermissions(ctx, clients, scope)
+	if err != nil {
+		return azureError(at.clients, "Failed to check permissions", err), nil
+	}
+
+	result := newPermissionCheck(subscriptionID, scope, permissions)
+	lines := make([]string, 0, len(toolActions))
+	for _, ta := range toolActions {
+		if !toolAvailable(ctx, ta.tool) {
+			continue
+		}
+		permission := ToolPermission{Tool: ta.tool, Action: ta.action, Allowed: allowed(permissions, ta.action)}
+		result.Tools = append(result.Tools, permission)
+		verdict := "allowed"
+		if !permission.Allowed {
+			verdict = "not allowed"
+		}
+		lines = append(lines, fmt.Sprintf("%s: %s (%s)", permission.Tool, verdict, permission.Action))
+	}
+
+	actions := "none"
+	if len(result.Actions) > 0 {
+		actions = strings.Join(result.Actions, ", ")
+	}
+	text := fmt.Sprintf("Permissions of the signed-in identity on %s:\nActions: %s", scope.describe(), actions)
+	if len(result.NotActions) > 0 {
+		text += "\nExcept: " + strings.Join(result.NotActions, ", ")
+	}
+	if len(lines) > 0 {
+		text += "\n\n" + strings.Join(lines, "\n")
+	}
+	return mcp.NewToolResultStructured(result, text), nil
+}
+
+// authStatusText renders an AuthStatus for the text output of the auth tools
+func authStatusText(status AuthStatus, heading string) string {
+	var b strings.Builder
+	b.WriteString(heading)
+	fmt.Fprintf(&b, "\nCredential type: %s", status.CredentialType)
+	if status.UserPrincipalName != "" {
+		fmt.Fprintf(&b, "\nUser: %s", status.UserPrincipalName)
+	}
+	if status.AppID != "" {
+		fmt.Fprintf(&b, "\nApp ID: %s", status.AppID)
+	}
+	fmt.Fprintf(&b, "\nObject ID: %s", orNA(status.ObjectID))
+	fmt.Fprintf(&b, "\nTenant: %s", orNA(status.TenantID))
+	if status.SubscriptionID != "" {
+		fmt.Fprintf(&b, " (for subscription %s)", status.SubscriptionID)
+	}
+	fmt.Fprintf(&b, "\nToken expires: %s (in %s)", status.ExpiresOn.Format(time.RFC3339), time.Until(status.ExpiresOn).Round(time.Minute))
+	fmt.Fprintf(&b, "\nResource Manager: %s", orNA(status.ResourceManagerEndpoint))
+	return b.String()
+}
@@ -0,0 +1,61 @@
+package tools_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/server"
+	"microsoft.com/aml-mcp/internal/azure"
+	"microsoft.com/aml-mcp/internal/fakearm"
+	"microsoft.com/aml-mcp/internal/tools"
+)
+
+func newAuthServer(clients *azure.ClientCache) *server.MCPServer {
+	s := server.NewMCPServer("test", "1.0.0")
+	tools.NewAuthTools(clients).AddToServer(s)
+	return s
+}
+
+func TestAuthStatus(t *testing.T) {
+	fake := newFakeARM(t)
+	s := newAuthServer(fake.ClientCache())
+
+	for _, name := range []string{"auth_status", "reauthenticate"} {
+		t.Run(name, func(t *testing.T) {
+			result := callTool(t, s, name, nil)
+			if result.IsError {
+				t.Fatalf("%s failed: %s", name, resultText(result))
+			}
+			status := structuredAs[tools.AuthStatus](t, result)
+			if status.CredentialType != azure.AuthCustom || status.UserPrincipalName != fakearm.UserName ||
+				status.ObjectID != fakearm.ObjectID || status.TenantID != fakearm.TenantID {
+				t.Errorf("AuthStatus = %+v, want the fake credential's identity", status)
+			}
+			if !status.ExpiresOn.After(time.Now()) {
+				t.Errorf("ExpiresOn = %v, want a time in the future", status.ExpiresOn)
+			}
+			if status.ResourceManagerEndpoint != fake.URL() {
+				t.Errorf("ResourceManagerEndpoint = %q, want %q", status.ResourceManagerEndpoint, fake.URL())
+			}
+			if text := resultText(result); !strings.Contains(text, "User: "+fakearm.UserName) {
+				t.Errorf("text %q does not name the user", text)
+			}
+		})
+	}
+}
+
+func TestAuthStatusWithoutCredential(t *testing.T) {
+	for _, name := range []string{azure.EnvTenantID, azure.EnvClientID} {
+		t.Setenv(name, "")
+	}
+	s := newAuthServer(azure.NewClientCacheWithOptions(azure.ClientCacheOptions{
+		Auth: azure.AuthOptions{Modes: []string{azure.AuthServicePrincipal}},
+	}))
+
+	result := callTool(t, s, "auth_status", nil)
+	text := resultText(result)
+	if !result.IsError || !strings.Contains(text, tools.ErrorUnauthenticated) || !strings.Contains(text, "needs a tenant and client ID") {
+		t.Errorf("auth_status without a credential = %s, want an Unauthenticated error explaining why", text)
+	}
+}
@@ -0,0 +1,115 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"microsoft.com/aml-mcp/internal/cache"
+)
+
+// DefaultCacheTTLs are how long the results of slow-changing read-only tools are cached
+var DefaultCacheTTLs = map[string]time.Duration{
+	"list_workspaces_by_subscription": 2 * time.Minute,
+	"list_workspace_features":         10 * time.Minute,
+	"list_quotas":                     10 * time.Minute,
+	"list_vm_sizes":                   time.Hour,
+}
+
+const argRefresh = "refresh"
+
+// refreshDescription describes the argument every cached tool accepts
+const refreshDescription = "Fetch fresh data from Azure instead of returning a recently cached result"
+
+// scopeArguments identify the Azure scope a call applies to, outermost first
+var scopeArguments = []string{argSubscriptionID, argResourceGroup, argWorkspace}
+
+// Cached returns a Registrar that caches the results of the tools in ttls before registering them with r
+func Cached(r Registrar, c *cache.Cache, ttls map[string]time.Duration, opts ...Option) Registrar {
+	return &cacheRegistrar{next: r, cache: c, ttls: ttls, shared: newShared(opts)}
+}
+
+type cacheRegistrar struct {
+	next  Registrar
+	cache *cache.Cache
+	ttls  map[string]time.Duration
+	shared
+}
+
+func (c *cacheRegistrar) AddTool(tool mcp.Tool, handler server.ToolHandlerFunc) {
+	// Only the scope arguments a tool declares apply to it
+	var scopes []string
+	for _, name := range scopeArguments {
+		if _, ok := tool.InputSchema.Properties[name]; ok {
+			scopes = append(scopes, name)
+		}
+	}
+
+	if readOnly := tool.Annotations.ReadOnlyHint; readOnly == nil || !*readOnly {
+		c.next.AddTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			result, err := handler(ctx, request)
+			if changed(result, err) {
+				c.cache.Invalidate(c.scope(ctx, request, scopes)...)
+			}
+			return result, err
+		})
+		return
+	}
+
+	ttl := c.ttls[tool.Name]
+	if ttl <= 0 {
+		c.next.AddTool(tool, handler)
+		return
+	}
+
+	c.next.AddTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		scope := c.scope(ctx, request, scopes)
+		key := cacheKey(request, scope)
+		if !request.GetBool(argRefresh, false) {
+			if cached, ok := c.cache.Get(key); ok {
+				return cached.(*mcp.CallToolResult), nil
+			}
+		}
+
+		result, err := handler(ctx, request)
+		if err == nil && result != nil && !result.IsError {
+			c.cache.Set(key, scope, result, ttl)
+		}
+		return result, err
+	})
+}
+
+// changed reports whether a mutating call may have changed Azure resources
+func changed(result *mcp.CallToolResult, err error) bool {
+	outcome, _ := auditOutcome(result, err)
+	return outcome != StatusDryRun && outcome != StatusConfirmationRequired
+}
+
+// scope resolves the scope arguments of a call, stopping at the first that cannot be resolved
+func (c *cacheRegistrar) scope(ctx context.Context, request mcp.CallToolRequest, names []string) []string {
+	var scope []string
+	for _, name := range names {
+		value, err := c.scopeArgument(ctx, request, name)
+		if err != nil {
+			break
+		}
+		scope = append(scope, strings.ToLower(value))
+	}
+	return scope
+}
+
+// cacheKey identifies a call by its tool, resolved scope and remaining arguments
+func cacheKey(request mcp.CallToolRequest, scope []string) string {
+	arguments := maps.Clone(request.GetArguments())
+	for _, name := range slices.Concat(scopeArguments, []string{argResourceID, argRefresh}) {
+		delete(arguments, name)
+	}
+	// Maps marshal with sorted keys, so equal arguments give equal keys
+	data, _ := json.Marshal(arguments)
+	return request.Params.Name + "\n" + strings.Join(scope, "/") + "\n" + string(data)
+}
@@ -0,0 +1,138 @@
+package tools_test
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/server"
+	"microsoft.com/aml-mcp/internal/cache"
+	"microsoft.com/aml-mcp/internal/fakearm"
+	"microsoft.com/aml-mcp/internal/tools"
+)
+
+// countRequests counts the GET requests the fake server received whose path
+// ends with suffix
+func countRequests(fake *fakearm.Server, suffix string) int {
+	count := 0
+	for _, request := range fake.Requests() {
+		if request.Method == "GET" && strings.HasSuffix(request.Path, suffix) {
+			count++
+		}
+	}
+	return count
+}
+
+func newCachingServer(t *testing.T, ttls map[string]time.Duration) (*fakearm.Server, *server.MCPServer) {
+	t.Helper()
+
+	fake := newFakeARM(t)
+	clients := fake.ClientCache()
+	opts := []tools.Option{tools.WithPollInterval(10 * time.Millisecond)}
+
+	s := server.NewMCPServer("test", "1.0.0")
+	cached := tools.Cached(s, cache.New(), ttls, opts...)
+	tools.NewWorkspaceTools(clients, opts...).AddToServer(cached)
+	tools.NewComputeTools(clients, opts...).AddToServer(cached)
+	tools.NewMonitoringTools(clients, opts...).AddToServer(cached)
+	tools.NewAuthTools(clients, opts...).AddToServer(cached)
+	return fake, s
+}
+
+func TestCachedTools(t *testing.T) {
+	fake, s := newCachingServer(t, tools.DefaultCacheTTLs)
+	args := map[string]any{"subscription_id": testSubscriptionID, "location": testLocation}
+
+	first := callTool(t, s, "list_vm_sizes", args)
+	second := callTool(t, s, "list_vm_sizes", args)
+	if got := countRequests(fake, "/vmSizes"); got != 1 {
+		t.Errorf("two list_vm_sizes calls sent %d requests, want 1", got)
+	}
+	if resultText(first) != resultText(second) {
+		t.Errorf("cached result %q differs from the first %q", resultText(second), resultText(first))
+	}
+
+	// Other arguments are cached separately
+	callTool(t, s, "list_vm_sizes", map[string]any{"subscription_id": testSubscriptionID, "location": "westus2"})
+	if got := countRequests(fake, "/vmSizes"); got != 2 {
+		t.Errorf("list_vm_sizes in another location sent %d requests in total, want 2", got)
+	}
+
+	args["refresh"] = true
+	callTool(t, s, "list_vm_sizes", args)
+	if got := countRequests(fake, "/vmSizes"); got != 3 {
+		t.Errorf("list_vm_sizes with refresh sent %d requests in total, want 3", got)
+	}
+
+	// list_usage has no TTL and is never cached
+	delete(args, "refresh")
+	callTool(t, s, "list_usage", args)
+	callTool(t, s, "list_usage", args)
+	if got := countRequests(fake, "/usages"); got != 2 {
+		t.Errorf("two list_usage calls sent %d requests, want 2", got)
+	}
+}
+
+func TestCachedToolsInvalidatedByMutation(t *testing.T) {
+	fake, s := newCachingServer(t, tools.DefaultCacheTTLs)
+	list := map[string]any{"subscription_id": testSubscriptionID}
+	workspacesPath := "/subscriptions/" + testSubscriptionID + "/providers/Microsoft.MachineLearningServices/workspaces"
+
+	callTool(t, s, "list_workspaces_by_subscription", list)
+	callTool(t, s, "list_workspaces_by_subscription", list)
+	if got := countRequests(fake, workspacesPath); got != 1 {
+		t.Fatalf("two list_workspaces_by_subscription calls sent %d requests, want 1", got)
+	}
+
+	// A dry run changes nothing, so the cached list stays
+	result := callTool(t, s, "start_compute", workspaceArgs(map[string]any{"compute_name": testCompute, "dry_run": true}))
+	if result.IsError {
+		t.Fatalf("start_compute dry run failed: %s", resultText(result))
+	}
+	callTool(t, s, "list_workspaces_by_subscription", list)
+	if got := countRequests(fake, workspacesPath); got != 1 {
+		t.Errorf("list_workspaces_by_subscription after a dry run sent %d requests in total, want 1", got)
+	}
+
+	result = callTool(t, s, "start_compute", workspaceArgs(map[string]any{"compute_name": testCompute}))
+	if result.IsError {
+		t.Fatalf("start_compute failed: %s", resultText(result))
+	}
+	callTool(t, s, "list_workspaces_by_subscription", list)
+	if got := countRequests(fake, workspacesPath); got != 2 {
+		t.Errorf("list_workspaces_by_subscription after start_compute sent %d requests in total, want 2", got)
+	}
+
+	// Signing in again may change what the identity can see
+	callTool(t, s, "list_workspaces_by_subscription", list)
+	if result := callTool(t, s, "reauthenticate", nil); result.IsError {
+		t.Fatalf("reauthenticate failed: %s", resultText(result))
+	}
+	callTool(t, s, "list_workspaces_by_subscription", list)
+	if got := countRequests(fake, workspacesPath); got != 3 {
+		t.Errorf("list_workspaces_by_subscription after reauthenticate sent %d requests in total, want 3", got)
+	}
+}
+
+func TestCachedCallsLogged(t *testing.T) {
+	fake := newFakeARM(t)
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, nil))
+
+	// The cache sits inside the logger, as in the server
+	s := server.NewMCPServer("test", "1.0.0")
+	cached := tools.Cached(tools.Logged(s, logger), cache.New(), tools.DefaultCacheTTLs)
+	tools.NewMonitoringTools(fake.ClientCache()).AddToServer(cached)
+
+	args := map[string]any{"subscription_id": testSubscriptionID, "location": testLocation}
+	callTool(t, s, "list_vm_sizes", args)
+	callTool(t, s, "list_vm_sizes", args)
+	if got := countRequests(fake, "/vmSizes"); got != 1 {
+		t.Fatalf("two list_vm_sizes calls sent %d requests, want 1", got)
+	}
+	if got := strings.Count(buf.String(), `msg="Tool call finished" tool=list_vm_sizes`); got != 2 {
+		t.Errorf("logged %d finished calls, want both, including the cached one:\n%s", got, buf.String())
+	}
+}
@@ -5,114 +5,154 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/machinelearning/armmachinelearning"
 	"github.com/mark3labs/mcp-go/mcp"
-	"github.com/mark3labs/mcp-go/server"
 	"microsoft.com/aml-mcp/internal/azure"
-	"microsoft.com/aml-mcp/internal/helpers"
+	"microsoft.com/aml-mcp/internal/operations"
 )
 
 // ComputeTools contains all compute-related MCP tools
-type ComputeTools struct{}
+type ComputeTools struct {
+	clients *azure.ClientCache
+	shared
+}
 
-// NewComputeTools creates a new ComputeTools instance
-func NewComputeTools() *ComputeTools {
-	return &ComputeTools{}
+// NewComputeTools creates a new ComputeTools instance backed by a shared client cache
+func NewComputeTools(clients *azure.ClientCache, opts ...Option) *ComputeTools {
+	return &ComputeTools{clients: clients, shared: newShared(opts)}
 }
 
 // AddToServer registers all compute tools with the MCP server
-func (ct *ComputeTools) AddToServer(s *server.MCPServer) {
+func (ct *ComputeTools) AddToServer(s Registrar) {
 	ct.addListComputeTool(s)
 	ct.addGetComputeTool(s)
 	ct.addStartComputeTool(s)
 	ct.addStopComputeTool(s)
 }
 
-func (ct *ComputeTools) addListComputeTool(s *server.MCPServer) {
+func (ct *ComputeTools) addListComputeTool(s Registrar) {
 	tool := mcp.NewTool("list_compute",
 		mcp.WithDescription("List all compute resources in an Azure ML workspace"),
-		mcp.WithString("subscription_id",
-			mcp.Required(),
-			mcp.Description("Azure subscription ID"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOutputSchema[ComputeList](),
+		mcp.WithString(argResourceID,
+			mcp.Description("Full ARM resource ID of the workspace. Takes precedence over subscription_id, resource_group_name and workspace_name"),
+		),
+		mcp.WithString(argSubscriptionID,
+			mcp.Description("Azure subscription ID. Defaults to the active workspace's subscription"),
+		),
+		mcp.WithString(argResourceGroup,
+			mcp.Description("Resource group name. Defaults to the active workspace's resource group"),
+		),
+		mcp.WithString(argWorkspace,
+			mcp.Description("Workspace name. Defaults to the active workspace"),
+		),
+		mcp.WithString(argNameContains,
+			mcp.Description("Only list compute resources whose name contains this text, ignoring case"),
+		),
+		mcp.WithString(argLocation,
+			mcp.Description("Only list compute resources in this Azure region (e.g., eastus)"),
 		),
-		mcp.WithString("resource_group_name",
-			mcp.Required(),
-			mcp.Description("Resource group name"),
+		mcp.WithString(argState,
+			mcp.Description("Only list compute resources in this provisioning state (e.g., Succeeded, Failed)"),
 		),
-		mcp.WithString("workspace_name",
-			mcp.Required(),
-			mcp.Description("Workspace name"),
+		mcp.WithString(argType,
+			mcp.Description("Only list compute resources of this type (e.g., ComputeInstance, AmlCompute)"),
 		),
 	)
+	addListOptions(&tool, "name", "type", "location", "state", "createdOn")
 
 	s.AddTool(tool, ct.handleListCompute)
 }
 
-func (ct *ComputeTools) addGetComputeTool(s *server.MCPServer) {
+func (ct *ComputeTools) addGetComputeTool(s Registrar) {
 	tool := mcp.NewTool("get_compute",
 		mcp.WithDescription("Get details of a specific compute resource"),
-		mcp.WithString("subscription_id",
-			mcp.Required(),
-			mcp.Description("Azure subscription ID"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOutputSchema[Compute](),
+		mcp.WithString(argResourceID,
+			mcp.Description("Full ARM resource ID of the compute resource. Takes precedence over subscription_id, resource_group_name, workspace_name and compute_name"),
 		),
-		mcp.WithString("resource_group_name",
-			mcp.Required(),
-			mcp.Description("Resource group name"),
+		mcp.WithString(argSubscriptionID,
+			mcp.Description("Azure subscription ID. Defaults to the active workspace's subscription"),
 		),
-		mcp.WithString("workspace_name",
-			mcp.Required(),
-			mcp.Description("Workspace name"),
+		mcp.WithString(argResourceGroup,
+			mcp.Description("Resource group name. Defaults to the active workspace's resource group"),
 		),
-		mcp.WithString("compute_name",
-			mcp.Required(),
-			mcp.Description("Compute resource name"),
+		mcp.WithString(argWorkspace,
+			mcp.Description("Workspace name. Defaults to the active workspace"),
+		),
+		mcp.WithString(argCompute,
+			mcp.Description("Compute resource name. Required unless resource_id identifies a compute resource"),
 		),
 	)
 
 	s.AddTool(tool, ct.handleGetCompute)
 }
 
-func (ct *ComputeTools) addStartComputeTool(s *server.MCPServer) {
+func (ct *ComputeTools) addStartComputeTool(s Registrar) {
 	tool := mcp.NewTool("start_compute",
 		mcp.WithDescription("Start a compute resource"),
-		mcp.WithString("subscription_id",
-			mcp.Required(),
-			mcp.Description("Azure subscription ID"),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOutputSchema[ComputeOperation](),
+		mcp.WithString(argResourceID,
+			mcp.Description("Full ARM resource ID of the compute resource. Takes precedence over subscription_id, resource_group_name, workspace_name and compute_name"),
+		),
+		mcp.WithString(argSubscriptionID,
+			mcp.Description("Azure subscription ID. Defaults to the active workspace's subscription"),
+		),
+		mcp.WithString(argResourceGroup,
+			mcp.Description("Resource group name. Defaults to the active workspace's resource group"),
 		),
-		mcp.WithString("resource_group_name",
-			mcp.Required(),
-			mcp.Description("Resource group name"),
+		mcp.WithString(argWorkspace,
+			mcp.Description("Workspace name. Defaults to the active workspace"),
 		),
-		mcp.WithString("workspace_name",
-			mcp.Required(),
-			mcp.Description("Workspace name"),
+		mcp.WithString(argCompute,
+			mcp.Description("Compute resource name. Required unless resource_id identifies a compute resource"),
 		),
-		mcp.WithString("compute_name",
-			mcp.Required(),
-			mcp.Description("Compute resource name"),
+		mcp.WithBoolean("wait",
+			mcp.Description("Wait for the operation to finish (default true). Set to false to return an operation ID immediately and track it with get_operation_status"),
+		),
+		mcp.WithBoolean(argDryRun,
+			mcp.Description(dryRunDescription),
+		),
+		mcp.WithString(argConfirmToken,
+			mcp.Description(confirmTokenDescription),
 		),
 	)
 
 	s.AddTool(tool, ct.handleStartCompute)
 }
 
-func (ct *ComputeTools) addStopComputeTool(s *server.MCPServer) {
+func (ct *ComputeTools) addStopComputeTool(s Registrar) {
 	tool := mcp.NewTool("stop_compute",
 		mcp.WithDescription("Stop a compute resource"),
-		mcp.WithString("subscription_id",
-			mcp.Required(),
-			mcp.Description("Azure subscription ID"),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOutputSchema[ComputeOperation](),
+		mcp.WithString(argResourceID,
+			mcp.Description("Full ARM resource ID of the compute resource. Takes precedence over subscription_id, resource_group_name, workspace_name and compute_name"),
+		),
+		mcp.WithString(argSubscriptionID,
+			mcp.Description("Azure subscription ID. Defaults to the active workspace's subscription"),
 		),
-		mcp.WithString("resource_group_name",
-			mcp.Required(),
-			mcp.Description("Resource group name"),
+		mcp.WithString(argResourceGroup,
+			mcp.Description("Resource group name. Defaults to the active workspace's resource group"),
 		),
-		mcp.WithString("workspace_name",
-			mcp.Required(),
-			mcp.Description("Workspace name"),
+		mcp.WithString(argWorkspace,
+			mcp.Description("Workspace name. Defaults to the active workspace"),
 		),
-		mcp.WithString("compute_name",
-			mcp.Required(),
-			mcp.Description("Compute resource name"),
+		mcp.WithString(argCompute,
+			mcp.Description("Compute resource name. Required unless resource_id identifies a compute resource"),
+		),
+		mcp.WithBoolean("wait",
+			mcp.Description("Wait for the operation to finish (default true). Set to false to return an operation ID immediately and track it with get_operation_status"),
+		),
+		mcp.WithBoolean(argDryRun,
+			mcp.Description(dryRunDescription),
+		),
+		mcp.WithString(argConfirmToken,
+			mcp.Description(confirmTokenDescription),
 		),
 	)
 
@@ -120,85 +160,109 @@ func (ct *ComputeTools) addStopComputeTool(s *server.MCPServer) {
 }
 
 func (ct *ComputeTools) handleListCompute(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	subscriptionID, err := request.RequireString("subscription_id")
+	subscriptionID, err := ct.scopeArgument(ctx, request, argSubscriptionID)
 	if err != nil {
 		return mcp.NewToolResultError(err.Error()), nil
 	}
 
-	resourceGroupName, err := request.RequireString("resource_group_name")
+	resourceGroupName, err := ct.scopeArgument(ctx, request, argResourceGroup)
 	if err != nil {
 		return mcp.NewToolResultError(err.Error()), nil
 	}
 
-	workspaceName, err := request.RequireString("workspace_name")
+	workspaceName, err := ct.scopeArgument(ctx, request, argWorkspace)
 	if err != nil {
 		return mcp.NewToolResultError(err.Error()), nil
 	}
 
-	clients, err := azure.NewClientSet(subscriptionID)
+	query, err := newListQuery(request, subscriptionID, resourceGroupName, workspaceName)
 	if err != nil {
 		return mcp.NewToolResultError(err.Error()), nil
 	}
 
-	pager := clients.ComputeClient.NewListPager(resourceGroupName, workspaceName, nil)
-	var computes []string
+	clients, err := ct.clients.Get(ctx, subscriptionID)
+	if err != nil {
+		return azureError(ct.clients, "Failed to sign in to Azure", err), nil
+	}
 
-	for pager.More() {
-		page, err := pager.NextPage(ctx)
+	fetch := func(ctx context.Context, skip string) ([]Compute, string, error) {
+		var options *armmachinelearning.ComputeClientListOptions
+		if skip != "" {
+			options = &armmachinelearning.ComputeClientListOptions{Skip: &skip}
+		}
+		page, err := clients.ComputeClient.NewListPager(resourceGroupName, workspaceName, options).NextPage(ctx)
 		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("Failed to get compute resources: %v", err)), nil
+			return nil, "", err
 		}
+		return newComputes(page.Value), valueOf(page.NextLink), nil
+	}
+	nameContains := request.GetString(argNameContains, "")
+	location := request.GetString(argLocation, "")
+	state := request.GetString(argState, "")
+	computeType := request.GetString(argType, "")
+	match := func(c Compute) bool {
+		return matchNameContains(nameContains, c.Name) && matchLocation(location, c.Location) &&
+			matchFilter(state, c.ProvisioningState) && matchFilter(computeType, c.Type)
+	}
+	compares := map[string]func(a, b Compute) int{
+		"name":      func(a, b Compute) int { return compareFold(a.Name, b.Name) },
+		"type":      func(a, b Compute) int { return compareFold(a.Type, b.Type) },
+		"location":  func(a, b Compute) int { return compareFold(a.Location, b.Location) },
+		"state":     func(a, b Compute) int { return compareFold(a.ProvisioningState, b.ProvisioningState) },
+		"createdOn": func(a, b Compute) int { return compareTimes(a.CreatedOn, b.CreatedOn) },
+	}
 
-		for _, compute := range page.Value {
-			if compute.Name != nil && compute.Properties != nil {
-				computes = append(computes, fmt.Sprintf("Name: %s, Type: %s, Location: %s, State: %s",
-					*compute.Name,
-					helpers.GetComputeType(compute.Properties),
-					helpers.GetStringValue(compute.Location),
-					helpers.GetComputeProvisioningState(compute.Properties)))
-			}
-		}
+	computes, nextCursor, err := listPage(ctx, query, fetch, match, compares)
+	if err != nil {
+		return azureError(ct.clients, "Failed to get compute resources", err), nil
 	}
+	result := ComputeList{Workspace: workspaceName, Count: len(computes), Computes: computes, NextCursor: nextCursor}
 
-	if len(computes) == 0 {
-		return mcp.NewToolResultText("No compute resources found in the workspace."), nil
+	if result.Count == 0 {
+		return mcp.NewToolResultStructured(result, "No compute resources found in the workspace."), nil
 	}
 
-	return mcp.NewToolResultText(fmt.Sprintf("Found %d compute resources:\n%s", len(computes), strings.Join(computes, "\n"))), nil
+	lines := make([]string, 0, len(computes))
+	for _, c := range computes {
+		lines = append(lines, fmt.Sprintf("Name: %s, Type: %s, Location: %s, State: %s",
+			c.Name, orNA(c.Type), orNA(c.Location), orNA(c.ProvisioningState)))
+	}
+	return mcp.NewToolResultStructured(result, fmt.Sprintf("Found %d compute resources:\n%s%s",
+		result.Count, strings.Join(lines, "\n"), moreResultsText(nextCursor))), nil
 }
 
 func (ct *ComputeTools) handleGetCompute(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	subscriptionID, err := request.RequireString("subscription_id")
+	subscriptionID, err := ct.scopeArgument(ctx, request, argSubscriptionID)
 	if err != nil {
 		return mcp.NewToolResultError(err.Error()), nil
 	}
 
-	resourceGroupName, err := request.RequireString("resource_group_name")
+	resourceGroupName, err := ct.scopeArgument(ctx, request, argResourceGroup)
 	if err != nil {
 		return mcp.NewToolResultError(err.Error()), nil
 	}
 
-	workspaceName, err := request.RequireString("workspace_name")
+	workspaceName, err := ct.scopeArgument(ctx, request, argWorkspace)
 	if err != nil {
 		return mcp.NewToolResultError(err.Error()), nil
 	}
 
-	computeName, err := request.RequireString("compute_name")
+	computeName, err := ct.scopeArgument(ctx, request, argCompute)
 	if err != nil {
 		return mcp.NewToolResultError(err.Error()), nil
 	}
 
-	clients, err := azure.NewClientSet(subscriptionID)
+	clients, err := ct.clients.Get(ctx, subscriptionID)
 	if err != nil {
-		return mcp.NewToolResultError(err.Error()), nil
+		return azureError(ct.clients, "Failed to sign in to Azure", err), nil
 	}
 
 	resp, err := clients.ComputeClient.Get(ctx, resourceGroupName, workspaceName, computeName, nil)
 	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("Failed to get compute resource: %v", err)), nil
+		return azureError(ct.clients, "Failed to get compute resource", err), nil
 	}
 
-	compute := resp.ComputeResource
+	compute := newCompute(&resp.ComputeResource)
 	details := fmt.Sprintf(`Compute Resource Details:
 Name: %s
 Type: %s
@@ -209,93 +273,163 @@ Provisioning State: %s
 Created On: %s
 Modified On: %s
 Is Attached: %t`,
-		helpers.GetStringValue(compute.Name),
-		helpers.GetComputeType(compute.Properties),
-		helpers.GetStringValue(compute.Location),
-		helpers.GetComputeDescription(compute.Properties),
-		helpers.GetStringValue(compute.ID),
-		helpers.GetComputeProvisioningState(compute.Properties),
-		helpers.GetComputeCreatedOn(compute.Properties),
-		helpers.GetComputeModifiedOn(compute.Properties),
-		helpers.GetComputeIsAttached(compute.Properties))
-
-	return mcp.NewToolResultText(details), nil
+		orNA(compute.Name),
+		orNA(compute.Type),
+		orNA(compute.Location),
+		orNA(compute.Description),
+		orNA(compute.ID),
+		orNA(compute.ProvisioningState),
+		formatTime(compute.CreatedOn),
+		formatTime(compute.ModifiedOn),
+		compute.IsAttached)
+
+	return mcp.NewToolResultStructured(compute, details), nil
 }
 
 func (ct *ComputeTools) handleStartCompute(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	subscriptionID, err := request.RequireString("subscription_id")
+	subscriptionID, err := ct.scopeArgument(ctx, request, argSubscriptionID)
 	if err != nil {
 		return mcp.NewToolResultError(err.Error()), nil
 	}
 
-	resourceGroupName, err := request.RequireString("resource_group_name")
+	resourceGroupName, err := ct.scopeArgument(ctx, request, argResourceGroup)
 	if err != nil {
 		return mcp.NewToolResultError(err.Error()), nil
 	}
 
-	workspaceName, err := request.RequireString("workspace_name")
+	workspaceName, err := ct.scopeArgument(ctx, request, argWorkspace)
 	if err != nil {
 		return mcp.NewToolResultError(err.Error()), nil
 	}
 
-	computeName, err := request.RequireString("compute_name")
+	computeName, err := ct.scopeArgument(ctx, request, argCompute)
 	if err != nil {
 		return mcp.NewToolResultError(err.Error()), nil
 	}
 
-	clients, err := azure.NewClientSet(subscriptionID)
+	clients, err := ct.clients.Get(ctx, subscriptionID)
 	if err != nil {
-		return mcp.NewToolResultError(err.Error()), nil
+		return azureError(ct.clients, "Failed to sign in to Azure", err), nil
+	}
+	if denied := preflight(ctx, request, ct.clients, clients, permissionScope{ResourceGroup: resourceGroupName, Workspace: workspaceName, Compute: computeName}); denied != nil {
+		return denied, nil
+	}
+
+	plan, failed := ct.planMutation(ctx, request, ct.clients, subscriptionID,
+		fmt.Sprintf("Start compute resource '%s' in workspace '%s'", computeName, workspaceName),
+		func(clients *azure.ClientSet) error {
+			_, err := clients.ComputeClient.BeginStart(ctx, resourceGroupName, workspaceName, computeName, nil)
+			return err
+		})
+	if failed != nil {
+		return failed, nil
+	}
+	if plan != nil {
+		return mcp.NewToolResultStructured(ComputeOperation{ComputeName: computeName, Action: "start", Status: plan.status(), Plan: plan}, plan.text()), nil
 	}
 
 	poller, err := clients.ComputeClient.BeginStart(ctx, resourceGroupName, workspaceName, computeName, nil)
 	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("Failed to start compute: %v", err)), nil
+		return azureError(ct.clients, "Failed to start compute", err), nil
+	}
+
+	if !request.GetBool("wait", true) {
+		op, err := ct.trackOperation(ctx, operations.Operation{
+			Kind:           operations.KindStartCompute,
+			SubscriptionID: subscriptionID,
+			ResourceGroup:  resourceGroupName,
+			Workspace:      workspaceName,
+			Target:         computeName,
+		}, sdkPoller[armmachinelearning.ComputeClientStartResponse]{poller})
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		return mcp.NewToolResultStructured(ComputeOperation{ComputeName: computeName, Action: "start", Status: string(op.Status), OperationID: op.ID},
+			fmt.Sprintf("Start of compute resource '%s' is %s. Operation ID: %s", computeName, op.Status, op.ID)), nil
 	}
 
-	_, err = poller.PollUntilDone(ctx, nil)
+	err = ct.pollUntilDone(ctx, request, fmt.Sprintf("Starting compute '%s'", computeName), sdkPoller[armmachinelearning.ComputeClientStartResponse]{poller})
 	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("Failed to start compute: %v", err)), nil
+		return azureError(ct.clients, "Failed to start compute", err), nil
+	}
+	if _, err := poller.Result(ctx); err != nil {
+		return azureError(ct.clients, "Failed to start compute", err), nil
 	}
 
-	return mcp.NewToolResultText(fmt.Sprintf("Successfully started compute resource '%s'", computeName)), nil
+	return mcp.NewToolResultStructured(ComputeOperation{ComputeName: computeName, Action: "start", Status: string(operations.StatusSucceeded)},
+		fmt.Sprintf("Successfully started compute resource '%s'", computeName)), nil
 }
 
 func (ct *ComputeTools) handleStopCompute(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	subscriptionID, err := request.RequireString("subscription_id")
+	subscriptionID, err := ct.scopeArgument(ctx, request, argSubscriptionID)
 	if err != nil {
 		return mcp.NewToolResultError(err.Error()), nil
 	}
 
-	resourceGroupName, err := request.RequireString("resource_group_name")
+	resourceGroupName, err := ct.scopeArgument(ctx, request, argResourceGroup)
 	if err != nil {
 		return mcp.NewToolResultError(err.Error()), nil
 	}
 
-	workspaceName, err := request.RequireString("workspace_name")
+	workspaceName, err := ct.scopeArgument(ctx, request, argWorkspace)
 	if err != nil {
 		return mcp.NewToolResultError(err.Error()), nil
 	}
 
-	computeName, err := request.RequireString("compute_name")
+	computeName, err := ct.scopeArgument(ctx, request, argCompute)
 	if err != nil {
 		return mcp.NewToolResultError(err.Error()), nil
 	}
 
-	clients, err := azure.NewClientSet(subscriptionID)
+	clients, err := ct.clients.Get(ctx, subscriptionID)
 	if err != nil {
-		return mcp.NewToolResultError(err.Error()), nil
+		return azureError(ct.clients, "Failed to sign in to Azure", err), nil
+	}
+	if denied := preflight(ctx, request, ct.clients, clients, permissionScope{ResourceGroup: resourceGroupName, Workspace: workspaceName, Compute: computeName}); denied != nil {
+		return denied, nil
+	}
+
+	plan, failed := ct.planMutation(ctx, request, ct.clients, subscriptionID,
+		fmt.Sprintf("Stop compute resource '%s' in workspace '%s'", computeName, workspaceName),
+		func(clients *azure.ClientSet) error {
+			_, err := clients.ComputeClient.BeginStop(ctx, resourceGroupName, workspaceName, computeName, nil)
+			return err
+		})
+	if failed != nil {
+		return failed, nil
+	}
+	if plan != nil {
+		return mcp.NewToolResultStructured(ComputeOperation{ComputeName: computeName, Action: "stop", Status: plan.status(), Plan: plan}, plan.text()), nil
 	}
 
 	poller, err := clients.ComputeClient.BeginStop(ctx, resourceGroupName, workspaceName, computeName, nil)
 	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("Failed to stop compute: %v", err)), nil
+		return azureError(ct.clients, "Failed to stop compute", err), nil
 	}
 
-	_, err = poller.PollUntilDone(ctx, nil)
+	if !request.GetBool("wait", true) {
+		op, err := ct.trackOperation(ctx, operations.Operation{
+			Kind:           operations.KindStopCompute,
+			SubscriptionID: subscriptionID,
+			ResourceGroup:  resourceGroupName,
+			Workspace:      workspaceName,
+			Target:         computeName,
+		}, sdkPoller[armmachinelearning.ComputeClientStopResponse]{poller})
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		return mcp.NewToolResultStructured(ComputeOperation{ComputeName: computeName, Action: "stop", Status: string(op.Status), OperationID: op.ID},
+			fmt.Sprintf("Stop of compute resource '%s' is %s. Operation ID: %s", computeName, op.Status, op.ID)), nil
+	}
+
+	err = ct.pollUntilDone(ctx, request, fmt.Sprintf("Stopping compute '%s'", computeName), sdkPoller[armmachinelearning.ComputeClientStopResponse]{poller})
 	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("Failed to stop compute: %v", err)), nil
+		return azureError(ct.clients, "Failed to stop compute", err), nil
+	}
+	if _, err := poller.Result(ctx); err != nil {
+		return azureError(ct.clients, "Failed to stop compute", err), nil
 	}
 
-	return mcp.NewToolResultText(fmt.Sprintf("Successfully stopped compute resource '%s'", computeName)), nil
+	return mcp.NewToolResultStructured(ComputeOperation{ComputeName: computeName, Action: "stop", Status: string(operations.StatusSucceeded)},
+		fmt.Sprintf("Successfully stopped compute resource '%s'", computeName)), nil
 }
@@ -1,35 +1,37 @@
 package tools_test
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/mark3labs/mcp-go/server"
+	"microsoft.com/aml-mcp/internal/azure"
 	"microsoft.com/aml-mcp/internal/tools"
 )
 
 func TestComputeTools_AddToServer(t *testing.T) {
 	s := server.NewMCPServer("test", "1.0.0")
-	computeTools := tools.NewComputeTools()
+	computeTools := tools.NewComputeTools(azure.NewClientCache())
 
 	// Test that AddToServer doesn't panic
 	computeTools.AddToServer(s)
 }
 
 func TestComputeTools_New(t *testing.T) {
-	computeTools := tools.NewComputeTools()
+	computeTools := tools.NewComputeTools(azure.NewClientCache())
 	if computeTools == nil {
 		t.Error("NewComputeTools() returned nil")
 	}
 }
 
 func TestComputeToolsStructure(t *testing.T) {
-	ct := tools.NewComputeTools()
+	ct := tools.NewComputeTools(azure.NewClientCache())
 	if ct == nil {
 		t.Fatal("NewComputeTools() returned nil")
 	}
 
 	// Test that we can create multiple instances
-	ct2 := tools.NewComputeTools()
+	ct2 := tools.NewComputeTools(azure.NewClientCache())
 	if ct2 == nil {
 		t.Fatal("Second NewComputeTools() returned nil")
 	}
@@ -39,3 +41,79 @@ func TestComputeToolsStructure(t *testing.T) {
 		t.Error("NewComputeTools() returned the same instance")
 	}
 }
+
+func TestComputeToolHandlers(t *testing.T) {
+	fake := newFakeARM(t)
+	s := server.NewMCPServer("test", "1.0.0")
+	tools.NewComputeTools(fake.ClientCache()).AddToServer(s)
+
+	tests := []struct {
+		name      string
+		toolName  string
+		args      map[string]any
+		wantError bool
+		contains  []string
+		wantState string
+	}{
+		{
+			name:     "list compute",
+			toolName: "list_compute",
+			args:     workspaceArgs(nil),
+			contains: []string{"Found 1 compute resources", "Name: test-ci, Type: ComputeInstance, Location: eastus, State: Succeeded"},
+		},
+		{
+			name:     "get compute",
+			toolName: "get_compute",
+			args:     workspaceArgs(map[string]any{"compute_name": testCompute}),
+			contains: []string{"Name: test-ci", "Type: ComputeInstance", "Is Attached: false"},
+		},
+		{
+			name:      "get missing compute",
+			toolName:  "get_compute",
+			args:      workspaceArgs(map[string]any{"compute_name": "missing"}),
+			wantError: true,
+			contains:  []string{"Failed to get compute resource", "ResourceNotFound"},
+		},
+		{
+			name:      "start compute",
+			toolName:  "start_compute",
+			args:      workspaceArgs(map[string]any{"compute_name": testCompute}),
+			contains:  []string{"Successfully started compute resource 'test-ci'"},
+			wantState: "Running",
+		},
+		{
+			name:      "stop compute",
+			toolName:  "stop_compute",
+			args:      workspaceArgs(map[string]any{"compute_name": testCompute}),
+			contains:  []string{"Successfully stopped compute resource 'test-ci'"},
+			wantState: "Stopped",
+		},
+		{
+			name:      "stop compute missing compute_name",
+			toolName:  "stop_compute",
+			args:      workspaceArgs(nil),
+			wantError: true,
+			contains:  []string{"compute_name"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := callTool(t, s, tt.toolName, tt.args)
+			text := resultText(result)
+			if result.IsError != tt.wantError {
+				t.Fatalf("IsError = %v, want %v (text: %s)", result.IsError, tt.wantError, text)
+			}
+			for _, want := range tt.contains {
+				if !strings.Contains(text, want) {
+					t.Errorf("result %q does not contain %q", text, want)
+				}
+			}
+			if tt.wantState != "" {
+				if got := fake.ComputeState(testSubscriptionID, testResourceGroup, testWorkspace, testCompute); got != tt.wantState {
+					t.Errorf("compute state = %q, want %q", got, tt.wantState)
+				}
+			}
+		})
+	}
+}
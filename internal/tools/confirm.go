@@ -0,0 +1,168 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"microsoft.com/aml-mcp/internal/azure"
+)
+
+// Statuses reported by mutating tools that returned a plan instead of acting
+const (
+	StatusDryRun               = "DryRun"
+	StatusConfirmationRequired = "ConfirmationRequired"
+)
+
+const (
+	argDryRun       = "dry_run"
+	argConfirmToken = "confirm_token"
+)
+
+// Descriptions of the arguments every mutating tool accepts
+const (
+	dryRunDescription       = "Validate the arguments and return the ARM request that would be sent, without sending it"
+	confirmTokenDescription = "Token returned by an earlier dry run or confirmation prompt for this exact request. Confirms the change when the client cannot prompt the user"
+)
+
+// planMutation returns the plan to return for a dry run or unconfirmed call, a failed result, or neither to go ahead
+func (s shared) planMutation(ctx context.Context, request mcp.CallToolRequest, clients *azure.ClientCache, subscriptionID, summary string, send func(*azure.ClientSet) error) (*MutationPlan, *mcp.CallToolResult) {
+	dryRun := request.GetBool(argDryRun, false)
+	if !dryRun && s.confirmations == nil {
+		return nil, nil
+	}
+
+	captured, err := clients.Capture(ctx, subscriptionID, send)
+	if err != nil {
+		return nil, azureError(clients, "Failed to prepare the request", err)
+	}
+	plan := newMutationPlan(request.Params.Name, summary, captured)
+	plan.DryRun = dryRun
+	if s.confirmations == nil {
+		return plan, nil
+	}
+
+	fingerprint := strings.Join([]string{sessionIDFromContext(ctx), plan.Tool, captured.Method, captured.URL, string(captured.Body)}, "\n")
+
+	if !dryRun {
+		if token := request.GetString(argConfirmToken, ""); token != "" {
+			if err := s.confirmations.Redeem(token, fingerprint); err != nil {
+				return nil, mcp.NewToolResultError(fmt.Sprintf("%v; call %s again without it to get a new one", err, plan.Tool))
+			}
+			return nil, nil
+		}
+
+		if confirmed, ok := elicitConfirmation(ctx, plan); ok {
+			if !confirmed {
+				return nil, mcp.NewToolResultError(fmt.Sprintf("%s was not confirmed; nothing was changed", plan.Tool))
+			}
+			return nil, nil
+		}
+	}
+
+	token, expiresAt := s.confirmations.Issue(fingerprint)
+	plan.ConfirmToken = token
+	plan.ExpiresAt = &expiresAt
+	return plan, nil
+}
+
+// elicitConfirmation asks the user to confirm the plan; ok is false if the client could not be asked
+func elicitConfirmation(ctx context.Context, plan *MutationPlan) (confirmed, ok bool) {
+	mcpServer := server.ServerFromContext(ctx)
+	session := server.ClientSessionFromContext(ctx)
+	if mcpServer == nil || session == nil {
+		return false, false
+	}
+	if _, ok := session.(server.SessionWithElicitation); !ok {
+		return false, false
+	}
+	if info, ok := session.(server.SessionWithClientInfo); !ok || info.GetClientCapabilities().Elicitation == nil {
+		return false, false
+	}
+
+	result, err := mcpServer.RequestElicitation(ctx, mcp.ElicitationRequest{
+		Params: mcp.ElicitationParams{
+			Message: fmt.Sprintf("%s. This will send:\n%s", plan.Summary, plan.Request.text()),
+			RequestedSchema: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"confirm": map[string]any{
+						"type":        "boolean",
+						"title":       "Confirm",
+						"description": "Make this change in Azure",
+					},
+				},
+				"required": []string{"confirm"},
+			},
+		},
+	})
+	if err != nil {
+		return false, false
+	}
+	if result.Action != mcp.ElicitationResponseActionAccept {
+		return false, true
+	}
+	content, _ := result.Content.(map[string]any)
+	confirm, _ := content["confirm"].(bool)
+	return confirm, true
+}
+
+func newMutationPlan(tool, summary string, captured *azure.CapturedRequest) *MutationPlan {
+	plan := &MutationPlan{
+		Tool:    tool,
+		Summary: summary,
+		Request: ARMRequest{Method: captured.Method, URL: captured.URL},
+	}
+	if len(captured.Body) > 0 {
+		var body any
+		if err := json.Unmarshal(captured.Body, &body); err == nil {
+			plan.Request.Body = body
+		} else {
+			plan.Request.Body = string(captured.Body)
+		}
+	}
+	return plan
+}
+
+// status is the status reported by the tool that returned the plan
+func (p *MutationPlan) status() string {
+	if p.DryRun {
+		return StatusDryRun
+	}
+	return StatusConfirmationRequired
+}
+
+// text renders the plan for the text output of a tool result
+func (p *MutationPlan) text() string {
+	var b strings.Builder
+	if p.DryRun {
+		fmt.Fprintf(&b, "Dry run: %s. Nothing was changed. The following request would be sent:\n", p.Summary)
+	} else {
+		fmt.Fprintf(&b, "Confirmation required: %s. Nothing was changed yet. The following request will be sent:\n", p.Summary)
+	}
+	b.WriteString(p.Request.text())
+	if p.ConfirmToken != "" {
+		arguments := "the same arguments"
+		if p.DryRun {
+			arguments += " without dry_run"
+		}
+		fmt.Fprintf(&b, "\nTo proceed, call %s again with %s and confirm_token %q (valid until %s).",
+			p.Tool, arguments, p.ConfirmToken, p.ExpiresAt.UTC().Format("15:04:05 UTC"))
+	}
+	return b.String()
+}
+
+func (r ARMRequest) text() string {
+	line := r.Method + " " + r.URL
+	if r.Body == nil {
+		return line
+	}
+	body, err := json.MarshalIndent(r.Body, "", "  ")
+	if err != nil {
+		return line
+	}
+	return line + "\n" + string(body)
+}
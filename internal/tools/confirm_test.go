@@ -0,0 +1,198 @@
+package tools_test
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"microsoft.com/aml-mcp/internal/confirm"
+	"microsoft.com/aml-mcp/internal/fakearm"
+	"microsoft.com/aml-mcp/internal/tools"
+)
+
+// newConfirmingServer registers the workspace and compute tools against a fake
+// ARM server with confirmations required
+func newConfirmingServer(t *testing.T) (*fakearm.Server, *server.MCPServer) {
+	t.Helper()
+
+	fake := newFakeARM(t)
+	clients := fake.ClientCache()
+	opts := []tools.Option{
+		tools.WithConfirmations(confirm.NewStore(confirm.DefaultTTL)),
+		tools.WithPollInterval(10 * time.Millisecond),
+	}
+
+	s := server.NewMCPServer("test", "1.0.0", server.WithElicitation())
+	tools.NewWorkspaceTools(clients, opts...).AddToServer(s)
+	tools.NewComputeTools(clients, opts...).AddToServer(s)
+	return fake, s
+}
+
+// elicitingSession is a client session that answers elicitation requests
+// with a fixed response
+type elicitingSession struct {
+	*testSession
+	response mcp.ElicitationResponse
+	messages []string
+}
+
+func (s *elicitingSession) RequestElicitation(ctx context.Context, request mcp.ElicitationRequest) (*mcp.ElicitationResult, error) {
+	s.messages = append(s.messages, request.Params.Message)
+	return &mcp.ElicitationResult{ElicitationResponse: s.response}, nil
+}
+
+func (s *elicitingSession) GetClientInfo() mcp.Implementation            { return mcp.Implementation{} }
+func (s *elicitingSession) SetClientInfo(mcp.Implementation)             {}
+func (s *elicitingSession) SetClientCapabilities(mcp.ClientCapabilities) {}
+func (s *elicitingSession) GetClientCapabilities() mcp.ClientCapabilities {
+	return mcp.ClientCapabilities{Elicitation: &struct{}{}}
+}
+
+func TestDryRun(t *testing.T) {
+	fake := newFakeARM(t)
+	s := server.NewMCPServer("test", "1.0.0")
+	tools.NewWorkspaceTools(fake.ClientCache()).AddToServer(s)
+	tools.NewComputeTools(fake.ClientCache()).AddToServer(s)
+
+	created := structuredAs[tools.WorkspaceCreation](t, callTool(t, s, "create_workspace", map[string]any{
+		"subscription_id":     testSubscriptionID,
+		"resource_group_name": testResourceGroup,
+		"workspace_name":      "new-ws",
+		"location":            "westus2",
+		"dry_run":             true,
+	}))
+	if created.Status != tools.StatusDryRun || created.Plan == nil {
+		t.Fatalf("create_workspace dry run = %+v, want status %s with a plan", created, tools.StatusDryRun)
+	}
+	plan := created.Plan
+	if plan.Request.Method != http.MethodPut {
+		t.Errorf("method = %q, want PUT", plan.Request.Method)
+	}
+	wantURL := fakearm.WorkspaceID(testSubscriptionID, testResourceGroup, "new-ws") + "?api-version="
+	if !strings.Contains(plan.Request.URL, wantURL) {
+		t.Errorf("URL = %q, want it to contain %q", plan.Request.URL, wantURL)
+	}
+	body, _ := plan.Request.Body.(map[string]any)
+	if body["location"] != "westus2" {
+		t.Errorf("body = %v, want location westus2", plan.Request.Body)
+	}
+	if plan.ConfirmToken != "" {
+		t.Errorf("confirm token = %q without confirmations enabled", plan.ConfirmToken)
+	}
+	if _, ok := fake.Workspace(testSubscriptionID, testResourceGroup, "new-ws"); ok {
+		t.Error("dry run created the workspace")
+	}
+
+	start := structuredAs[tools.ComputeOperation](t,
+		callTool(t, s, "start_compute", workspaceArgs(map[string]any{"compute_name": testCompute, "dry_run": true})))
+	if start.Status != tools.StatusDryRun || start.Plan == nil {
+		t.Fatalf("start_compute dry run = %+v, want status %s with a plan", start, tools.StatusDryRun)
+	}
+	if start.Plan.Request.Method != http.MethodPost || !strings.Contains(start.Plan.Request.URL, "/computes/"+testCompute+"/start?") {
+		t.Errorf("request = %s %s, want POST to the start action", start.Plan.Request.Method, start.Plan.Request.URL)
+	}
+
+	if requests := fake.Requests(); len(requests) != 0 {
+		t.Errorf("dry runs sent %d requests to ARM, want none", len(requests))
+	}
+}
+
+func TestConfirmToken(t *testing.T) {
+	fake, s := newConfirmingServer(t)
+	args := workspaceArgs(map[string]any{"compute_name": testCompute})
+
+	pending := structuredAs[tools.ComputeOperation](t, callTool(t, s, "start_compute", args))
+	if pending.Status != tools.StatusConfirmationRequired || pending.Plan == nil || pending.Plan.ConfirmToken == "" {
+		t.Fatalf("start_compute = %+v, want status %s with a confirm token", pending, tools.StatusConfirmationRequired)
+	}
+	if got := fake.ComputeState(testSubscriptionID, testResourceGroup, testWorkspace, testCompute); got != "Stopped" {
+		t.Fatalf("compute state = %q before confirmation, want Stopped", got)
+	}
+	token := pending.Plan.ConfirmToken
+
+	// A token only confirms the request it was issued for
+	result := callTool(t, s, "stop_compute", workspaceArgs(map[string]any{"compute_name": testCompute, "confirm_token": token}))
+	if !result.IsError || !strings.Contains(resultText(result), "different request") {
+		t.Errorf("stop_compute with a start token = %s, want a mismatch error", resultText(result))
+	}
+
+	args["confirm_token"] = token
+	started := structuredAs[tools.ComputeOperation](t, callTool(t, s, "start_compute", args))
+	if started.Status != "Succeeded" {
+		t.Fatalf("start_compute with confirm token status = %q, want Succeeded", started.Status)
+	}
+	if got := fake.ComputeState(testSubscriptionID, testResourceGroup, testWorkspace, testCompute); got != "Running" {
+		t.Errorf("compute state = %q after confirmation, want Running", got)
+	}
+
+	result = callTool(t, s, "start_compute", args)
+	if !result.IsError || !strings.Contains(resultText(result), "already used") {
+		t.Errorf("reusing a confirm token = %s, want an error", resultText(result))
+	}
+}
+
+func TestDryRunIssuesConfirmToken(t *testing.T) {
+	fake, s := newConfirmingServer(t)
+
+	planned := structuredAs[tools.ComputeOperation](t,
+		callTool(t, s, "start_compute", workspaceArgs(map[string]any{"compute_name": testCompute, "dry_run": true})))
+	if planned.Status != tools.StatusDryRun || planned.Plan == nil || planned.Plan.ConfirmToken == "" {
+		t.Fatalf("start_compute dry run = %+v, want status %s with a confirm token", planned, tools.StatusDryRun)
+	}
+
+	started := structuredAs[tools.ComputeOperation](t,
+		callTool(t, s, "start_compute", workspaceArgs(map[string]any{"compute_name": testCompute, "confirm_token": planned.Plan.ConfirmToken})))
+	if started.Status != "Succeeded" {
+		t.Fatalf("start_compute with the dry run's token status = %q, want Succeeded", started.Status)
+	}
+	if got := fake.ComputeState(testSubscriptionID, testResourceGroup, testWorkspace, testCompute); got != "Running" {
+		t.Errorf("compute state = %q, want Running", got)
+	}
+}
+
+func TestConfirmElicitation(t *testing.T) {
+	tests := []struct {
+		name      string
+		response  mcp.ElicitationResponse
+		wantState string
+	}{
+		{
+			name:      "accepted",
+			response:  mcp.ElicitationResponse{Action: mcp.ElicitationResponseActionAccept, Content: map[string]any{"confirm": true}},
+			wantState: "Running",
+		},
+		{
+			name:      "accepted without confirming",
+			response:  mcp.ElicitationResponse{Action: mcp.ElicitationResponseActionAccept, Content: map[string]any{"confirm": false}},
+			wantState: "Stopped",
+		},
+		{
+			name:      "declined",
+			response:  mcp.ElicitationResponse{Action: mcp.ElicitationResponseActionDecline},
+			wantState: "Stopped",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake, s := newConfirmingServer(t)
+			session := &elicitingSession{testSession: newTestSession("session-1"), response: tt.response}
+			ctx := s.WithContext(context.Background(), session)
+
+			result := callToolInContext(ctx, t, s, "start_compute", workspaceArgs(map[string]any{"compute_name": testCompute}))
+			if len(session.messages) != 1 || !strings.Contains(session.messages[0], "/start?") {
+				t.Errorf("elicitation messages = %q, want one describing the start request", session.messages)
+			}
+			if confirmed := tt.wantState == "Running"; result.IsError == confirmed {
+				t.Errorf("start_compute IsError = %v (%s)", result.IsError, resultText(result))
+			}
+			if got := fake.ComputeState(testSubscriptionID, testResourceGroup, testWorkspace, testCompute); got != tt.wantState {
+				t.Errorf("compute state = %q, want %q", got, tt.wantState)
+			}
+		})
+	}
+}
@@ -0,0 +1,237 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"microsoft.com/aml-mcp/internal/azure"
+	"microsoft.com/aml-mcp/internal/helpers"
+	"microsoft.com/aml-mcp/internal/session"
+)
+
+// Arguments that identify what a tool acts on
+const (
+	argSubscriptionID = "subscription_id"
+	argResourceGroup  = "resource_group_name"
+	argWorkspace      = "workspace_name"
+	argCompute        = "compute_name"
+	// argResourceID is a full ARM resource ID that overrides the arguments it contains
+	argResourceID = "resource_id"
+)
+
+// Resource types whose names are read from resource_id
+const (
+	workspaceResourceType = "Microsoft.MachineLearningServices/workspaces"
+	computeResourceType   = workspaceResourceType + "/computes"
+)
+
+// ContextTools contains the tools for managing a session's active workspace
+type ContextTools struct {
+	clients *azure.ClientCache
+	shared
+}
+
+// NewContextTools creates a new ContextTools instance backed by a shared client cache
+func NewContextTools(clients *azure.ClientCache, opts ...Option) *ContextTools {
+	return &ContextTools{clients: clients, shared: newShared(opts)}
+}
+
+// AddToServer registers all context tools with the MCP server
+func (xt *ContextTools) AddToServer(s Registrar) {
+	xt.addSetActiveWorkspaceTool(s)
+	xt.addGetActiveWorkspaceTool(s)
+}
+
+func (xt *ContextTools) addSetActiveWorkspaceTool(s Registrar) {
+	tool := mcp.NewTool("set_active_workspace",
+		mcp.WithDescription("Set the subscription, resource group and workspace other tools use when those arguments are left out. Applies to this session only."),
+		// Only changes this server's session state, never Azure resources
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(false),
+		mcp.WithOutputSchema[ActiveWorkspace](),
+		mcp.WithString(argResourceID,
+			mcp.Description("Full ARM resource ID of the workspace. Takes precedence over subscription_id, resource_group_name and workspace_name"),
+		),
+		mcp.WithString(argSubscriptionID,
+			mcp.Description("Azure subscription ID"),
+		),
+		mcp.WithString(argResourceGroup,
+			mcp.Description("Resource group name"),
+		),
+		mcp.WithString(argWorkspace,
+			mcp.Description("Workspace name. The workspace is checked to exist before it is made active."),
+		),
+		mcp.WithBoolean("clear",
+			mcp.Description("Clear the session's active workspace before applying the other arguments, falling back to the configured defaults"),
+		),
+	)
+
+	s.AddTool(tool, xt.handleSetActiveWorkspace)
+}
+
+func (xt *ContextTools) addGetActiveWorkspaceTool(s Registrar) {
+	tool := mcp.NewTool("get_active_workspace",
+		mcp.WithDescription("Show the subscription, resource group and workspace tools use when those arguments are left out"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(false),
+		mcp.WithOutputSchema[ActiveWorkspace](),
+	)
+
+	s.AddTool(tool, xt.handleGetActiveWorkspace)
+}
+
+func (xt *ContextTools) handleSetActiveWorkspace(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	sessionID := sessionIDFromContext(ctx)
+
+	active := xt.sessions.Get(sessionID)
+	if request.GetBool("clear", false) {
+		active = session.Workspace{}
+	}
+	update := session.Workspace{
+		SubscriptionID: request.GetString(argSubscriptionID, ""),
+		ResourceGroup:  request.GetString(argResourceGroup, ""),
+		Workspace:      request.GetString(argWorkspace, ""),
+	}
+	if id := request.GetString(argResourceID, ""); id != "" {
+		parsed, err := helpers.ParseResourceID(id)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("invalid resource_id: %v", err)), nil
+		}
+		update = update.Merge(session.Workspace{
+			SubscriptionID: parsed.SubscriptionID,
+			ResourceGroup:  parsed.ResourceGroup,
+			Workspace:      parsed.NameOf(workspaceResourceType),
+		})
+	}
+	active = active.Merge(update)
+
+	// Check a newly named workspace exists
+	if update.Workspace != "" {
+		effective := xt.defaults.Merge(active)
+		if effective.SubscriptionID == "" || effective.ResourceGroup == "" {
+			return mcp.NewToolResultError("subscription_id and resource_group_name are required to set an active workspace"), nil
+		}
+		clients, err := xt.clients.Get(ctx, effective.SubscriptionID)
+		if err != nil {
+			return azureError(xt.clients, "Failed to sign in to Azure", err), nil
+		}
+		if _, err := clients.WorkspacesClient.Get(ctx, effective.ResourceGroup, effective.Workspace, nil); err != nil {
+			return azureError(xt.clients, "Failed to get workspace", err), nil
+		}
+	}
+
+	xt.sessions.Set(sessionID, active)
+	return xt.activeWorkspaceResult(active, "Active workspace updated."), nil
+}
+
+func (xt *ContextTools) handleGetActiveWorkspace(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return xt.activeWorkspaceResult(xt.sessions.Get(sessionIDFromContext(ctx)), "Active workspace:"), nil
+}
+
+func (xt *ContextTools) activeWorkspaceResult(active session.Workspace, heading string) *mcp.CallToolResult {
+	result := ActiveWorkspace{
+		Effective: xt.defaults.Merge(active),
+		Session:   active,
+		Defaults:  xt.defaults,
+	}
+	text := fmt.Sprintf(`%s
+Subscription ID: %s
+Resource Group: %s
+Workspace: %s`,
+		heading,
+		orNA(result.Effective.SubscriptionID),
+		orNA(result.Effective.ResourceGroup),
+		orNA(result.Effective.Workspace))
+	return mcp.NewToolResultStructured(result, text)
+}
+
+// scopeArgument returns an identifying argument of a tool call, falling back to the active workspace and defaults
+func (s shared) scopeArgument(ctx context.Context, request mcp.CallToolRequest, name string) (string, error) {
+	if id := request.GetString(argResourceID, ""); id != "" {
+		value, err := resourceIDArgument(id, name)
+		if err != nil {
+			return "", err
+		}
+		if value != "" {
+			return value, nil
+		}
+	}
+	if value := request.GetString(name, ""); value != "" {
+		return value, nil
+	}
+
+	effective := s.defaults.Merge(s.sessions.Get(sessionIDFromContext(ctx)))
+	var value string
+	switch name {
+	case argSubscriptionID:
+		value = effective.SubscriptionID
+	case argResourceGroup:
+		value = effective.ResourceGroup
+	case argWorkspace:
+		value = effective.Workspace
+	default:
+		return "", fmt.Errorf("%s is required", name)
+	}
+	if value == "" {
+		return "", fmt.Errorf("%s is required: pass it or resource_id, call set_active_workspace, or configure a default", name)
+	}
+	return value, nil
+}
+
+// scopeAttrs returns the resolved subscription, resource group and workspace of a call as attributes
+func scopeAttrs[T any](s shared, ctx context.Context, request mcp.CallToolRequest, keys [3]string, attr func(key, value string) T) []T {
+	var attrs []T
+	for i, name := range scopeArguments {
+		if value, err := s.scopeArgument(ctx, request, name); err == nil {
+			attrs = append(attrs, attr(keys[i], value))
+		}
+	}
+	return attrs
+}
+
+// explicitArgument returns an identifying argument the call itself gives, without fallbacks
+func explicitArgument(request mcp.CallToolRequest, name string) (string, error) {
+	if id := request.GetString(argResourceID, ""); id != "" {
+		value, err := resourceIDArgument(id, name)
+		if err != nil {
+			return "", err
+		}
+		if value != "" {
+			return value, nil
+		}
+	}
+	if value := request.GetString(name, ""); value != "" {
+		return value, nil
+	}
+	return "", fmt.Errorf("%s is required: pass it or a resource_id naming the resource; the active workspace and defaults do not apply", name)
+}
+
+// resourceIDArgument returns an identifying argument contained in a resource ID, or ""
+func resourceIDArgument(id, name string) (string, error) {
+	parsed, err := helpers.ParseResourceID(id)
+	if err != nil {
+		return "", fmt.Errorf("invalid resource_id: %v", err)
+	}
+	switch name {
+	case argSubscriptionID:
+		return parsed.SubscriptionID, nil
+	case argResourceGroup:
+		return parsed.ResourceGroup, nil
+	case argWorkspace:
+		return parsed.NameOf(workspaceResourceType), nil
+	case argCompute:
+		return parsed.NameOf(computeResourceType), nil
+	default:
+		return "", nil
+	}
+}
+
+// sessionIDFromContext returns the ID of the client session making a tool call
+func sessionIDFromContext(ctx context.Context) string {
+	if s := server.ClientSessionFromContext(ctx); s != nil {
+		return s.SessionID()
+	}
+	return ""
+}
@@ -0,0 +1,204 @@
+package tools_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/server"
+	"microsoft.com/aml-mcp/internal/session"
+	"microsoft.com/aml-mcp/internal/tools"
+)
+
+// newContextServer registers the context and compute tools against a fake
+// ARM server, sharing one session store
+func newContextServer(t *testing.T, opts ...tools.Option) *server.MCPServer {
+	t.Helper()
+
+	clients := newFakeARM(t).ClientCache()
+	opts = append(opts, tools.WithSessions(session.NewStore()))
+
+	s := server.NewMCPServer("test", "1.0.0")
+	tools.NewContextTools(clients, opts...).AddToServer(s)
+	tools.NewComputeTools(clients, opts...).AddToServer(s)
+	tools.NewWorkspaceTools(clients, opts...).AddToServer(s)
+	return s
+}
+
+func TestActiveWorkspace(t *testing.T) {
+	s := newContextServer(t)
+	ctx := s.WithContext(context.Background(), newTestSession("session-1"))
+
+	result := callToolInContext(ctx, t, s, "list_compute", nil)
+	if !result.IsError || !strings.Contains(resultText(result), "set_active_workspace") {
+		t.Fatalf("list_compute without arguments = %s, want an error pointing at set_active_workspace", resultText(result))
+	}
+
+	result = callToolInContext(ctx, t, s, "set_active_workspace", workspaceArgs(nil))
+	if result.IsError {
+		t.Fatalf("set_active_workspace failed: %s", resultText(result))
+	}
+	active := structuredAs[tools.ActiveWorkspace](t, result)
+	want := session.Workspace{SubscriptionID: testSubscriptionID, ResourceGroup: testResourceGroup, Workspace: testWorkspace}
+	if active.Effective != want || active.Session != want {
+		t.Errorf("ActiveWorkspace = %+v, want %+v in effect for the session", active, want)
+	}
+
+	result = callToolInContext(ctx, t, s, "get_compute", map[string]any{"compute_name": testCompute})
+	if result.IsError {
+		t.Fatalf("get_compute with active workspace failed: %s", resultText(result))
+	}
+
+	// Explicit arguments still win over the active workspace
+	result = callToolInContext(ctx, t, s, "get_workspace", map[string]any{"workspace_name": "missing"})
+	if !result.IsError || !strings.Contains(resultText(result), "ResourceNotFound") {
+		t.Errorf("get_workspace with explicit workspace_name = %s, want ResourceNotFound", resultText(result))
+	}
+
+	// Other sessions are unaffected
+	other := s.WithContext(context.Background(), newTestSession("session-2"))
+	if result := callToolInContext(other, t, s, "list_compute", nil); !result.IsError {
+		t.Errorf("list_compute in another session succeeded: %s", resultText(result))
+	}
+
+	result = callToolInContext(ctx, t, s, "set_active_workspace", map[string]any{"clear": true})
+	if active := structuredAs[tools.ActiveWorkspace](t, result); !active.Effective.IsZero() {
+		t.Errorf("ActiveWorkspace after clear = %+v, want nothing in effect", active)
+	}
+}
+
+func TestSetActiveWorkspaceValidates(t *testing.T) {
+	s := newContextServer(t)
+	ctx := s.WithContext(context.Background(), newTestSession("session-1"))
+
+	result := callToolInContext(ctx, t, s, "set_active_workspace", workspaceArgs(map[string]any{"workspace_name": "missing"}))
+	if !result.IsError || !strings.Contains(resultText(result), "ResourceNotFound") {
+		t.Fatalf("set_active_workspace with a missing workspace = %s, want ResourceNotFound", resultText(result))
+	}
+
+	active := structuredAs[tools.ActiveWorkspace](t, callToolInContext(ctx, t, s, "get_active_workspace", nil))
+	if !active.Session.IsZero() {
+		t.Errorf("session workspace = %+v after a failed set, want nothing", active.Session)
+	}
+
+	result = callToolInContext(ctx, t, s, "set_active_workspace", map[string]any{"workspace_name": testWorkspace})
+	if !result.IsError || !strings.Contains(resultText(result), "subscription_id and resource_group_name are required") {
+		t.Errorf("set_active_workspace with only a workspace = %s, want a missing argument error", resultText(result))
+	}
+}
+
+func TestConfiguredDefaults(t *testing.T) {
+	defaults := session.Workspace{SubscriptionID: testSubscriptionID, ResourceGroup: testResourceGroup, Workspace: "missing"}
+	s := newContextServer(t, tools.WithDefaults(defaults))
+	ctx := s.WithContext(context.Background(), newTestSession("session-1"))
+
+	active := structuredAs[tools.ActiveWorkspace](t, callToolInContext(ctx, t, s, "get_active_workspace", nil))
+	if active.Effective != defaults || active.Defaults != defaults {
+		t.Errorf("ActiveWorkspace = %+v, want defaults %+v in effect", active, defaults)
+	}
+
+	// The session only overrides the workspace; the rest still comes from the defaults
+	if result := callToolInContext(ctx, t, s, "set_active_workspace", map[string]any{"workspace_name": testWorkspace}); result.IsError {
+		t.Fatalf("set_active_workspace failed: %s", resultText(result))
+	}
+	result := callToolInContext(ctx, t, s, "get_compute", map[string]any{"compute_name": testCompute})
+	if result.IsError {
+		t.Fatalf("get_compute with defaults failed: %s", resultText(result))
+	}
+}
+
+func TestResourceIDArgument(t *testing.T) {
+	s := newContextServer(t)
+	workspaceID := "/subscriptions/" + testSubscriptionID + "/resourceGroups/" + testResourceGroup +
+		"/providers/Microsoft.MachineLearningServices/workspaces/" + testWorkspace
+	computeID := workspaceID + "/computes/" + testCompute
+
+	tests := []struct {
+		name      string
+		toolName  string
+		args      map[string]any
+		wantError bool
+		contains  []string
+	}{
+		{
+			name:     "workspace ID",
+			toolName: "get_workspace",
+			args:     map[string]any{"resource_id": workspaceID},
+			contains: []string{"Name: test-ws"},
+		},
+		{
+			name:     "compute ID",
+			toolName: "get_compute",
+			args:     map[string]any{"resource_id": computeID},
+			contains: []string{"Name: test-ci"},
+		},
+		{
+			name:     "compute ID for a workspace-scoped tool",
+			toolName: "list_compute",
+			args:     map[string]any{"resource_id": computeID},
+			contains: []string{"Found 1 compute resources"},
+		},
+		{
+			name:     "workspace ID with compute_name",
+			toolName: "get_compute",
+			args:     map[string]any{"resource_id": workspaceID, "compute_name": testCompute},
+			contains: []string{"Name: test-ci"},
+		},
+		{
+			name:     "resource ID takes precedence over arguments",
+			toolName: "get_workspace",
+			args:     workspaceArgs(map[string]any{"resource_id": workspaceID, "workspace_name": "missing"}),
+			contains: []string{"Name: test-ws"},
+		},
+		{
+			name:     "subscription ID for a subscription-scoped tool",
+			toolName: "list_workspaces_by_subscription",
+			args:     map[string]any{"resource_id": "/subscriptions/" + testSubscriptionID},
+			contains: []string{"Found 1 Azure ML workspaces"},
+		},
+		{
+			name:      "workspace ID for a compute tool without compute_name",
+			toolName:  "get_compute",
+			args:      map[string]any{"resource_id": workspaceID},
+			wantError: true,
+			contains:  []string{"compute_name is required"},
+		},
+		{
+			name:      "malformed resource ID",
+			toolName:  "get_workspace",
+			args:      map[string]any{"resource_id": "/resourceGroups/" + testResourceGroup},
+			wantError: true,
+			contains:  []string{"invalid resource_id"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := callTool(t, s, tt.toolName, tt.args)
+			text := resultText(result)
+			if result.IsError != tt.wantError {
+				t.Fatalf("IsError = %v, want %v (text: %s)", result.IsError, tt.wantError, text)
+			}
+			for _, want := range tt.contains {
+				if !strings.Contains(text, want) {
+					t.Errorf("result %q does not contain %q", text, want)
+				}
+			}
+		})
+	}
+}
+
+func TestSetActiveWorkspaceFromResourceID(t *testing.T) {
+	s := newContextServer(t)
+	workspaceID := "/subscriptions/" + testSubscriptionID + "/resourceGroups/" + testResourceGroup +
+		"/providers/Microsoft.MachineLearningServices/workspaces/" + testWorkspace
+
+	result := callTool(t, s, "set_active_workspace", map[string]any{"resource_id": workspaceID})
+	if result.IsError {
+		t.Fatalf("set_active_workspace failed: %s", resultText(result))
+	}
+	want := session.Workspace{SubscriptionID: testSubscriptionID, ResourceGroup: testResourceGroup, Workspace: testWorkspace}
+	if got := structuredAs[tools.ActiveWorkspace](t, result).Session; got != want {
+		t.Errorf("session workspace = %+v, want %+v", got, want)
+	}
+}
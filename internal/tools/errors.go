@@ -0,0 +1,155 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"microsoft.com/aml-mcp/internal/azure"
+)
+
+// Error codes reported in ToolError.Code
+const (
+	ErrorUnauthenticated  = "Unauthenticated"
+	ErrorPermissionDenied = "PermissionDenied"
+	ErrorNotFound         = "NotFound"
+	ErrorQuotaExceeded    = "QuotaExceeded"
+	ErrorConflict         = "Conflict"
+	ErrorThrottled        = "Throttled"
+	ErrorInvalidArgument  = "InvalidArgument"
+	ErrorUnavailable      = "Unavailable"
+	ErrorCancelled        = "Cancelled"
+	ErrorUnknown          = "Unknown"
+)
+
+// errorMetaKey is the _meta field of a failed tool result holding its ToolError
+const errorMetaKey = "error"
+
+// ARM error codes that are not distinguished by their HTTP status alone
+var (
+	permissionDeniedCodes = []string{"AuthorizationFailed", "LinkedAuthorizationFailed", "InsufficientPermissions", "Forbidden"}
+	notFoundCodes         = []string{"ResourceNotFound", "ResourceGroupNotFound", "SubscriptionNotFound", "ParentResourceNotFound", "NotFound"}
+	conflictCodes         = []string{"Conflict", "AnotherOperationInProgress", "ResourceGroupBeingDeleted", "ScopeLocked", "UserError_ComputeInUse"}
+	throttledCodes        = []string{"TooManyRequests", "Throttled", "RequestThrottled", "SubscriptionRequestsThrottled"}
+)
+
+// errInvalidArgument marks argument errors found once the call is under way
+var errInvalidArgument = errors.New("invalid argument")
+
+// azureError builds the tool result for a failed Azure call
+func azureError(clients *azure.ClientCache, message string, err error) *mcp.CallToolResult {
+	toolErr := newToolError(err)
+	if clients.InvalidateOnAuthError(err) {
+		toolErr.credentialDiscarded()
+	}
+	return toolErrorResult(message, toolErr)
+}
+
+// toolErrorResult builds a failed tool result reporting toolErr
+func toolErrorResult(message string, toolErr ToolError) *mcp.CallToolResult {
+	result := mcp.NewToolResultError(fmt.Sprintf("%s: %s", message, toolErr.text()))
+	result.Meta = mcp.NewMetaFromMap(map[string]any{errorMetaKey: toolErr})
+	return result
+}
+
+// newToolError translates err into a concise ToolError
+func newToolError(err error) ToolError {
+	switch {
+	case errors.Is(err, context.Canceled):
+		return ToolError{Code: ErrorCancelled, Message: "the call was cancelled"}
+	case errors.Is(err, errInvalidArgument):
+		return ToolError{Code: ErrorInvalidArgument, Message: err.Error()}
+	case errors.Is(err, context.DeadlineExceeded):
+		return ToolError{
+			Code:    ErrorUnavailable,
+			Message: "the call timed out",
+			Hint:    "Azure did not finish in time. A change may still be in progress, so check the resource before retrying.",
+		}
+	}
+
+	details, ok := azure.ParseResponseError(err)
+	if !ok {
+		if azure.IsAuthError(err) {
+			return ToolError{
+				Code:    ErrorUnauthenticated,
+				Message: err.Error(),
+				Hint:    "Sign in to Azure again (for example with az login), then retry.",
+			}
+		}
+		return ToolError{Code: ErrorUnknown, Message: err.Error()}
+	}
+
+	toolErr := ToolError{
+		Message:    details.Message,
+		StatusCode: details.StatusCode,
+		ARMCode:    details.Code,
+		RequestID:  details.RequestID,
+	}
+	switch {
+	case details.StatusCode == http.StatusUnauthorized:
+		toolErr.Code = ErrorUnauthenticated
+		toolErr.Hint = "Azure rejected the credential. Sign in to Azure again (for example with az login), then retry."
+	case containsFold(permissionDeniedCodes, details.Code) || details.StatusCode == http.StatusForbidden:
+		toolErr.Code = ErrorPermissionDenied
+		toolErr.Hint = "The signed-in identity has no role on this scope that allows the action. Ask an owner of the resource for a role assignment, or use a resource you have access to."
+	case strings.Contains(strings.ToLower(details.Code), "quota"):
+		toolErr.Code = ErrorQuotaExceeded
+		toolErr.Hint = "Check list_usage and list_quotas for the region, then pick a smaller VM size or another region, or request a quota increase."
+	case containsFold(throttledCodes, details.Code) || details.StatusCode == http.StatusTooManyRequests:
+		toolErr.Code = ErrorThrottled
+		toolErr.Hint = "Azure is throttling requests. Wait before retrying."
+		if details.RetryAfter > 0 {
+			toolErr.Hint = fmt.Sprintf("Azure is throttling requests. Wait %s before retrying.", details.RetryAfter.Round(time.Second))
+		}
+	case containsFold(notFoundCodes, details.Code) || details.StatusCode == http.StatusNotFound:
+		toolErr.Code = ErrorNotFound
+		toolErr.Hint = "Check the subscription, resource group and resource names. list_workspaces_by_subscription and list_compute show what exists."
+	case containsFold(conflictCodes, details.Code) || details.StatusCode == http.StatusConflict:
+		toolErr.Code = ErrorConflict
+		toolErr.Hint = "The resource is busy or in a state that does not allow this change. Wait for running operations to finish, check its state, then retry."
+	case details.StatusCode == http.StatusBadRequest:
+		toolErr.Code = ErrorInvalidArgument
+		toolErr.Hint = "Azure rejected an argument; the message names it. Correct it and retry."
+	case details.StatusCode >= http.StatusInternalServerError:
+		toolErr.Code = ErrorUnavailable
+		toolErr.Hint = "Azure reported a transient failure. Retry later."
+	default:
+		toolErr.Code = ErrorUnknown
+	}
+	return toolErr
+}
+
+// credentialDiscarded notes in the hint that the cached credential was dropped
+func (e *ToolError) credentialDiscarded() {
+	e.Hint = strings.TrimSpace(e.Hint + " The cached credential has been discarded.")
+}
+
+// text renders the error for the text output of a tool result
+func (e ToolError) text() string {
+	var b strings.Builder
+	b.WriteString(e.Message)
+	if e.ARMCode != "" {
+		fmt.Fprintf(&b, " (%s)", e.ARMCode)
+	}
+	fmt.Fprintf(&b, "\nError code: %s", e.Code)
+	if e.RequestID != "" {
+		fmt.Fprintf(&b, "\nRequest ID: %s", e.RequestID)
+	}
+	if e.Hint != "" {
+		fmt.Fprintf(&b, "\nHint: %s", e.Hint)
+	}
+	return b.String()
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,160 @@
+package tools_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"microsoft.com/aml-mcp/internal/azure"
+	"microsoft.com/aml-mcp/internal/fakearm"
+	"microsoft.com/aml-mcp/internal/tools"
+)
+
+// toolError decodes the ToolError in the _meta of a failed tool result
+func toolError(t *testing.T, result mcp.CallToolResult) tools.ToolError {
+	t.Helper()
+
+	if !result.IsError || result.Meta == nil {
+		t.Fatalf("result is not a failed Azure call: %s", resultText(result))
+	}
+	data, err := json.Marshal(result.Meta.AdditionalFields["error"])
+	if err != nil {
+		t.Fatalf("failed to marshal error: %v", err)
+	}
+	var out tools.ToolError
+	if err := json.Unmarshal(data, &out); err != nil {
+		t.Fatalf("failed to decode error %s: %v", data, err)
+	}
+	return out
+}
+
+func TestAzureErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		failure  fakearm.Failure
+		wantCode string
+		wantHint string
+	}{
+		{
+			name:     "authorization failed",
+			failure:  fakearm.Failure{Status: http.StatusForbidden, Code: "AuthorizationFailed", Message: "The client does not have authorization"},
+			wantCode: tools.ErrorPermissionDenied,
+			wantHint: "role assignment",
+		},
+		{
+			name:     "quota exceeded",
+			failure:  fakearm.Failure{Status: http.StatusBadRequest, Code: "QuotaExceeded", Message: "Not enough quota for Standard_DS3_v2"},
+			wantCode: tools.ErrorQuotaExceeded,
+			wantHint: "list_usage",
+		},
+		{
+			name:     "conflict",
+			failure:  fakearm.Failure{Status: http.StatusConflict, Code: "Conflict", Message: "Another operation is in progress"},
+			wantCode: tools.ErrorConflict,
+			wantHint: "Wait for running operations",
+		},
+		{
+			name: "throttled",
+			failure: fakearm.Failure{
+				Status: http.StatusTooManyRequests, Code: "TooManyRequests", Message: "Rate limit exceeded",
+				Header: http.Header{"Retry-After": []string{"17"}},
+			},
+			wantCode: tools.ErrorThrottled,
+			wantHint: "Wait 17s",
+		},
+		{
+			name:     "unavailable",
+			failure:  fakearm.Failure{Status: http.StatusServiceUnavailable, Code: "ServiceUnavailable", Message: "Try again later"},
+			wantCode: tools.ErrorUnavailable,
+			wantHint: "transient",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := newFakeARM(t)
+			fake.AddFailure(tt.failure)
+			s := server.NewMCPServer("test", "1.0.0")
+			tools.NewComputeTools(fake.ClientCache()).AddToServer(s)
+
+			result := callTool(t, s, "get_compute", workspaceArgs(map[string]any{"compute_name": testCompute}))
+			got := toolError(t, result)
+			if got.Code != tt.wantCode || got.ARMCode != tt.failure.Code || got.StatusCode != tt.failure.Status {
+				t.Errorf("error = %+v, want code %s from ARM code %s", got, tt.wantCode, tt.failure.Code)
+			}
+			if got.Message != tt.failure.Message {
+				t.Errorf("message = %q, want ARM's message %q", got.Message, tt.failure.Message)
+			}
+			if !strings.HasPrefix(got.RequestID, "fake-request-") {
+				t.Errorf("request ID = %q, want the fake server's", got.RequestID)
+			}
+			if !strings.Contains(got.Hint, tt.wantHint) {
+				t.Errorf("hint = %q, want it to mention %q", got.Hint, tt.wantHint)
+			}
+
+			text := resultText(result)
+			for _, want := range []string{"Failed to get compute resource: " + tt.failure.Message, "Error code: " + tt.wantCode, got.RequestID} {
+				if !strings.Contains(text, want) {
+					t.Errorf("text = %q, want it to contain %q", text, want)
+				}
+			}
+			if strings.Contains(text, "RESPONSE") {
+				t.Errorf("text = %q, want no SDK request dump", text)
+			}
+		})
+	}
+}
+
+func TestAzureErrorNotFound(t *testing.T) {
+	fake := newFakeARM(t)
+	s := server.NewMCPServer("test", "1.0.0")
+	tools.NewWorkspaceTools(fake.ClientCache()).AddToServer(s)
+
+	got := toolError(t, callTool(t, s, "get_workspace", map[string]any{
+		"subscription_id":     testSubscriptionID,
+		"resource_group_name": testResourceGroup,
+		"workspace_name":      "missing",
+	}))
+	if got.Code != tools.ErrorNotFound || got.ARMCode != "ResourceNotFound" {
+		t.Errorf("error = %+v, want NotFound from ResourceNotFound", got)
+	}
+}
+
+func TestSignInErrors(t *testing.T) {
+	for _, name := range []string{azure.EnvTenantID, azure.EnvClientID} {
+		t.Setenv(name, "")
+	}
+	// Service principal sign-in without a tenant and client ID fails before
+	// any request is sent
+	clients := azure.NewClientCacheWithOptions(azure.ClientCacheOptions{
+		Auth: azure.AuthOptions{Modes: []string{azure.AuthServicePrincipal}},
+	})
+	s := server.NewMCPServer("test", "1.0.0")
+	tools.NewWorkspaceTools(clients).AddToServer(s)
+	tools.NewComputeTools(clients).AddToServer(s)
+	tools.NewMonitoringTools(clients).AddToServer(s)
+	tools.NewAccessTools(clients).AddToServer(s)
+
+	for _, call := range []struct {
+		tool string
+		args map[string]any
+	}{
+		{"get_workspace", workspaceArgs(nil)},
+		{"list_compute", workspaceArgs(nil)},
+		{"list_usage", map[string]any{"subscription_id": testSubscriptionID, "location": testLocation}},
+		{"list_workspace_role_assignments", workspaceArgs(nil)},
+	} {
+		t.Run(call.tool, func(t *testing.T) {
+			got := toolError(t, callTool(t, s, call.tool, call.args))
+			if got.Code != tools.ErrorUnauthenticated || got.Hint == "" {
+				t.Errorf("error = %+v, want Unauthenticated with a hint", got)
+			}
+			if text := resultText(callTool(t, s, call.tool, call.args)); !strings.HasPrefix(text, "Failed to sign in to Azure: ") {
+				t.Errorf("text = %q, want it to say signing in failed", text)
+			}
+		})
+	}
+}
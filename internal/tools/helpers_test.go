@@ -0,0 +1,184 @@
+package tools_test
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/machinelearning/armmachinelearning"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"microsoft.com/aml-mcp/internal/fakearm"
+)
+
+const (
+	testSubscriptionID = "00000000-0000-0000-0000-000000000001"
+	testResourceGroup  = "test-rg"
+	testWorkspace      = "test-ws"
+	testCompute        = "test-ci"
+	testLocation       = "eastus"
+)
+
+// newFakeARM starts a fake ARM server seeded with one workspace holding a
+// stopped compute instance
+func newFakeARM(t *testing.T) *fakearm.Server {
+	t.Helper()
+
+	fake := fakearm.New()
+	t.Cleanup(fake.Close)
+
+	fake.AddWorkspace(testSubscriptionID, testResourceGroup, armmachinelearning.Workspace{
+		Name:     to.Ptr(testWorkspace),
+		Location: to.Ptr(testLocation),
+		SKU:      &armmachinelearning.SKU{Name: to.Ptr("Basic")},
+		Properties: &armmachinelearning.WorkspaceProperties{
+			Description:  to.Ptr("Test workspace"),
+			FriendlyName: to.Ptr("Test Workspace"),
+		},
+	})
+	fake.AddCompute(testSubscriptionID, testResourceGroup, testWorkspace, armmachinelearning.ComputeResource{
+		Name:     to.Ptr(testCompute),
+		Location: to.Ptr(testLocation),
+		Properties: &armmachinelearning.ComputeInstance{
+			ComputeType:       to.Ptr(armmachinelearning.ComputeTypeComputeInstance),
+			ProvisioningState: to.Ptr(armmachinelearning.ProvisioningStateSucceeded),
+			Properties: &armmachinelearning.ComputeInstanceProperties{
+				VMSize: to.Ptr("Standard_DS3_v2"),
+				State:  to.Ptr(armmachinelearning.ComputeInstanceStateStopped),
+			},
+		},
+	})
+
+	return fake
+}
+
+// workspaceArgs returns the arguments identifying the seeded test workspace
+func workspaceArgs(extra map[string]any) map[string]any {
+	args := map[string]any{
+		"subscription_id":     testSubscriptionID,
+		"resource_group_name": testResourceGroup,
+		"workspace_name":      testWorkspace,
+	}
+	for k, v := range extra {
+		args[k] = v
+	}
+	return args
+}
+
+// callTool invokes a tool through the MCP server's JSON-RPC handler
+func callTool(t *testing.T, s *server.MCPServer, name string, args map[string]any) mcp.CallToolResult {
+	t.Helper()
+	return callToolInContext(context.Background(), t, s, name, args)
+}
+
+// callToolInContext invokes a tool with ctx, which may carry a client session
+func callToolInContext(ctx context.Context, t *testing.T, s *server.MCPServer, name string, args map[string]any) mcp.CallToolResult {
+	t.Helper()
+
+	message, err := json.Marshal(map[string]any{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  "tools/call",
+		"params": map[string]any{
+			"name":      name,
+			"arguments": args,
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to marshal request: %v", err)
+	}
+
+	response := s.HandleMessage(ctx, message)
+	rpcResponse, ok := response.(mcp.JSONRPCResponse)
+	if !ok {
+		t.Fatalf("tools/call %s returned %T: %+v", name, response, response)
+	}
+	result, ok := rpcResponse.Result.(mcp.CallToolResult)
+	if !ok {
+		t.Fatalf("tools/call %s result is %T", name, rpcResponse.Result)
+	}
+	return result
+}
+
+// resultText returns the text content of a tool result
+func resultText(result mcp.CallToolResult) string {
+	var parts []string
+	for _, content := range result.Content {
+		if text, ok := mcp.AsTextContent(content); ok {
+			parts = append(parts, text.Text)
+		}
+	}
+	return strings.Join(parts, "\n")
+}
+
+// structuredAs decodes the structured content of a tool result into T
+func structuredAs[T any](t *testing.T, result mcp.CallToolResult) T {
+	t.Helper()
+
+	var out T
+	if result.StructuredContent == nil {
+		t.Fatalf("result has no structured content (text: %s)", resultText(result))
+	}
+	data, err := json.Marshal(result.StructuredContent)
+	if err != nil {
+		t.Fatalf("failed to marshal structured content: %v", err)
+	}
+	if err := json.Unmarshal(data, &out); err != nil {
+		t.Fatalf("failed to decode structured content %s: %v", data, err)
+	}
+	return out
+}
+
+// listTools returns the tools advertised by the MCP server's tools/list handler
+func listTools(t *testing.T, s *server.MCPServer) []map[string]any {
+	t.Helper()
+
+	response := s.HandleMessage(context.Background(), []byte(`{"jsonrpc":"2.0","id":1,"method":"tools/list"}`))
+	data, err := json.Marshal(response)
+	if err != nil {
+		t.Fatalf("failed to marshal tools/list response: %v", err)
+	}
+	var decoded struct {
+		Result struct {
+			Tools []map[string]any `json:"tools"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("failed to decode tools/list response %s: %v", data, err)
+	}
+	return decoded.Result.Tools
+}
+
+// testSession is an initialized client session that collects the
+// notifications sent to it
+type testSession struct {
+	id            string
+	notifications chan mcp.JSONRPCNotification
+}
+
+func newTestSession(id string) *testSession {
+	return &testSession{id: id, notifications: make(chan mcp.JSONRPCNotification, 100)}
+}
+
+func (s *testSession) SessionID() string                                   { return s.id }
+func (s *testSession) NotificationChannel() chan<- mcp.JSONRPCNotification { return s.notifications }
+func (s *testSession) Initialize()                                         {}
+func (s *testSession) Initialized() bool                                   { return true }
+
+// progressMessages drains the progress notifications received for token
+func (s *testSession) progressMessages(token string) []string {
+	var messages []string
+	for {
+		select {
+		case n := <-s.notifications:
+			if n.Method == "notifications/progress" && n.Params.AdditionalFields["progressToken"] == token {
+				message, _ := n.Params.AdditionalFields["message"].(string)
+				messages = append(messages, message)
+			}
+		default:
+			return messages
+		}
+	}
+}
@@ -0,0 +1,300 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armsubscriptions"
+	"github.com/mark3labs/mcp-go/mcp"
+	"microsoft.com/aml-mcp/internal/azure"
+)
+
+// Number of subscriptions and workspaces get_inventory lists at once
+const (
+	DefaultInventoryConcurrency = 4
+	MaxInventoryConcurrency     = 16
+)
+
+const (
+	argSubscriptionIDs = "subscription_ids"
+	argIncludeCompute  = "include_compute"
+	argMaxConcurrency  = "max_concurrency"
+)
+
+func (wt *WorkspaceTools) addGetInventoryTool(s Registrar) {
+	tool := mcp.NewTool("get_inventory",
+		mcp.WithDescription("List the Azure ML workspaces, and optionally their compute resources, across many subscriptions at once. "+
+			"Covers the given subscriptions, else the configured ones, else every enabled subscription the signed-in identity can see. "+
+			"A subscription or workspace that cannot be listed is reported with its error instead of failing the call."),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOutputSchema[Inventory](),
+		mcp.WithArray(argSubscriptionIDs,
+			mcp.Description("Azure subscription IDs to cover. Defaults to the configured subscriptions, or every subscription the signed-in identity can see"),
+			mcp.WithStringItems(),
+		),
+		mcp.WithBoolean(argIncludeCompute,
+			mcp.Description("Also list the compute resources of every workspace (default false)"),
+		),
+		mcp.WithNumber(argMaxConcurrency,
+			mcp.Description(fmt.Sprintf("Maximum number of subscriptions and workspaces listed at once (default %d)", DefaultInventoryConcurrency)),
+			mcp.Min(1),
+			mcp.Max(MaxInventoryConcurrency),
+		),
+	)
+
+	s.AddTool(tool, wt.handleGetInventory)
+}
+
+func (wt *WorkspaceTools) handleGetInventory(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	includeCompute := request.GetBool(argIncludeCompute, false)
+	concurrency := request.GetInt(argMaxConcurrency, DefaultInventoryConcurrency)
+	if concurrency < 1 || concurrency > MaxInventoryConcurrency {
+		return mcp.NewToolResultError(fmt.Sprintf("max_concurrency must be between 1 and %d", MaxInventoryConcurrency)), nil
+	}
+
+	subscriptions, err := wt.inventorySubscriptions(ctx, request)
+	if err != nil {
+		return azureError(wt.clients, "Failed to list subscriptions", err), nil
+	}
+	if len(subscriptions) == 0 {
+		result := Inventory{Subscriptions: []SubscriptionInventory{}}
+		return mcp.NewToolResultStructured(result, "No enabled Azure subscriptions found for the signed-in identity."), nil
+	}
+
+	result := wt.collectInventory(ctx, newProgressReporter(ctx, request), subscriptions, includeCompute, concurrency)
+	return mcp.NewToolResultStructured(result, inventoryText(result, includeCompute)), nil
+}
+
+// inventorySubscriptions returns the subscriptions get_inventory covers
+func (wt *WorkspaceTools) inventorySubscriptions(ctx context.Context, request mcp.CallToolRequest) ([]SubscriptionInventory, error) {
+	ids := request.GetStringSlice(argSubscriptionIDs, nil)
+	if len(ids) == 0 {
+		ids = wt.subscriptions
+	}
+	if len(ids) > 0 {
+		var subscriptions []SubscriptionInventory
+		seen := make(map[string]bool)
+		for _, id := range ids {
+			id = strings.TrimSpace(id)
+			if id == "" || seen[strings.ToLower(id)] {
+				continue
+			}
+			seen[strings.ToLower(id)] = true
+			subscriptions = append(subscriptions, SubscriptionInventory{SubscriptionID: id})
+		}
+		return subscriptions, nil
+	}
+
+	all, err := listSubscriptions(ctx, wt.clients)
+	if err != nil {
+		return nil, err
+	}
+	var subscriptions []SubscriptionInventory
+	for _, sub := range all {
+		// Workspaces of disabled and deleted subscriptions cannot be listed
+		if sub.State != nil && (*sub.State == armsubscriptions.SubscriptionStateDisabled || *sub.State == armsubscriptions.SubscriptionStateDeleted) {
+			continue
+		}
+		subscriptions = append(subscriptions, SubscriptionInventory{
+			SubscriptionID: *sub.SubscriptionID,
+			DisplayName:    valueOf(sub.DisplayName),
+		})
+	}
+	return subscriptions, nil
+}
+
+// collectInventory lists the workspaces, and optionally compute, of every subscription
+func (wt *WorkspaceTools) collectInventory(ctx context.Context, progress *progressReporter, subscriptions []SubscriptionInventory, includeCompute bool, concurrency int) Inventory {
+	slots := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	// run calls f in a new goroutine once a slot is free
+	run := func(f func()) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			slots <- struct{}{}
+			defer func() { <-slots }()
+			f()
+		}()
+	}
+
+	var progressMu sync.Mutex
+	done := 0
+	reportDone := func(sub *SubscriptionInventory) {
+		progressMu.Lock()
+		defer progressMu.Unlock()
+		done++
+		progress.report(ctx, fmt.Sprintf("Listed %d of %d subscriptions (%s)", done, len(subscriptions), sub.SubscriptionID))
+	}
+
+	// Each goroutine writes only its own subscription's error
+	errs := make([]error, len(subscriptions))
+	for i := range subscriptions {
+		sub := &subscriptions[i]
+		run(func() {
+			workspaces, err := listAllWorkspaces(ctx, wt.clients, sub.SubscriptionID)
+			if err != nil {
+				errs[i] = err
+				toolErr := newToolError(err)
+				sub.Error = &toolErr
+				sub.Workspaces = []WorkspaceInventory{}
+				reportDone(sub)
+				return
+			}
+			sub.Workspaces = make([]WorkspaceInventory, 0, len(workspaces))
+			for _, ws := range workspaces {
+				sub.Workspaces = append(sub.Workspaces, WorkspaceInventory{Workspace: ws})
+			}
+			if !includeCompute || len(sub.Workspaces) == 0 {
+				reportDone(sub)
+				return
+			}
+			// The last workspace to finish reports the subscription done
+			var pending atomic.Int32
+			pending.Store(int32(len(sub.Workspaces)))
+			for j := range sub.Workspaces {
+				ws := &sub.Workspaces[j]
+				run(func() {
+					defer func() {
+						if pending.Add(-1) == 0 {
+							reportDone(sub)
+						}
+					}()
+					computes, err := listAllCompute(ctx, wt.clients, sub.SubscriptionID, ws.ResourceGroup, ws.Name)
+					if err != nil {
+						toolErr := newToolError(err)
+						ws.ComputeError = &toolErr
+						return
+					}
+					ws.Computes = computes
+				})
+			}
+		})
+	}
+	wg.Wait()
+	// The credential is only dropped when every subscription failed to authenticate
+	if len(errs) > 0 && !slices.ContainsFunc(errs, func(err error) bool { return !azure.IsAuthError(err) }) &&
+		wt.clients.InvalidateOnAuthError(errs[0]) {
+		for i := range subscriptions {
+			subscriptions[i].Error.credentialDiscarded()
+		}
+	}
+
+	result := Inventory{SubscriptionCount: len(subscriptions), Subscriptions: subscriptions}
+	for _, sub := range subscriptions {
+		if sub.Error != nil {
+			result.ErrorCount++
+		}
+		result.WorkspaceCount += len(sub.Workspaces)
+		for _, ws := range sub.Workspaces {
+			if ws.ComputeError != nil {
+				result.ErrorCount++
+			}
+			result.ComputeCount += len(ws.Computes)
+		}
+	}
+	return result
+}
+
+// listSubscriptions lists every subscription the credential can see
+func listSubscriptions(ctx context.Context, clients *azure.ClientCache) ([]*armsubscriptions.Subscription, error) {
+	client, err := clients.SubscriptionsClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var subscriptions []*armsubscriptions.Subscription
+	pager := client.NewListPager(nil)
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, sub := range page.Value {
+			if sub.SubscriptionID != nil {
+				subscriptions = append(subscriptions, sub)
+			}
+		}
+	}
+	return subscriptions, nil
+}
+
+// listAllWorkspaces lists every workspace in a subscription
+func listAllWorkspaces(ctx context.Context, clientCache *azure.ClientCache, subscriptionID string) ([]Workspace, error) {
+	clients, err := clientCache.Get(ctx, subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	workspaces := []Workspace{}
+	pager := clients.WorkspacesClient.NewListBySubscriptionPager(nil)
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, workspace := range page.Value {
+			if workspace.Name != nil {
+				workspaces = append(workspaces, newWorkspace(workspace))
+			}
+		}
+	}
+	return workspaces, nil
+}
+
+// listAllCompute lists every compute resource in a workspace
+func listAllCompute(ctx context.Context, clientCache *azure.ClientCache, subscriptionID, resourceGroupName, workspaceName string) ([]Compute, error) {
+	clients, err := clientCache.Get(ctx, subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	computes := []Compute{}
+	pager := clients.ComputeClient.NewListPager(resourceGroupName, workspaceName, nil)
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		computes = append(computes, newComputes(page.Value)...)
+	}
+	return computes, nil
+}
+
+// inventoryText renders an inventory for the text output of get_inventory
+func inventoryText(inventory Inventory, includeCompute bool) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Inventory of %d subscriptions: %d workspaces", inventory.SubscriptionCount, inventory.WorkspaceCount)
+	if includeCompute {
+		fmt.Fprintf(&b, ", %d compute resources", inventory.ComputeCount)
+	}
+	if inventory.ErrorCount > 0 {
+		fmt.Fprintf(&b, ", %d errors", inventory.ErrorCount)
+	}
+
+	for _, sub := range inventory.Subscriptions {
+		if sub.DisplayName != "" {
+			fmt.Fprintf(&b, "\n\nSubscription: %s (%s)", sub.DisplayName, sub.SubscriptionID)
+		} else {
+			fmt.Fprintf(&b, "\n\nSubscription: %s", sub.SubscriptionID)
+		}
+		if sub.Error != nil {
+			fmt.Fprintf(&b, "\n  Error: %s (%s)", sub.Error.Message, sub.Error.Code)
+			continue
+		}
+		if len(sub.Workspaces) == 0 {
+			b.WriteString("\n  No Azure ML workspaces")
+		}
+		for _, ws := range sub.Workspaces {
+			fmt.Fprintf(&b, "\n  Workspace: %s, Location: %s, Resource Group: %s", ws.Name, orNA(ws.Location), orNA(ws.ResourceGroup))
+			if ws.ComputeError != nil {
+				fmt.Fprintf(&b, "\n    Compute error: %s (%s)", ws.ComputeError.Message, ws.ComputeError.Code)
+			}
+			for _, c := range ws.Computes {
+				fmt.Fprintf(&b, "\n    Compute: %s, Type: %s, State: %s", c.Name, orNA(c.Type), orNA(c.ProvisioningState))
+			}
+		}
+	}
+	return b.String()
+}
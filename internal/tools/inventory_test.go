@@ -0,0 +1,255 @@
+package tools_test
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/machinelearning/armmachinelearning"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armsubscriptions"
+	"github.com/mark3labs/mcp-go/server"
+	"microsoft.com/aml-mcp/internal/azure"
+	"microsoft.com/aml-mcp/internal/fakearm"
+	"microsoft.com/aml-mcp/internal/tools"
+)
+
+const (
+	otherSubscriptionID    = "00000000-0000-0000-0000-000000000002"
+	failingSubscriptionID  = "00000000-0000-0000-0000-000000000003"
+	disabledSubscriptionID = "00000000-0000-0000-0000-000000000004"
+)
+
+// newInventoryServer seeds four subscriptions: the test one, another with a
+// workspace of its own, one whose workspaces cannot be listed and a disabled one
+func newInventoryServer(t *testing.T, opts ...tools.Option) (*fakearm.Server, *server.MCPServer) {
+	t.Helper()
+
+	fake := newFakeARM(t)
+	fake.SetPageSize(1)
+	fake.AddSubscription(testSubscriptionID, "Team A", "")
+	fake.AddSubscription(otherSubscriptionID, "Team B", "")
+	fake.AddSubscription(failingSubscriptionID, "Team C", "")
+	fake.AddSubscription(disabledSubscriptionID, "Old team", armsubscriptions.SubscriptionStateDisabled)
+	fake.AddWorkspace(otherSubscriptionID, "other-rg", armmachinelearning.Workspace{
+		Name:     to.Ptr("other-ws"),
+		Location: to.Ptr("westus"),
+	})
+	fake.AddFailure(fakearm.Failure{
+		PathContains: "/subscriptions/" + failingSubscriptionID + "/",
+		Status:       http.StatusForbidden,
+		Code:         "AuthorizationFailed",
+		Message:      "The client does not have authorization to perform action",
+	})
+
+	s := server.NewMCPServer("test", "1.0.0")
+	tools.NewWorkspaceTools(fake.ClientCache(), opts...).AddToServer(s)
+	return fake, s
+}
+
+func TestGetInventory(t *testing.T) {
+	fake, s := newInventoryServer(t)
+	// list_compute leaves out compute resources without properties, and so
+	// does the inventory
+	fake.AddCompute(testSubscriptionID, testResourceGroup, testWorkspace, armmachinelearning.ComputeResource{Name: to.Ptr("no-properties")})
+
+	result := callTool(t, s, "get_inventory", map[string]any{"include_compute": true, "max_concurrency": 2})
+	if result.IsError {
+		t.Fatalf("get_inventory failed: %s", resultText(result))
+	}
+	inventory := structuredAs[tools.Inventory](t, result)
+
+	if inventory.SubscriptionCount != 3 || inventory.WorkspaceCount != 2 || inventory.ComputeCount != 1 || inventory.ErrorCount != 1 {
+		t.Errorf("counts = %d subscriptions, %d workspaces, %d computes, %d errors, want 3, 2, 1, 1",
+			inventory.SubscriptionCount, inventory.WorkspaceCount, inventory.ComputeCount, inventory.ErrorCount)
+	}
+	bySubscription := make(map[string]tools.SubscriptionInventory)
+	for _, sub := range inventory.Subscriptions {
+		bySubscription[sub.SubscriptionID] = sub
+	}
+	if _, ok := bySubscription[disabledSubscriptionID]; ok {
+		t.Error("disabled subscription was inventoried")
+	}
+
+	team := bySubscription[testSubscriptionID]
+	if team.DisplayName != "Team A" || len(team.Workspaces) != 1 || team.Workspaces[0].Name != testWorkspace {
+		t.Fatalf("test subscription = %+v", team)
+	}
+	if computes := team.Workspaces[0].Computes; len(computes) != 1 || computes[0].Name != testCompute {
+		t.Errorf("test workspace computes = %+v", computes)
+	}
+	if other := bySubscription[otherSubscriptionID]; len(other.Workspaces) != 1 || other.Workspaces[0].ResourceGroup != "other-rg" {
+		t.Errorf("other subscription = %+v", other)
+	}
+	failing := bySubscription[failingSubscriptionID]
+	if failing.Error == nil || failing.Error.Code != tools.ErrorPermissionDenied {
+		t.Errorf("failing subscription error = %+v, want %s", failing.Error, tools.ErrorPermissionDenied)
+	}
+
+	text := resultText(result)
+	for _, want := range []string{
+		"Inventory of 3 subscriptions: 2 workspaces, 1 compute resources, 1 errors",
+		"Subscription: Team B (" + otherSubscriptionID + ")",
+		"Workspace: other-ws, Location: westus, Resource Group: other-rg",
+		"Compute: test-ci, Type: ComputeInstance",
+		"Error: The client does not have authorization to perform action (PermissionDenied)",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("result %q does not contain %q", text, want)
+		}
+	}
+
+	// Every page of the subscription list was followed
+	if n := countRequests(fake, "/subscriptions"); n != 4 {
+		t.Errorf("subscription list requests = %d, want 4", n)
+	}
+}
+
+func TestGetInventorySubscriptionList(t *testing.T) {
+	t.Run("configured", func(t *testing.T) {
+		fake, s := newInventoryServer(t, tools.WithSubscriptions([]string{otherSubscriptionID}))
+
+		inventory := structuredAs[tools.Inventory](t, callTool(t, s, "get_inventory", nil))
+		if inventory.SubscriptionCount != 1 || inventory.Subscriptions[0].SubscriptionID != otherSubscriptionID {
+			t.Errorf("inventory = %+v, want only the configured subscription", inventory)
+		}
+		if n := countRequests(fake, "/subscriptions"); n != 0 {
+			t.Errorf("subscriptions were listed %d times, want 0", n)
+		}
+	})
+
+	t.Run("argument", func(t *testing.T) {
+		_, s := newInventoryServer(t, tools.WithSubscriptions([]string{otherSubscriptionID}))
+
+		inventory := structuredAs[tools.Inventory](t, callTool(t, s, "get_inventory", map[string]any{
+			"subscription_ids": []string{testSubscriptionID, strings.ToUpper(testSubscriptionID)},
+		}))
+		if inventory.SubscriptionCount != 1 || inventory.Subscriptions[0].SubscriptionID != testSubscriptionID {
+			t.Errorf("inventory = %+v, want only the named subscription", inventory)
+		}
+		if inventory.ComputeCount != 0 || inventory.Subscriptions[0].Workspaces[0].Computes != nil {
+			t.Error("compute was listed without include_compute")
+		}
+	})
+}
+
+// countingCredential counts the tokens requested from the fake server's credential
+type countingCredential struct {
+	azcore.TokenCredential
+	count atomic.Int32
+}
+
+func (c *countingCredential) GetToken(ctx context.Context, options policy.TokenRequestOptions) (azcore.AccessToken, error) {
+	c.count.Add(1)
+	return c.TokenCredential.GetToken(ctx, options)
+}
+
+func TestGetInventoryAuthFailures(t *testing.T) {
+	unauthorized := fakearm.Failure{
+		Status:  http.StatusUnauthorized,
+		Code:    "InvalidAuthenticationTokenTenant",
+		Message: "The access token is from the wrong issuer",
+	}
+	tests := []struct {
+		name            string
+		failing         string
+		wantInvalidated bool
+	}{
+		// A guest-tenant subscription leaves the others' clients in place
+		{name: "one subscription", failing: "/subscriptions/" + otherSubscriptionID + "/", wantInvalidated: false},
+		{name: "every subscription", failing: "/providers/Microsoft.MachineLearningServices/workspaces", wantInvalidated: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := newFakeARM(t)
+			fake.AddSubscription(testSubscriptionID, "Team A", "")
+			fake.AddSubscription(otherSubscriptionID, "Team B", "")
+			failure := unauthorized
+			failure.PathContains = tt.failing
+			failure.Times = 2
+			fake.AddFailure(failure)
+
+			credential := &countingCredential{TokenCredential: fake.Credential()}
+			clients := azure.NewClientCacheWithOptions(azure.ClientCacheOptions{Credential: credential, ClientOptions: fake.ClientOptions()})
+			s := server.NewMCPServer("test", "1.0.0")
+			tools.NewWorkspaceTools(clients).AddToServer(s)
+
+			inventory := structuredAs[tools.Inventory](t, callTool(t, s, "get_inventory", nil))
+			if inventory.SubscriptionCount != 2 {
+				t.Fatalf("inventory = %+v, want both subscriptions", inventory)
+			}
+			for _, sub := range inventory.Subscriptions {
+				if sub.Error == nil {
+					continue
+				}
+				if sub.Error.Code != tools.ErrorUnauthenticated {
+					t.Errorf("subscription %s error = %+v, want Unauthenticated", sub.SubscriptionID, sub.Error)
+				}
+				// The hint only claims the credential was dropped when it was
+				if discarded := strings.Contains(sub.Error.Hint, "discarded"); discarded != tt.wantInvalidated {
+					t.Errorf("subscription %s hint = %q, want discarded mentioned %v", sub.SubscriptionID, sub.Error.Hint, tt.wantInvalidated)
+				}
+			}
+
+			before := credential.count.Load()
+			if result := callTool(t, s, "get_workspace", workspaceArgs(nil)); result.IsError {
+				t.Fatalf("get_workspace failed: %s", resultText(result))
+			}
+			if invalidated := credential.count.Load() > before; invalidated != tt.wantInvalidated {
+				t.Errorf("clients dropped = %v, want %v", invalidated, tt.wantInvalidated)
+			}
+		})
+	}
+}
+
+// computeListPolicy holds back compute listings, and records whether every
+// subscription had been reported done by the time one of them finished
+type computeListPolicy struct {
+	session       *testSession
+	subscriptions int
+	early         atomic.Bool
+}
+
+func (p *computeListPolicy) Do(req *policy.Request) (*http.Response, error) {
+	if !strings.HasSuffix(req.Raw().URL.Path, "/computes") {
+		return req.Next()
+	}
+	time.Sleep(20 * time.Millisecond)
+	resp, err := req.Next()
+	if len(p.session.notifications) >= p.subscriptions {
+		p.early.Store(true)
+	}
+	return resp, err
+}
+
+func TestGetInventoryProgress(t *testing.T) {
+	fake := newFakeARM(t)
+	fake.AddSubscription(testSubscriptionID, "Team A", "")
+	fake.AddSubscription(otherSubscriptionID, "Team B", "")
+
+	session := newTestSession("session-1")
+	computeList := &computeListPolicy{session: session, subscriptions: 2}
+	options := fake.ClientOptions()
+	options.PerCallPolicies = append(options.PerCallPolicies, computeList)
+	clients := azure.NewClientCacheWithOptions(azure.ClientCacheOptions{Credential: fake.Credential(), ClientOptions: options})
+	s := server.NewMCPServer("test", "1.0.0")
+	tools.NewWorkspaceTools(clients).AddToServer(s)
+
+	result := callToolWithProgress(context.Background(), t, s, session, "get_inventory", "inventory-1", map[string]any{"include_compute": true})
+	if result.IsError {
+		t.Fatalf("get_inventory failed: %s", resultText(result))
+	}
+	// A subscription is done once its compute has been listed
+	if computeList.early.Load() {
+		t.Error("every subscription was reported done while compute listings were still running")
+	}
+	messages := session.progressMessages("inventory-1")
+	if len(messages) != 2 || !strings.HasPrefix(messages[1], "Listed 2 of 2 subscriptions") {
+		t.Errorf("progress messages = %q, want one per subscription", messages)
+	}
+}
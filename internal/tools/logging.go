@@ -0,0 +1,60 @@
+package tools
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"go.opentelemetry.io/otel/trace"
+	"microsoft.com/aml-mcp/internal/logging"
+)
+
+// Logged returns a Registrar that logs every tool call to logger before registering the tool with r
+func Logged(r Registrar, logger *slog.Logger, opts ...Option) Registrar {
+	return &logRegistrar{next: r, logger: logger, shared: newShared(opts)}
+}
+
+type logRegistrar struct {
+	next   Registrar
+	logger *slog.Logger
+	shared
+}
+
+func (l *logRegistrar) AddTool(tool mcp.Tool, handler server.ToolHandlerFunc) {
+	l.next.AddTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		logger := l.logger.With(l.requestAttrs(ctx, request)...)
+		ctx = logging.WithLogger(ctx, logger)
+
+		logger.Debug("Tool call started")
+		start := time.Now()
+		result, err := handler(ctx, request)
+		duration := time.Since(start).Round(time.Millisecond)
+
+		switch {
+		case err != nil:
+			logger.Error("Tool call failed", "duration", duration, "error", err)
+		case result != nil && result.IsError:
+			logger.Warn("Tool call returned an error", "duration", duration, "error", resultText(result))
+		default:
+			logger.Info("Tool call finished", "duration", duration)
+		}
+		return result, err
+	})
+}
+
+// requestAttrs returns the fields identifying a tool call
+func (l *logRegistrar) requestAttrs(ctx context.Context, request mcp.CallToolRequest) []any {
+	attrs := []any{slog.String("tool", request.Params.Name)}
+	if sessionID := sessionIDFromContext(ctx); sessionID != "" {
+		attrs = append(attrs, slog.String("session", sessionID))
+	}
+	// Calls that are traced can be looked up from their logs
+	if spanContext := trace.SpanContextFromContext(ctx); spanContext.IsValid() {
+		attrs = append(attrs, slog.String("traceId", spanContext.TraceID().String()))
+	}
+	scope := scopeAttrs(l.shared, ctx, request, [3]string{"subscription", "resourceGroup", "workspace"},
+		func(key, value string) any { return slog.String(key, value) })
+	return append(attrs, scope...)
+}
@@ -0,0 +1,67 @@
+package tools_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/server"
+	"microsoft.com/aml-mcp/internal/tools"
+)
+
+func TestLoggedTools(t *testing.T) {
+	fake := newFakeARM(t)
+	fake.SetPendingPolls(1)
+	clients := fake.ClientCache()
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	opts := []tools.Option{tools.WithPollInterval(10 * time.Millisecond)}
+
+	s := server.NewMCPServer("test", "1.0.0")
+	logged := tools.Logged(s, logger, opts...)
+	tools.NewWorkspaceTools(clients, opts...).AddToServer(logged)
+	tools.NewComputeTools(clients, opts...).AddToServer(logged)
+
+	callTool(t, s, "start_compute", workspaceArgs(map[string]any{"compute_name": testCompute}))
+	callTool(t, s, "get_workspace", map[string]any{"subscription_id": testSubscriptionID})
+
+	var records []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var record map[string]any
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("invalid log line %q: %v", line, err)
+		}
+		records = append(records, record)
+	}
+
+	var finished, polled, failed bool
+	for _, record := range records {
+		switch record["msg"] {
+		case "Tool call finished":
+			finished = true
+			if record["tool"] != "start_compute" || record["subscription"] != testSubscriptionID ||
+				record["resourceGroup"] != testResourceGroup || record["workspace"] != testWorkspace {
+				t.Errorf("finished record = %v, want the tool and its workspace", record)
+			}
+		case "Polled long-running operation":
+			polled = true
+			if record["tool"] != "start_compute" {
+				t.Errorf("poll record = %v, want it to carry the tool's fields", record)
+			}
+		case "Tool call returned an error":
+			failed = true
+			if record["tool"] != "get_workspace" || record["subscription"] != testSubscriptionID {
+				t.Errorf("error record = %v, want get_workspace with its subscription", record)
+			}
+			if _, ok := record["workspace"]; ok {
+				t.Errorf("error record = %v, want no workspace for a call without one", record)
+			}
+		}
+	}
+	if !finished || !polled || !failed {
+		t.Errorf("logged finished=%v polled=%v failed=%v, want all three:\n%s", finished, polled, failed, buf.String())
+	}
+}
@@ -0,0 +1,173 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/machinelearning/armmachinelearning"
+	"github.com/mark3labs/mcp-go/mcp"
+	"microsoft.com/aml-mcp/internal/azure"
+	"microsoft.com/aml-mcp/internal/logging"
+	"microsoft.com/aml-mcp/internal/operations"
+)
+
+// lro is the untyped view of an SDK poller
+type lro interface {
+	Done() bool
+	Poll(ctx context.Context) (*http.Response, error)
+	ResumeToken() (string, error)
+	// outcome reports whether a finished operation succeeded
+	outcome(ctx context.Context) error
+}
+
+// sdkPoller adapts a typed SDK poller to lro
+type sdkPoller[T any] struct {
+	*runtime.Poller[T]
+}
+
+func (p sdkPoller[T]) outcome(ctx context.Context) error {
+	_, err := p.Result(ctx)
+	return err
+}
+
+// pollUntilDone polls p until the operation finishes or ctx is done, reporting progress after each poll
+func (s shared) pollUntilDone(ctx context.Context, request mcp.CallToolRequest, description string, p lro) error {
+	progress := newProgressReporter(ctx, request)
+	start := time.Now()
+
+	for !p.Done() {
+		resp, err := p.Poll(ctx)
+		if err != nil {
+			return err
+		}
+		if p.Done() {
+			break
+		}
+
+		elapsed := time.Since(start).Round(time.Second)
+		status := pollStatus(resp)
+		logging.FromContext(ctx).Debug("Polled long-running operation", "operation", description, "status", status, "elapsed", elapsed)
+		progress.report(ctx, fmt.Sprintf("%s: %s (%s elapsed)", description, orNA(status), elapsed))
+
+		delay := s.pollInterval
+		if retryAfter := retryAfter(resp); retryAfter > 0 {
+			delay = retryAfter
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+	return nil
+}
+
+// pollStatus extracts the status or provisioning state ARM reported in a poll response
+func pollStatus(resp *http.Response) string {
+	if resp == nil {
+		return ""
+	}
+	body, err := runtime.Payload(resp)
+	if err != nil {
+		return ""
+	}
+	var payload struct {
+		Status     string `json:"status"`
+		Properties struct {
+			ProvisioningState string `json:"provisioningState"`
+		} `json:"properties"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return ""
+	}
+	if payload.Status != "" {
+		return payload.Status
+	}
+	return payload.Properties.ProvisioningState
+}
+
+// retryAfter returns the delay requested by a poll response's Retry-After header, if any
+func retryAfter(resp *http.Response) time.Duration {
+	if resp == nil {
+		return 0
+	}
+	return azure.RetryAfter(resp.Header)
+}
+
+// resumeOperation rebuilds the SDK poller for a tracked operation from its resume token
+func resumeOperation(ctx context.Context, clients *azure.ClientSet, op operations.Operation) (lro, error) {
+	switch op.Kind {
+	case operations.KindCreateWorkspace:
+		p, err := clients.WorkspacesClient.BeginCreateOrUpdate(ctx, op.ResourceGroup, op.Target, armmachinelearning.Workspace{},
+			&armmachinelearning.WorkspacesClientBeginCreateOrUpdateOptions{ResumeToken: op.ResumeToken})
+		if err != nil {
+			return nil, err
+		}
+		return sdkPoller[armmachinelearning.WorkspacesClientCreateOrUpdateResponse]{p}, nil
+	case operations.KindStartCompute:
+		p, err := clients.ComputeClient.BeginStart(ctx, op.ResourceGroup, op.Workspace, op.Target,
+			&armmachinelearning.ComputeClientBeginStartOptions{ResumeToken: op.ResumeToken})
+		if err != nil {
+			return nil, err
+		}
+		return sdkPoller[armmachinelearning.ComputeClientStartResponse]{p}, nil
+	case operations.KindStopCompute:
+		p, err := clients.ComputeClient.BeginStop(ctx, op.ResourceGroup, op.Workspace, op.Target,
+			&armmachinelearning.ComputeClientBeginStopOptions{ResumeToken: op.ResumeToken})
+		if err != nil {
+			return nil, err
+		}
+		return sdkPoller[armmachinelearning.ComputeClientStopResponse]{p}, nil
+	default:
+		return nil, fmt.Errorf("unknown operation kind %q", op.Kind)
+	}
+}
+
+// trackOperation registers an operation started by a tool so later calls can poll it
+func (s shared) trackOperation(ctx context.Context, op operations.Operation, p lro) (operations.Operation, error) {
+	if p.Done() {
+		if err := p.outcome(ctx); err != nil {
+			op.Status = operations.StatusFailed
+			op.Error = newToolError(err).text()
+		} else {
+			op.Status = operations.StatusSucceeded
+		}
+		return s.operations.Add(op), nil
+	}
+
+	token, err := p.ResumeToken()
+	if err != nil {
+		return operations.Operation{}, fmt.Errorf("failed to get operation resume token: %v", err)
+	}
+	op.ResumeToken = token
+	return s.operations.Add(op), nil
+}
+
+// recordOutcome stores the state of a resumed poller after it has been polled
+func (s shared) recordOutcome(ctx context.Context, id string, p lro) operations.Operation {
+	if !p.Done() {
+		op, _ := s.operations.Update(id, func(op *operations.Operation) {
+			if token, err := p.ResumeToken(); err == nil {
+				op.ResumeToken = token
+			}
+		})
+		return op
+	}
+
+	err := p.outcome(ctx)
+	op, _ := s.operations.Update(id, func(op *operations.Operation) {
+		if err != nil {
+			op.Status = operations.StatusFailed
+			op.Error = newToolError(err).text()
+		} else {
+			op.Status = operations.StatusSucceeded
+		}
+	})
+	return op
+}
@@ -0,0 +1,491 @@
+package tools
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/authorization/armauthorization/v2"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/machinelearning/armmachinelearning"
+	"microsoft.com/aml-mcp/internal/audit"
+	"microsoft.com/aml-mcp/internal/azure"
+	"microsoft.com/aml-mcp/internal/helpers"
+	"microsoft.com/aml-mcp/internal/operations"
+	"microsoft.com/aml-mcp/internal/session"
+)
+
+// Structured tool results, returned as structured content alongside the text output
+
+// Workspace is the structured form of an Azure ML workspace
+type Workspace struct {
+	Name              string `json:"name"`
+	ID                string `json:"id"`
+	Location          string `json:"location"`
+	ResourceGroup     string `json:"resourceGroup"`
+	Type              string `json:"type,omitempty"`
+	SKU               string `json:"sku,omitempty"`
+	Description       string `json:"description,omitempty"`
+	FriendlyName      string `json:"friendlyName,omitempty"`
+	DiscoveryURL      string `json:"discoveryUrl,omitempty"`
+	MLFlowTrackingURI string `json:"mlflowTrackingUri,omitempty"`
+	ProvisioningState string `json:"provisioningState,omitempty"`
+	// URI is the workspace's MCP resource
+	URI string `json:"uri,omitempty"`
+}
+
+// WorkspaceCreation is the result of create_workspace
+type WorkspaceCreation struct {
+	Status      string        `json:"status"`
+	OperationID string        `json:"operationId,omitempty"`
+	Workspace   *Workspace    `json:"workspace,omitempty"`
+	Plan        *MutationPlan `json:"plan,omitempty"`
+}
+
+// WorkspaceList is the result of list_workspaces_by_subscription
+type WorkspaceList struct {
+	SubscriptionID string      `json:"subscriptionId"`
+	Count          int         `json:"count"`
+	Workspaces     []Workspace `json:"workspaces"`
+	NextCursor     string      `json:"nextCursor,omitempty"`
+}
+
+// Subscription is an Azure subscription the signed-in identity can see
+type Subscription struct {
+	SubscriptionID string `json:"subscriptionId"`
+	DisplayName    string `json:"displayName,omitempty"`
+	State          string `json:"state,omitempty"`
+	// WorkspacesURI is the MCP resource listing the subscription's workspaces
+	WorkspacesURI string `json:"workspacesUri"`
+}
+
+// SubscriptionList is the contents of the aml://subscriptions resource
+type SubscriptionList struct {
+	Count         int            `json:"count"`
+	Subscriptions []Subscription `json:"subscriptions"`
+}
+
+// Inventory is the result of get_inventory
+type Inventory struct {
+	SubscriptionCount int                     `json:"subscriptionCount"`
+	WorkspaceCount    int                     `json:"workspaceCount"`
+	ComputeCount      int                     `json:"computeCount"`
+	ErrorCount        int                     `json:"errorCount"`
+	Subscriptions     []SubscriptionInventory `json:"subscriptions"`
+}
+
+// SubscriptionInventory is the workspaces of one subscription in an Inventory
+type SubscriptionInventory struct {
+	SubscriptionID string               `json:"subscriptionId"`
+	DisplayName    string               `json:"displayName,omitempty"`
+	Workspaces     []WorkspaceInventory `json:"workspaces"`
+	Error          *ToolError           `json:"error,omitempty"`
+}
+
+// WorkspaceInventory is a workspace in an Inventory
+type WorkspaceInventory struct {
+	Workspace
+	Computes     []Compute  `json:"computes,omitempty"`
+	ComputeError *ToolError `json:"computeError,omitempty"`
+}
+
+// Compute is the structured form of an Azure ML compute resource
+type Compute struct {
+	Name              string     `json:"name"`
+	ID                string     `json:"id"`
+	Type              string     `json:"type"`
+	Location          string     `json:"location"`
+	Description       string     `json:"description,omitempty"`
+	ProvisioningState string     `json:"provisioningState"`
+	CreatedOn         *time.Time `json:"createdOn,omitempty"`
+	ModifiedOn        *time.Time `json:"modifiedOn,omitempty"`
+	IsAttached        bool       `json:"isAttached"`
+	// URI is the compute resource's MCP resource
+	URI string `json:"uri,omitempty"`
+}
+
+// ComputeList is the result of list_compute
+type ComputeList struct {
+	Workspace  string    `json:"workspace"`
+	Count      int       `json:"count"`
+	Computes   []Compute `json:"computes"`
+	NextCursor string    `json:"nextCursor,omitempty"`
+}
+
+// ComputeOperation is the result of a compute start or stop
+type ComputeOperation struct {
+	ComputeName string        `json:"computeName"`
+	Action      string        `json:"action"`
+	Status      string        `json:"status"`
+	OperationID string        `json:"operationId,omitempty"`
+	Plan        *MutationPlan `json:"plan,omitempty"`
+}
+
+// MutationPlan is the change a mutating tool would make, returned instead of acting
+type MutationPlan struct {
+	Tool         string     `json:"tool"`
+	Summary      string     `json:"summary"`
+	DryRun       bool       `json:"dryRun"`
+	Request      ARMRequest `json:"request"`
+	ConfirmToken string     `json:"confirmToken,omitempty"`
+	ExpiresAt    *time.Time `json:"expiresAt,omitempty"`
+}
+
+// ARMRequest is an Azure Resource Manager request. Body is the decoded JSON body.
+type ARMRequest struct {
+	Method string `json:"method"`
+	URL    string `json:"url"`
+	Body   any    `json:"body,omitempty"`
+}
+
+// ToolError describes a failed Azure call in the _meta.error field of a tool result
+type ToolError struct {
+	// Code groups the failure by what the caller can do about it, e.g. NotFound
+	Code       string `json:"code"`
+	Message    string `json:"message"`
+	StatusCode int    `json:"statusCode,omitempty"`
+	// ARMCode is the error code Azure returned, e.g. AuthorizationFailed
+	ARMCode   string `json:"armCode,omitempty"`
+	RequestID string `json:"requestId,omitempty"`
+	Hint      string `json:"hint,omitempty"`
+}
+
+// Operation is the status of a long-running operation tracked by the server
+type Operation struct {
+	OperationID   string    `json:"operationId"`
+	Kind          string    `json:"kind"`
+	Target        string    `json:"target"`
+	ResourceGroup string    `json:"resourceGroup,omitempty"`
+	Workspace     string    `json:"workspace,omitempty"`
+	Status        string    `json:"status"`
+	Error         string    `json:"error,omitempty"`
+	StartedAt     time.Time `json:"startedAt"`
+	UpdatedAt     time.Time `json:"updatedAt"`
+}
+
+// ActiveWorkspace is the result of set_active_workspace and get_active_workspace
+type ActiveWorkspace struct {
+	Effective session.Workspace `json:"effective"`
+	Session   session.Workspace `json:"session"`
+	Defaults  session.Workspace `json:"defaults"`
+}
+
+// AuthStatus is the result of auth_status and reauthenticate
+type AuthStatus struct {
+	CredentialType string `json:"credentialType"`
+	TenantID       string `json:"tenantId,omitempty"`
+	ObjectID       string `json:"objectId,omitempty"`
+	// UserPrincipalName is empty for service principals and managed identities
+	UserPrincipalName string    `json:"userPrincipalName,omitempty"`
+	AppID             string    `json:"appId,omitempty"`
+	ExpiresOn         time.Time `json:"expiresOn"`
+	// SubscriptionID is the subscription whose tenant the token came from
+	SubscriptionID          string `json:"subscriptionId,omitempty"`
+	AuthorityHost           string `json:"authorityHost,omitempty"`
+	ResourceManagerEndpoint string `json:"resourceManagerEndpoint,omitempty"`
+}
+
+// newAuthStatus converts a token status to the auth tools' result
+func newAuthStatus(status azure.TokenStatus, subscriptionID string) AuthStatus {
+	return AuthStatus{
+		CredentialType:          status.Mode,
+		TenantID:                status.TenantID,
+		ObjectID:                status.ObjectID,
+		UserPrincipalName:       status.Name,
+		AppID:                   status.AppID,
+		ExpiresOn:               status.ExpiresOn.UTC(),
+		SubscriptionID:          subscriptionID,
+		AuthorityHost:           status.AuthorityHost,
+		ResourceManagerEndpoint: status.ResourceManagerEndpoint,
+	}
+}
+
+// PermissionCheck is the result of check_permissions
+type PermissionCheck struct {
+	SubscriptionID string `json:"subscriptionId"`
+	// Scope is the ARM ID of the scope checked
+	Scope string `json:"scope"`
+	// Actions and NotActions are the effective permissions on the scope
+	Actions    []string         `json:"actions"`
+	NotActions []string         `json:"notActions,omitempty"`
+	Tools      []ToolPermission `json:"tools"`
+}
+
+// ToolPermission reports whether a mutating tool is allowed on a scope
+type ToolPermission struct {
+	Tool    string `json:"tool"`
+	Action  string `json:"action"`
+	Allowed bool   `json:"allowed"`
+}
+
+// newPermissionCheck converts the permissions on scope, without the tools
+func newPermissionCheck(subscriptionID string, scope permissionScope, permissions []*armauthorization.Permission) PermissionCheck {
+	check := PermissionCheck{
+		SubscriptionID: subscriptionID,
+		Scope:          scope.resourceID(subscriptionID),
+		Actions:        []string{},
+		Tools:          []ToolPermission{},
+	}
+	for _, p := range permissions {
+		if p == nil {
+			continue
+		}
+		for _, action := range p.Actions {
+			if action != nil && !slices.Contains(check.Actions, *action) {
+				check.Actions = append(check.Actions, *action)
+			}
+		}
+		for _, action := range p.NotActions {
+			if action != nil && !slices.Contains(check.NotActions, *action) {
+				check.NotActions = append(check.NotActions, *action)
+			}
+		}
+	}
+	return check
+}
+
+// RoleAssignment is a role assignment that gives a principal access to a workspace
+type RoleAssignment struct {
+	// Name is the assignment's GUID, which revoke_workspace_role accepts
+	Name             string `json:"name"`
+	ID               string `json:"id"`
+	PrincipalID      string `json:"principalId"`
+	PrincipalType    string `json:"principalType,omitempty"`
+	RoleDefinitionID string `json:"roleDefinitionId"`
+	// RoleName is empty when the role definition could not be read
+	RoleName string `json:"roleName,omitempty"`
+	Scope    string `json:"scope"`
+	// Inherited is set for assignments on a scope above the workspace
+	Inherited   bool       `json:"inherited"`
+	Description string     `json:"description,omitempty"`
+	CreatedOn   *time.Time `json:"createdOn,omitempty"`
+}
+
+// RoleAssignmentList is the result of list_workspace_role_assignments
+type RoleAssignmentList struct {
+	// Scope is the workspace's resource ID
+	Scope           string           `json:"scope"`
+	Count           int              `json:"count"`
+	RoleAssignments []RoleAssignment `json:"roleAssignments"`
+}
+
+// RoleAssignmentChange is the result of grant_workspace_role and revoke_workspace_role
+type RoleAssignmentChange struct {
+	Status         string          `json:"status"`
+	RoleAssignment *RoleAssignment `json:"roleAssignment,omitempty"`
+	Plan           *MutationPlan   `json:"plan,omitempty"`
+}
+
+// newRoleAssignment converts a role assignment, naming its role from names
+func newRoleAssignment(a *armauthorization.RoleAssignment, workspaceID string, names map[string]string) RoleAssignment {
+	assignment := RoleAssignment{Name: valueOf(a.Name), ID: valueOf(a.ID)}
+	if p := a.Properties; p != nil {
+		assignment.PrincipalID = valueOf(p.PrincipalID)
+		if p.PrincipalType != nil {
+			assignment.PrincipalType = string(*p.PrincipalType)
+		}
+		assignment.RoleDefinitionID = valueOf(p.RoleDefinitionID)
+		assignment.RoleName = names[roleGUID(assignment.RoleDefinitionID)]
+		assignment.Scope = valueOf(p.Scope)
+		assignment.Description = valueOf(p.Description)
+		assignment.CreatedOn = p.CreatedOn
+	}
+	assignment.Inherited = assignment.Scope != "" && len(assignment.Scope) < len(workspaceID) &&
+		strings.HasPrefix(strings.ToLower(workspaceID), strings.ToLower(strings.TrimSuffix(assignment.Scope, "/")))
+	return assignment
+}
+
+// AuditEventList is the result of list_audit_events
+type AuditEventList struct {
+	Count  int           `json:"count"`
+	Events []audit.Event `json:"events"`
+}
+
+// Quota is a single Azure ML resource quota
+type Quota struct {
+	Resource string `json:"resource"`
+	Limit    int64  `json:"limit"`
+	Unit     string `json:"unit"`
+	Type     string `json:"type"`
+}
+
+// QuotaList is the result of list_quotas
+type QuotaList struct {
+	Location string  `json:"location"`
+	Count    int     `json:"count"`
+	Quotas   []Quota `json:"quotas"`
+}
+
+// Usage is the current usage of a single Azure ML resource
+type Usage struct {
+	Resource string `json:"resource"`
+	Current  int64  `json:"current"`
+	Limit    int64  `json:"limit"`
+	Unit     string `json:"unit"`
+}
+
+// UsageList is the result of list_usage
+type UsageList struct {
+	Location string  `json:"location"`
+	Count    int     `json:"count"`
+	Usages   []Usage `json:"usages"`
+}
+
+// VMSize is a virtual machine size available to Azure ML compute
+type VMSize struct {
+	Name     string  `json:"name"`
+	VCPUs    int32   `json:"vCPUs"`
+	MemoryGB float64 `json:"memoryGB"`
+}
+
+// VMSizeList is the result of list_vm_sizes
+type VMSizeList struct {
+	Location   string   `json:"location"`
+	Count      int      `json:"count"`
+	VMSizes    []VMSize `json:"vmSizes"`
+	NextCursor string   `json:"nextCursor,omitempty"`
+}
+
+// PrivateEndpoint is a workspace private endpoint connection
+type PrivateEndpoint struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	ID     string `json:"id"`
+}
+
+// PrivateEndpointList is the result of list_private_endpoints
+type PrivateEndpointList struct {
+	Workspace        string            `json:"workspace"`
+	Count            int               `json:"count"`
+	PrivateEndpoints []PrivateEndpoint `json:"privateEndpoints"`
+}
+
+// Connection is a workspace connection
+type Connection struct {
+	Name     string `json:"name"`
+	Category string `json:"category"`
+	AuthType string `json:"authType"`
+	ID       string `json:"id"`
+}
+
+// ConnectionList is the result of list_workspace_connections
+type ConnectionList struct {
+	Workspace   string       `json:"workspace"`
+	Count       int          `json:"count"`
+	Connections []Connection `json:"connections"`
+}
+
+// Feature is a feature available to a workspace
+type Feature struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// FeatureList is the result of list_workspace_features
+type FeatureList struct {
+	Workspace string    `json:"workspace"`
+	Count     int       `json:"count"`
+	Features  []Feature `json:"features"`
+}
+
+func newWorkspace(ws *armmachinelearning.Workspace) Workspace {
+	result := Workspace{
+		Name:     valueOf(ws.Name),
+		ID:       valueOf(ws.ID),
+		Location: valueOf(ws.Location),
+		Type:     valueOf(ws.Type),
+	}
+	if rg := helpers.ExtractResourceGroupFromID(result.ID); rg != "N/A" {
+		result.ResourceGroup = rg
+	}
+	result.URI = resourceURI(result.ID)
+	if ws.SKU != nil {
+		result.SKU = valueOf(ws.SKU.Name)
+	}
+	if props := ws.Properties; props != nil {
+		result.Description = valueOf(props.Description)
+		result.FriendlyName = valueOf(props.FriendlyName)
+		result.DiscoveryURL = valueOf(props.DiscoveryURL)
+		result.MLFlowTrackingURI = valueOf(props.MlFlowTrackingURI)
+		if props.ProvisioningState != nil {
+			result.ProvisioningState = string(*props.ProvisioningState)
+		}
+	}
+	return result
+}
+
+// newComputes converts compute resources, leaving out those without a name or properties
+func newComputes(resources []*armmachinelearning.ComputeResource) []Compute {
+	var computes []Compute
+	for _, compute := range resources {
+		if compute.Name != nil && compute.Properties != nil {
+			computes = append(computes, newCompute(compute))
+		}
+	}
+	return computes
+}
+
+func newCompute(compute *armmachinelearning.ComputeResource) Compute {
+	result := Compute{
+		Name:     valueOf(compute.Name),
+		ID:       valueOf(compute.ID),
+		Location: valueOf(compute.Location),
+	}
+	result.URI = resourceURI(result.ID)
+	if compute.Properties == nil {
+		return result
+	}
+	base := compute.Properties.GetCompute()
+	if base == nil {
+		return result
+	}
+	if base.ComputeType != nil {
+		result.Type = string(*base.ComputeType)
+	}
+	if base.ProvisioningState != nil {
+		result.ProvisioningState = string(*base.ProvisioningState)
+	}
+	result.Description = valueOf(base.Description)
+	result.CreatedOn = base.CreatedOn
+	result.ModifiedOn = base.ModifiedOn
+	result.IsAttached = base.IsAttachedCompute != nil && *base.IsAttachedCompute
+	return result
+}
+
+func newOperation(op operations.Operation) Operation {
+	return Operation{
+		OperationID:   op.ID,
+		Kind:          op.Kind,
+		Target:        op.Target,
+		ResourceGroup: op.ResourceGroup,
+		Workspace:     op.Workspace,
+		Status:        string(op.Status),
+		Error:         op.Error,
+		StartedAt:     op.StartedAt,
+		UpdatedAt:     op.UpdatedAt,
+	}
+}
+
+// valueOf dereferences an optional string, returning "" for nil
+func valueOf(ptr *string) string {
+	if ptr == nil {
+		return ""
+	}
+	return *ptr
+}
+
+// orNA renders an empty value as "N/A" in text output
+func orNA(value string) string {
+	if value == "" {
+		return "N/A"
+	}
+	return value
+}
+
+// formatTime renders an optional timestamp the way the text output always has
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return "N/A"
+	}
+	return t.Format("2006-01-02 15:04:05")
+}
@@ -0,0 +1,71 @@
+package tools_test
+
+import (
+	"testing"
+
+	"github.com/mark3labs/mcp-go/server"
+	"microsoft.com/aml-mcp/internal/azure"
+	"microsoft.com/aml-mcp/internal/tools"
+)
+
+func TestToolsDeclareOutputSchema(t *testing.T) {
+	clients := azure.NewClientCache()
+	s := server.NewMCPServer("test", "1.0.0")
+	tools.NewWorkspaceTools(clients).AddToServer(s)
+	tools.NewComputeTools(clients).AddToServer(s)
+	tools.NewMonitoringTools(clients).AddToServer(s)
+	tools.NewNetworkTools(clients).AddToServer(s)
+	tools.NewOperationTools(clients).AddToServer(s)
+
+	listed := listTools(t, s)
+	if len(listed) == 0 {
+		t.Fatal("no tools registered")
+	}
+	for _, tool := range listed {
+		schema, ok := tool["outputSchema"].(map[string]any)
+		if !ok {
+			t.Errorf("tool %v has no output schema", tool["name"])
+			continue
+		}
+		if schema["type"] != "object" {
+			t.Errorf("tool %v output schema type = %v, want object", tool["name"], schema["type"])
+		}
+	}
+}
+
+func TestStructuredResults(t *testing.T) {
+	fake := newFakeARM(t)
+	clients := fake.ClientCache()
+	s := server.NewMCPServer("test", "1.0.0")
+	tools.NewWorkspaceTools(clients).AddToServer(s)
+	tools.NewComputeTools(clients).AddToServer(s)
+
+	workspaces := structuredAs[tools.WorkspaceList](t, callTool(t, s, "list_workspaces_by_subscription",
+		map[string]any{"subscription_id": testSubscriptionID}))
+	if workspaces.Count != 1 || len(workspaces.Workspaces) != 1 {
+		t.Fatalf("WorkspaceList = %+v, want one workspace", workspaces)
+	}
+	if ws := workspaces.Workspaces[0]; ws.Name != testWorkspace || ws.ResourceGroup != testResourceGroup || ws.SKU != "Basic" {
+		t.Errorf("Workspace = %+v", ws)
+	}
+
+	workspace := structuredAs[tools.Workspace](t, callTool(t, s, "get_workspace", workspaceArgs(nil)))
+	if workspace.FriendlyName != "Test Workspace" || workspace.DiscoveryURL != "" {
+		t.Errorf("Workspace = %+v, want friendly name set and no discovery URL", workspace)
+	}
+
+	compute := structuredAs[tools.Compute](t, callTool(t, s, "get_compute", workspaceArgs(map[string]any{"compute_name": testCompute})))
+	if compute.Name != testCompute || compute.Type != "ComputeInstance" || compute.ProvisioningState != "Succeeded" {
+		t.Errorf("Compute = %+v", compute)
+	}
+
+	op := structuredAs[tools.ComputeOperation](t, callTool(t, s, "start_compute", workspaceArgs(map[string]any{"compute_name": testCompute})))
+	if op.Action != "start" || op.Status != "Succeeded" {
+		t.Errorf("ComputeOperation = %+v", op)
+	}
+
+	computes := structuredAs[tools.ComputeList](t, callTool(t, s, "list_compute", workspaceArgs(nil)))
+	if computes.Count != 1 || computes.Computes[0].Name != testCompute {
+		t.Errorf("ComputeList = %+v, want the seeded compute instance", computes)
+	}
+}
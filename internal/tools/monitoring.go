@@ -1,53 +1,67 @@
 package tools
 
 import (
+	"cmp"
 	"context"
 	"fmt"
 	"strings"
 
 	"github.com/mark3labs/mcp-go/mcp"
-	"github.com/mark3labs/mcp-go/server"
 	"microsoft.com/aml-mcp/internal/azure"
 	"microsoft.com/aml-mcp/internal/helpers"
 )
 
 // MonitoringTools contains all monitoring-related MCP tools (quotas, usage, VM sizes)
-type MonitoringTools struct{}
+type MonitoringTools struct {
+	clients *azure.ClientCache
+	shared
+}
 
-// NewMonitoringTools creates a new MonitoringTools instance
-func NewMonitoringTools() *MonitoringTools {
-	return &MonitoringTools{}
+// NewMonitoringTools creates a new MonitoringTools instance backed by a shared client cache
+func NewMonitoringTools(clients *azure.ClientCache, opts ...Option) *MonitoringTools {
+	return &MonitoringTools{clients: clients, shared: newShared(opts)}
 }
 
 // AddToServer registers all monitoring tools with the MCP server
-func (mt *MonitoringTools) AddToServer(s *server.MCPServer) {
+func (mt *MonitoringTools) AddToServer(s Registrar) {
 	mt.addListQuotasTool(s)
 	mt.addListUsageTool(s)
 	mt.addListVMSizesTool(s)
 }
 
-func (mt *MonitoringTools) addListQuotasTool(s *server.MCPServer) {
+func (mt *MonitoringTools) addListQuotasTool(s Registrar) {
 	tool := mcp.NewTool("list_quotas",
 		mcp.WithDescription("List quotas for Azure ML resources in a location"),
-		mcp.WithString("subscription_id",
-			mcp.Required(),
-			mcp.Description("Azure subscription ID"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOutputSchema[QuotaList](),
+		mcp.WithString(argResourceID,
+			mcp.Description("ARM resource ID of the subscription or any resource in it. Takes precedence over subscription_id"),
+		),
+		mcp.WithString(argSubscriptionID,
+			mcp.Description("Azure subscription ID. Defaults to the active workspace's subscription"),
 		),
 		mcp.WithString("location",
 			mcp.Required(),
 			mcp.Description("Azure region location (e.g., eastus, westus2)"),
 		),
+		mcp.WithBoolean(argRefresh,
+			mcp.Description(refreshDescription),
+		),
 	)
 
 	s.AddTool(tool, mt.handleListQuotas)
 }
 
-func (mt *MonitoringTools) addListUsageTool(s *server.MCPServer) {
+func (mt *MonitoringTools) addListUsageTool(s Registrar) {
 	tool := mcp.NewTool("list_usage",
 		mcp.WithDescription("List current usage for Azure ML resources in a location"),
-		mcp.WithString("subscription_id",
-			mcp.Required(),
-			mcp.Description("Azure subscription ID"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOutputSchema[UsageList](),
+		mcp.WithString(argResourceID,
+			mcp.Description("ARM resource ID of the subscription or any resource in it. Takes precedence over subscription_id"),
+		),
+		mcp.WithString(argSubscriptionID,
+			mcp.Description("Azure subscription ID. Defaults to the active workspace's subscription"),
 		),
 		mcp.WithString("location",
 			mcp.Required(),
@@ -58,24 +72,41 @@ func (mt *MonitoringTools) addListUsageTool(s *server.MCPServer) {
 	s.AddTool(tool, mt.handleListUsage)
 }
 
-func (mt *MonitoringTools) addListVMSizesTool(s *server.MCPServer) {
+func (mt *MonitoringTools) addListVMSizesTool(s Registrar) {
 	tool := mcp.NewTool("list_vm_sizes",
 		mcp.WithDescription("List available virtual machine sizes for Azure ML compute"),
-		mcp.WithString("subscription_id",
-			mcp.Required(),
-			mcp.Description("Azure subscription ID"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOutputSchema[VMSizeList](),
+		mcp.WithString(argResourceID,
+			mcp.Description("ARM resource ID of the subscription or any resource in it. Takes precedence over subscription_id"),
+		),
+		mcp.WithString(argSubscriptionID,
+			mcp.Description("Azure subscription ID. Defaults to the active workspace's subscription"),
 		),
 		mcp.WithString("location",
 			mcp.Required(),
 			mcp.Description("Azure region location (e.g., eastus, westus2)"),
 		),
+		mcp.WithString(argNameContains,
+			mcp.Description("Only list VM sizes whose name contains this text, ignoring case (e.g., NC for GPU sizes)"),
+		),
+		mcp.WithNumber("min_vcpus",
+			mcp.Description("Only list VM sizes with at least this many vCPUs"),
+		),
+		mcp.WithNumber("min_memory_gb",
+			mcp.Description("Only list VM sizes with at least this much memory, in GB"),
+		),
+		mcp.WithBoolean(argRefresh,
+			mcp.Description(refreshDescription),
+		),
 	)
+	addListOptions(&tool, "name", "vCPUs", "memoryGB")
 
 	s.AddTool(tool, mt.handleListVMSizes)
 }
 
 func (mt *MonitoringTools) handleListQuotas(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	subscriptionID, err := request.RequireString("subscription_id")
+	subscriptionID, err := mt.scopeArgument(ctx, request, argSubscriptionID)
 	if err != nil {
 		return mcp.NewToolResultError(err.Error()), nil
 	}
@@ -85,40 +116,46 @@ func (mt *MonitoringTools) handleListQuotas(ctx context.Context, request mcp.Cal
 		return mcp.NewToolResultError(err.Error()), nil
 	}
 
-	clients, err := azure.NewClientSet(subscriptionID)
+	clients, err := mt.clients.Get(ctx, subscriptionID)
 	if err != nil {
-		return mcp.NewToolResultError(err.Error()), nil
+		return azureError(mt.clients, "Failed to sign in to Azure", err), nil
 	}
 
 	pager := clients.QuotasClient.NewListPager(location, nil)
-	var quotas []string
+	result := QuotaList{Location: location, Quotas: []Quota{}}
+	var lines []string
 
 	for pager.More() {
 		page, err := pager.NextPage(ctx)
 		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("Failed to get quotas: %v", err)), nil
+			return azureError(mt.clients, "Failed to get quotas", err), nil
 		}
 
 		for _, quota := range page.Value {
 			if quota.Name != nil && quota.Limit != nil {
-				quotas = append(quotas, fmt.Sprintf("Resource: %s, Limit: %d, Unit: %s, Type: %s",
-					helpers.GetStringValue(quota.Name.Value),
-					*quota.Limit,
-					helpers.GetQuotaUnit(quota.Unit),
-					helpers.GetStringValue(quota.Type)))
+				q := Quota{
+					Resource: helpers.GetStringValue(quota.Name.Value),
+					Limit:    *quota.Limit,
+					Unit:     helpers.GetQuotaUnit(quota.Unit),
+					Type:     helpers.GetStringValue(quota.Type),
+				}
+				result.Quotas = append(result.Quotas, q)
+				lines = append(lines, fmt.Sprintf("Resource: %s, Limit: %d, Unit: %s, Type: %s",
+					q.Resource, q.Limit, q.Unit, q.Type))
 			}
 		}
 	}
+	result.Count = len(result.Quotas)
 
-	if len(quotas) == 0 {
-		return mcp.NewToolResultText(fmt.Sprintf("No quotas found for location '%s'.", location)), nil
+	if result.Count == 0 {
+		return mcp.NewToolResultStructured(result, fmt.Sprintf("No quotas found for location '%s'.", location)), nil
 	}
 
-	return mcp.NewToolResultText(fmt.Sprintf("Found %d quotas for location '%s':\n%s", len(quotas), location, strings.Join(quotas, "\n"))), nil
+	return mcp.NewToolResultStructured(result, fmt.Sprintf("Found %d quotas for location '%s':\n%s", result.Count, location, strings.Join(lines, "\n"))), nil
 }
 
 func (mt *MonitoringTools) handleListUsage(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	subscriptionID, err := request.RequireString("subscription_id")
+	subscriptionID, err := mt.scopeArgument(ctx, request, argSubscriptionID)
 	if err != nil {
 		return mcp.NewToolResultError(err.Error()), nil
 	}
@@ -128,40 +165,46 @@ func (mt *MonitoringTools) handleListUsage(ctx context.Context, request mcp.Call
 		return mcp.NewToolResultError(err.Error()), nil
 	}
 
-	clients, err := azure.NewClientSet(subscriptionID)
+	clients, err := mt.clients.Get(ctx, subscriptionID)
 	if err != nil {
-		return mcp.NewToolResultError(err.Error()), nil
+		return azureError(mt.clients, "Failed to sign in to Azure", err), nil
 	}
 
 	pager := clients.UsagesClient.NewListPager(location, nil)
-	var usages []string
+	result := UsageList{Location: location, Usages: []Usage{}}
+	var lines []string
 
 	for pager.More() {
 		page, err := pager.NextPage(ctx)
 		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("Failed to get usage: %v", err)), nil
+			return azureError(mt.clients, "Failed to get usage", err), nil
 		}
 
 		for _, usage := range page.Value {
 			if usage.Name != nil && usage.CurrentValue != nil && usage.Limit != nil {
-				usages = append(usages, fmt.Sprintf("Resource: %s, Current: %d, Limit: %d, Unit: %s",
-					helpers.GetStringValue(usage.Name.Value),
-					*usage.CurrentValue,
-					*usage.Limit,
-					helpers.GetUsageUnit(usage.Unit)))
+				u := Usage{
+					Resource: helpers.GetStringValue(usage.Name.Value),
+					Current:  *usage.CurrentValue,
+					Limit:    *usage.Limit,
+					Unit:     helpers.GetUsageUnit(usage.Unit),
+				}
+				result.Usages = append(result.Usages, u)
+				lines = append(lines, fmt.Sprintf("Resource: %s, Current: %d, Limit: %d, Unit: %s",
+					u.Resource, u.Current, u.Limit, u.Unit))
 			}
 		}
 	}
+	result.Count = len(result.Usages)
 
-	if len(usages) == 0 {
-		return mcp.NewToolResultText(fmt.Sprintf("No usage data found for location '%s'.", location)), nil
+	if result.Count == 0 {
+		return mcp.NewToolResultStructured(result, fmt.Sprintf("No usage data found for location '%s'.", location)), nil
 	}
 
-	return mcp.NewToolResultText(fmt.Sprintf("Found %d usage entries for location '%s':\n%s", len(usages), location, strings.Join(usages, "\n"))), nil
+	return mcp.NewToolResultStructured(result, fmt.Sprintf("Found %d usage entries for location '%s':\n%s", result.Count, location, strings.Join(lines, "\n"))), nil
 }
 
 func (mt *MonitoringTools) handleListVMSizes(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	subscriptionID, err := request.RequireString("subscription_id")
+	subscriptionID, err := mt.scopeArgument(ctx, request, argSubscriptionID)
 	if err != nil {
 		return mcp.NewToolResultError(err.Error()), nil
 	}
@@ -171,29 +214,61 @@ func (mt *MonitoringTools) handleListVMSizes(ctx context.Context, request mcp.Ca
 		return mcp.NewToolResultError(err.Error()), nil
 	}
 
-	clients, err := azure.NewClientSet(subscriptionID)
+	query, err := newListQuery(request, subscriptionID)
 	if err != nil {
 		return mcp.NewToolResultError(err.Error()), nil
 	}
 
-	resp, err := clients.VirtualMachineSizesClient.List(ctx, location, nil)
+	clients, err := mt.clients.Get(ctx, subscriptionID)
 	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("Failed to get VM sizes: %v", err)), nil
+		return azureError(mt.clients, "Failed to sign in to Azure", err), nil
 	}
 
-	var vmSizes []string
-	for _, vmSize := range resp.Value {
-		if vmSize.Name != nil {
-			vmSizes = append(vmSizes, fmt.Sprintf("Name: %s, vCPUs: %d, Memory: %.1f GB",
-				*vmSize.Name,
-				helpers.GetInt32Value(vmSize.VCPUs),
-				helpers.GetFloat64Value(vmSize.MemoryGB)))
+	// VM sizes come in a single response, so pages are cut from it
+	fetch := func(ctx context.Context, _ string) ([]VMSize, string, error) {
+		resp, err := clients.VirtualMachineSizesClient.List(ctx, location, nil)
+		if err != nil {
+			return nil, "", err
 		}
+		var sizes []VMSize
+		for _, vmSize := range resp.Value {
+			if vmSize.Name != nil {
+				sizes = append(sizes, VMSize{
+					Name:     *vmSize.Name,
+					VCPUs:    helpers.GetInt32Value(vmSize.VCPUs),
+					MemoryGB: helpers.GetFloat64Value(vmSize.MemoryGB),
+				})
+			}
+		}
+		return sizes, "", nil
+	}
+	nameContains := request.GetString(argNameContains, "")
+	minVCPUs := request.GetFloat("min_vcpus", 0)
+	minMemoryGB := request.GetFloat("min_memory_gb", 0)
+	match := func(size VMSize) bool {
+		return matchNameContains(nameContains, size.Name) && float64(size.VCPUs) >= minVCPUs && size.MemoryGB >= minMemoryGB
+	}
+	compares := map[string]func(a, b VMSize) int{
+		"name":     func(a, b VMSize) int { return compareFold(a.Name, b.Name) },
+		"vCPUs":    func(a, b VMSize) int { return cmp.Compare(a.VCPUs, b.VCPUs) },
+		"memoryGB": func(a, b VMSize) int { return cmp.Compare(a.MemoryGB, b.MemoryGB) },
 	}
 
-	if len(vmSizes) == 0 {
-		return mcp.NewToolResultText(fmt.Sprintf("No VM sizes found for location '%s'.", location)), nil
+	sizes, nextCursor, err := listPage(ctx, query, fetch, match, compares)
+	if err != nil {
+		return azureError(mt.clients, "Failed to get VM sizes", err), nil
 	}
+	result := VMSizeList{Location: location, Count: len(sizes), VMSizes: sizes, NextCursor: nextCursor}
 
-	return mcp.NewToolResultText(fmt.Sprintf("Found %d VM sizes for location '%s':\n%s", len(vmSizes), location, strings.Join(vmSizes, "\n"))), nil
+	if result.Count == 0 {
+		return mcp.NewToolResultStructured(result, fmt.Sprintf("No VM sizes found for location '%s'.", location)), nil
+	}
+
+	lines := make([]string, 0, len(sizes))
+	for _, size := range sizes {
+		lines = append(lines, fmt.Sprintf("Name: %s, vCPUs: %d, Memory: %.1f GB",
+			size.Name, size.VCPUs, size.MemoryGB))
+	}
+	return mcp.NewToolResultStructured(result, fmt.Sprintf("Found %d VM sizes for location '%s':\n%s%s",
+		result.Count, location, strings.Join(lines, "\n"), moreResultsText(nextCursor))), nil
 }
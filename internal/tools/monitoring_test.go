@@ -1,35 +1,39 @@
 package tools_test
 
 import (
+	"strings"
 	"testing"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/machinelearning/armmachinelearning"
 	"github.com/mark3labs/mcp-go/server"
+	"microsoft.com/aml-mcp/internal/azure"
 	"microsoft.com/aml-mcp/internal/tools"
 )
 
 func TestMonitoringTools_AddToServer(t *testing.T) {
 	s := server.NewMCPServer("test", "1.0.0")
-	monitoringTools := tools.NewMonitoringTools()
+	monitoringTools := tools.NewMonitoringTools(azure.NewClientCache())
 
 	// Test that AddToServer doesn't panic
 	monitoringTools.AddToServer(s)
 }
 
 func TestMonitoringTools_New(t *testing.T) {
-	monitoringTools := tools.NewMonitoringTools()
+	monitoringTools := tools.NewMonitoringTools(azure.NewClientCache())
 	if monitoringTools == nil {
 		t.Error("NewMonitoringTools() returned nil")
 	}
 }
 
 func TestMonitoringToolsStructure(t *testing.T) {
-	mt := tools.NewMonitoringTools()
+	mt := tools.NewMonitoringTools(azure.NewClientCache())
 	if mt == nil {
 		t.Fatal("NewMonitoringTools() returned nil")
 	}
 
 	// Test that we can create multiple instances
-	mt2 := tools.NewMonitoringTools()
+	mt2 := tools.NewMonitoringTools(azure.NewClientCache())
 	if mt2 == nil {
 		t.Fatal("Second NewMonitoringTools() returned nil")
 	}
@@ -39,3 +43,82 @@ func TestMonitoringToolsStructure(t *testing.T) {
 		t.Error("NewMonitoringTools() returned the same instance")
 	}
 }
+
+func TestMonitoringToolHandlers(t *testing.T) {
+	fake := newFakeARM(t)
+	fake.SetQuotas(testSubscriptionID, testLocation, &armmachinelearning.ResourceQuota{
+		Name:  &armmachinelearning.ResourceName{Value: to.Ptr("standardDSv2Family")},
+		Limit: to.Ptr(int64(100)),
+		Unit:  to.Ptr(armmachinelearning.QuotaUnitCount),
+		Type:  to.Ptr("Microsoft.MachineLearningServices/vmFamily/quotas"),
+	})
+	fake.SetUsages(testSubscriptionID, testLocation, &armmachinelearning.Usage{
+		Name:         &armmachinelearning.UsageName{Value: to.Ptr("Total Cluster Dedicated Regional vCPUs")},
+		CurrentValue: to.Ptr(int64(12)),
+		Limit:        to.Ptr(int64(300)),
+		Unit:         to.Ptr(armmachinelearning.UsageUnitCount),
+	})
+	fake.SetVMSizes(testSubscriptionID, testLocation, &armmachinelearning.VirtualMachineSize{
+		Name:     to.Ptr("Standard_NC6s_v3"),
+		VCPUs:    to.Ptr(int32(6)),
+		MemoryGB: to.Ptr(112.0),
+	})
+
+	s := server.NewMCPServer("test", "1.0.0")
+	tools.NewMonitoringTools(fake.ClientCache()).AddToServer(s)
+
+	tests := []struct {
+		name      string
+		toolName  string
+		args      map[string]any
+		wantError bool
+		contains  []string
+	}{
+		{
+			name:     "list quotas",
+			toolName: "list_quotas",
+			args:     map[string]any{"subscription_id": testSubscriptionID, "location": testLocation},
+			contains: []string{"Found 1 quotas for location 'eastus'", "Resource: standardDSv2Family, Limit: 100, Unit: Count"},
+		},
+		{
+			name:     "list quotas in empty location",
+			toolName: "list_quotas",
+			args:     map[string]any{"subscription_id": testSubscriptionID, "location": "westus"},
+			contains: []string{"No quotas found for location 'westus'"},
+		},
+		{
+			name:     "list usage",
+			toolName: "list_usage",
+			args:     map[string]any{"subscription_id": testSubscriptionID, "location": testLocation},
+			contains: []string{"Resource: Total Cluster Dedicated Regional vCPUs, Current: 12, Limit: 300, Unit: Count"},
+		},
+		{
+			name:     "list VM sizes",
+			toolName: "list_vm_sizes",
+			args:     map[string]any{"subscription_id": testSubscriptionID, "location": testLocation},
+			contains: []string{"Name: Standard_NC6s_v3, vCPUs: 6, Memory: 112.0 GB"},
+		},
+		{
+			name:      "list VM sizes missing location",
+			toolName:  "list_vm_sizes",
+			args:      map[string]any{"subscription_id": testSubscriptionID},
+			wantError: true,
+			contains:  []string{"location"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := callTool(t, s, tt.toolName, tt.args)
+			text := resultText(result)
+			if result.IsError != tt.wantError {
+				t.Fatalf("IsError = %v, want %v (text: %s)", result.IsError, tt.wantError, text)
+			}
+			for _, want := range tt.contains {
+				if !strings.Contains(text, want) {
+					t.Errorf("result %q does not contain %q", text, want)
+				}
+			}
+		})
+	}
+}
@@ -6,80 +6,91 @@ import (
 	"strings"
 
 	"github.com/mark3labs/mcp-go/mcp"
-	"github.com/mark3labs/mcp-go/server"
 	"microsoft.com/aml-mcp/internal/azure"
 	"microsoft.com/aml-mcp/internal/helpers"
 )
 
 // NetworkTools contains all network and security-related MCP tools
-type NetworkTools struct{}
+type NetworkTools struct {
+	clients *azure.ClientCache
+	shared
+}
 
-// NewNetworkTools creates a new NetworkTools instance
-func NewNetworkTools() *NetworkTools {
-	return &NetworkTools{}
+// NewNetworkTools creates a new NetworkTools instance backed by a shared client cache
+func NewNetworkTools(clients *azure.ClientCache, opts ...Option) *NetworkTools {
+	return &NetworkTools{clients: clients, shared: newShared(opts)}
 }
 
 // AddToServer registers all network tools with the MCP server
-func (nt *NetworkTools) AddToServer(s *server.MCPServer) {
+func (nt *NetworkTools) AddToServer(s Registrar) {
 	nt.addListPrivateEndpointsTool(s)
 	nt.addListWorkspaceConnectionsTool(s)
 	nt.addListWorkspaceFeaturesTool(s)
 }
 
-func (nt *NetworkTools) addListPrivateEndpointsTool(s *server.MCPServer) {
+func (nt *NetworkTools) addListPrivateEndpointsTool(s Registrar) {
 	tool := mcp.NewTool("list_private_endpoints",
 		mcp.WithDescription("List private endpoint connections for a workspace"),
-		mcp.WithString("subscription_id",
-			mcp.Required(),
-			mcp.Description("Azure subscription ID"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOutputSchema[PrivateEndpointList](),
+		mcp.WithString(argResourceID,
+			mcp.Description("Full ARM resource ID of the workspace. Takes precedence over subscription_id, resource_group_name and workspace_name"),
+		),
+		mcp.WithString(argSubscriptionID,
+			mcp.Description("Azure subscription ID. Defaults to the active workspace's subscription"),
 		),
-		mcp.WithString("resource_group_name",
-			mcp.Required(),
-			mcp.Description("Resource group name"),
+		mcp.WithString(argResourceGroup,
+			mcp.Description("Resource group name. Defaults to the active workspace's resource group"),
 		),
-		mcp.WithString("workspace_name",
-			mcp.Required(),
-			mcp.Description("Workspace name"),
+		mcp.WithString(argWorkspace,
+			mcp.Description("Workspace name. Defaults to the active workspace"),
 		),
 	)
 
 	s.AddTool(tool, nt.handleListPrivateEndpoints)
 }
 
-func (nt *NetworkTools) addListWorkspaceConnectionsTool(s *server.MCPServer) {
+func (nt *NetworkTools) addListWorkspaceConnectionsTool(s Registrar) {
 	tool := mcp.NewTool("list_workspace_connections",
 		mcp.WithDescription("List connections for a workspace"),
-		mcp.WithString("subscription_id",
-			mcp.Required(),
-			mcp.Description("Azure subscription ID"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOutputSchema[ConnectionList](),
+		mcp.WithString(argResourceID,
+			mcp.Description("Full ARM resource ID of the workspace. Takes precedence over subscription_id, resource_group_name and workspace_name"),
+		),
+		mcp.WithString(argSubscriptionID,
+			mcp.Description("Azure subscription ID. Defaults to the active workspace's subscription"),
 		),
-		mcp.WithString("resource_group_name",
-			mcp.Required(),
-			mcp.Description("Resource group name"),
+		mcp.WithString(argResourceGroup,
+			mcp.Description("Resource group name. Defaults to the active workspace's resource group"),
 		),
-		mcp.WithString("workspace_name",
-			mcp.Required(),
-			mcp.Description("Workspace name"),
+		mcp.WithString(argWorkspace,
+			mcp.Description("Workspace name. Defaults to the active workspace"),
 		),
 	)
 
 	s.AddTool(tool, nt.handleListWorkspaceConnections)
 }
 
-func (nt *NetworkTools) addListWorkspaceFeaturesTool(s *server.MCPServer) {
+func (nt *NetworkTools) addListWorkspaceFeaturesTool(s Registrar) {
 	tool := mcp.NewTool("list_workspace_features",
 		mcp.WithDescription("List available features for a workspace"),
-		mcp.WithString("subscription_id",
-			mcp.Required(),
-			mcp.Description("Azure subscription ID"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOutputSchema[FeatureList](),
+		mcp.WithString(argResourceID,
+			mcp.Description("Full ARM resource ID of the workspace. Takes precedence over subscription_id, resource_group_name and workspace_name"),
+		),
+		mcp.WithString(argSubscriptionID,
+			mcp.Description("Azure subscription ID. Defaults to the active workspace's subscription"),
+		),
+		mcp.WithString(argResourceGroup,
+			mcp.Description("Resource group name. Defaults to the active workspace's resource group"),
 		),
-		mcp.WithString("resource_group_name",
-			mcp.Required(),
-			mcp.Description("Resource group name"),
+		mcp.WithString(argWorkspace,
+			mcp.Description("Workspace name. Defaults to the active workspace"),
 		),
-		mcp.WithString("workspace_name",
-			mcp.Required(),
-			mcp.Description("Workspace name"),
+		mcp.WithBoolean(argRefresh,
+			mcp.Description(refreshDescription),
 		),
 	)
 
@@ -87,33 +98,34 @@ func (nt *NetworkTools) addListWorkspaceFeaturesTool(s *server.MCPServer) {
 }
 
 func (nt *NetworkTools) handleListPrivateEndpoints(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	subscriptionID, err := request.RequireString("subscription_id")
+	subscriptionID, err := nt.scopeArgument(ctx, request, argSubscriptionID)
 	if err != nil {
 		return mcp.NewToolResultError(err.Error()), nil
 	}
 
-	resourceGroupName, err := request.RequireString("resource_group_name")
+	resourceGroupName, err := nt.scopeArgument(ctx, request, argResourceGroup)
 	if err != nil {
 		return mcp.NewToolResultError(err.Error()), nil
 	}
 
-	workspaceName, err := request.RequireString("workspace_name")
+	workspaceName, err := nt.scopeArgument(ctx, request, argWorkspace)
 	if err != nil {
 		return mcp.NewToolResultError(err.Error()), nil
 	}
 
-	clients, err := azure.NewClientSet(subscriptionID)
+	clients, err := nt.clients.Get(ctx, subscriptionID)
 	if err != nil {
-		return mcp.NewToolResultError(err.Error()), nil
+		return azureError(nt.clients, "Failed to sign in to Azure", err), nil
 	}
 
 	pager := clients.PrivateEndpointClient.NewListPager(resourceGroupName, workspaceName, nil)
-	var endpoints []string
+	result := PrivateEndpointList{Workspace: workspaceName, PrivateEndpoints: []PrivateEndpoint{}}
+	var lines []string
 
 	for pager.More() {
 		page, err := pager.NextPage(ctx)
 		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("Failed to get private endpoints: %v", err)), nil
+			return azureError(nt.clients, "Failed to get private endpoints", err), nil
 		}
 
 		for _, endpoint := range page.Value {
@@ -122,47 +134,50 @@ func (nt *NetworkTools) handleListPrivateEndpoints(ctx context.Context, request
 				if endpoint.Properties != nil && endpoint.Properties.PrivateLinkServiceConnectionState != nil {
 					status = helpers.GetPrivateEndpointStatus(endpoint.Properties.PrivateLinkServiceConnectionState.Status)
 				}
-				endpoints = append(endpoints, fmt.Sprintf("Name: %s, Status: %s, ID: %s",
-					*endpoint.Name, status, helpers.GetStringValue(endpoint.ID)))
+				pe := PrivateEndpoint{Name: *endpoint.Name, Status: status, ID: helpers.GetStringValue(endpoint.ID)}
+				result.PrivateEndpoints = append(result.PrivateEndpoints, pe)
+				lines = append(lines, fmt.Sprintf("Name: %s, Status: %s, ID: %s", pe.Name, pe.Status, pe.ID))
 			}
 		}
 	}
+	result.Count = len(result.PrivateEndpoints)
 
-	if len(endpoints) == 0 {
-		return mcp.NewToolResultText("No private endpoint connections found."), nil
+	if result.Count == 0 {
+		return mcp.NewToolResultStructured(result, "No private endpoint connections found."), nil
 	}
 
-	return mcp.NewToolResultText(fmt.Sprintf("Found %d private endpoint connections:\n%s", len(endpoints), strings.Join(endpoints, "\n"))), nil
+	return mcp.NewToolResultStructured(result, fmt.Sprintf("Found %d private endpoint connections:\n%s", result.Count, strings.Join(lines, "\n"))), nil
 }
 
 func (nt *NetworkTools) handleListWorkspaceConnections(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	subscriptionID, err := request.RequireString("subscription_id")
+	subscriptionID, err := nt.scopeArgument(ctx, request, argSubscriptionID)
 	if err != nil {
 		return mcp.NewToolResultError(err.Error()), nil
 	}
 
-	resourceGroupName, err := request.RequireString("resource_group_name")
+	resourceGroupName, err := nt.scopeArgument(ctx, request, argResourceGroup)
 	if err != nil {
 		return mcp.NewToolResultError(err.Error()), nil
 	}
 
-	workspaceName, err := request.RequireString("workspace_name")
+	workspaceName, err := nt.scopeArgument(ctx, request, argWorkspace)
 	if err != nil {
 		return mcp.NewToolResultError(err.Error()), nil
 	}
 
-	clients, err := azure.NewClientSet(subscriptionID)
+	clients, err := nt.clients.Get(ctx, subscriptionID)
 	if err != nil {
-		return mcp.NewToolResultError(err.Error()), nil
+		return azureError(nt.clients, "Failed to sign in to Azure", err), nil
 	}
 
 	pager := clients.WorkspaceConnectionsClient.NewListPager(resourceGroupName, workspaceName, nil)
-	var connections []string
+	result := ConnectionList{Workspace: workspaceName, Connections: []Connection{}}
+	var lines []string
 
 	for pager.More() {
 		page, err := pager.NextPage(ctx)
 		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("Failed to get workspace connections: %v", err)), nil
+			return azureError(nt.clients, "Failed to get workspace connections", err), nil
 		}
 
 		for _, connection := range page.Value {
@@ -173,62 +188,70 @@ func (nt *NetworkTools) handleListWorkspaceConnections(ctx context.Context, requ
 					authType = helpers.GetStringValue(connection.Properties.AuthType)
 					category = helpers.GetStringValue(connection.Properties.Category)
 				}
-				connections = append(connections, fmt.Sprintf("Name: %s, Category: %s, Auth Type: %s, ID: %s",
-					*connection.Name, category, authType, helpers.GetStringValue(connection.ID)))
+				conn := Connection{Name: *connection.Name, Category: category, AuthType: authType, ID: helpers.GetStringValue(connection.ID)}
+				result.Connections = append(result.Connections, conn)
+				lines = append(lines, fmt.Sprintf("Name: %s, Category: %s, Auth Type: %s, ID: %s",
+					conn.Name, conn.Category, conn.AuthType, conn.ID))
 			}
 		}
 	}
+	result.Count = len(result.Connections)
 
-	if len(connections) == 0 {
-		return mcp.NewToolResultText("No workspace connections found."), nil
+	if result.Count == 0 {
+		return mcp.NewToolResultStructured(result, "No workspace connections found."), nil
 	}
 
-	return mcp.NewToolResultText(fmt.Sprintf("Found %d workspace connections:\n%s", len(connections), strings.Join(connections, "\n"))), nil
+	return mcp.NewToolResultStructured(result, fmt.Sprintf("Found %d workspace connections:\n%s", result.Count, strings.Join(lines, "\n"))), nil
 }
 
 func (nt *NetworkTools) handleListWorkspaceFeatures(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	subscriptionID, err := request.RequireString("subscription_id")
+	subscriptionID, err := nt.scopeArgument(ctx, request, argSubscriptionID)
 	if err != nil {
 		return mcp.NewToolResultError(err.Error()), nil
 	}
 
-	resourceGroupName, err := request.RequireString("resource_group_name")
+	resourceGroupName, err := nt.scopeArgument(ctx, request, argResourceGroup)
 	if err != nil {
 		return mcp.NewToolResultError(err.Error()), nil
 	}
 
-	workspaceName, err := request.RequireString("workspace_name")
+	workspaceName, err := nt.scopeArgument(ctx, request, argWorkspace)
 	if err != nil {
 		return mcp.NewToolResultError(err.Error()), nil
 	}
 
-	clients, err := azure.NewClientSet(subscriptionID)
+	clients, err := nt.clients.Get(ctx, subscriptionID)
 	if err != nil {
-		return mcp.NewToolResultError(err.Error()), nil
+		return azureError(nt.clients, "Failed to sign in to Azure", err), nil
 	}
 
 	pager := clients.WorkspaceFeaturesClient.NewListPager(resourceGroupName, workspaceName, nil)
-	var features []string
+	result := FeatureList{Workspace: workspaceName, Features: []Feature{}}
+	var lines []string
 
 	for pager.More() {
 		page, err := pager.NextPage(ctx)
 		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("Failed to get workspace features: %v", err)), nil
+			return azureError(nt.clients, "Failed to get workspace features", err), nil
 		}
 
 		for _, feature := range page.Value {
 			if feature.ID != nil {
-				displayName := helpers.GetStringValue(feature.DisplayName)
-				description := helpers.GetStringValue(feature.Description)
-				features = append(features, fmt.Sprintf("ID: %s, Name: %s, Description: %s",
-					*feature.ID, displayName, description))
+				f := Feature{
+					ID:          *feature.ID,
+					Name:        helpers.GetStringValue(feature.DisplayName),
+					Description: helpers.GetStringValue(feature.Description),
+				}
+				result.Features = append(result.Features, f)
+				lines = append(lines, fmt.Sprintf("ID: %s, Name: %s, Description: %s", f.ID, f.Name, f.Description))
 			}
 		}
 	}
+	result.Count = len(result.Features)
 
-	if len(features) == 0 {
-		return mcp.NewToolResultText("No workspace features found."), nil
+	if result.Count == 0 {
+		return mcp.NewToolResultStructured(result, "No workspace features found."), nil
 	}
 
-	return mcp.NewToolResultText(fmt.Sprintf("Found %d workspace features:\n%s", len(features), strings.Join(features, "\n"))), nil
+	return mcp.NewToolResultStructured(result, fmt.Sprintf("Found %d workspace features:\n%s", result.Count, strings.Join(lines, "\n"))), nil
 }
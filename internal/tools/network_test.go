@@ -1,35 +1,39 @@
 package tools_test
 
 import (
+	"strings"
 	"testing"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/machinelearning/armmachinelearning"
 	"github.com/mark3labs/mcp-go/server"
+	"microsoft.com/aml-mcp/internal/azure"
 	"microsoft.com/aml-mcp/internal/tools"
 )
 
 func TestNetworkTools_AddToServer(t *testing.T) {
 	s := server.NewMCPServer("test", "1.0.0")
-	networkTools := tools.NewNetworkTools()
+	networkTools := tools.NewNetworkTools(azure.NewClientCache())
 
 	// Test that AddToServer doesn't panic
 	networkTools.AddToServer(s)
 }
 
 func TestNetworkTools_New(t *testing.T) {
-	networkTools := tools.NewNetworkTools()
+	networkTools := tools.NewNetworkTools(azure.NewClientCache())
 	if networkTools == nil {
 		t.Error("NewNetworkTools() returned nil")
 	}
 }
 
 func TestNetworkToolsStructure(t *testing.T) {
-	nt := tools.NewNetworkTools()
+	nt := tools.NewNetworkTools(azure.NewClientCache())
 	if nt == nil {
 		t.Fatal("NewNetworkTools() returned nil")
 	}
 
 	// Test that we can create multiple instances
-	nt2 := tools.NewNetworkTools()
+	nt2 := tools.NewNetworkTools(azure.NewClientCache())
 	if nt2 == nil {
 		t.Fatal("Second NewNetworkTools() returned nil")
 	}
@@ -39,3 +43,81 @@ func TestNetworkToolsStructure(t *testing.T) {
 		t.Error("NewNetworkTools() returned the same instance")
 	}
 }
+
+func TestNetworkToolHandlers(t *testing.T) {
+	fake := newFakeARM(t)
+	fake.AddPrivateEndpointConnection(testSubscriptionID, testResourceGroup, testWorkspace, &armmachinelearning.PrivateEndpointConnection{
+		Name: to.Ptr("pe-1"),
+		ID:   to.Ptr("/subscriptions/sub/pe-1"),
+		Properties: &armmachinelearning.PrivateEndpointConnectionProperties{
+			PrivateLinkServiceConnectionState: &armmachinelearning.PrivateLinkServiceConnectionState{
+				Status: to.Ptr(armmachinelearning.PrivateEndpointServiceConnectionStatusApproved),
+			},
+		},
+	})
+	fake.AddWorkspaceConnection(testSubscriptionID, testResourceGroup, testWorkspace, &armmachinelearning.WorkspaceConnection{
+		Name: to.Ptr("conn-1"),
+		ID:   to.Ptr("/subscriptions/sub/conn-1"),
+		Properties: &armmachinelearning.WorkspaceConnectionProps{
+			AuthType: to.Ptr("PAT"),
+			Category: to.Ptr("ACR"),
+		},
+	})
+	fake.AddWorkspaceFeature(testSubscriptionID, testResourceGroup, testWorkspace, &armmachinelearning.AmlUserFeature{
+		ID:          to.Ptr("feature-1"),
+		DisplayName: to.Ptr("Feature One"),
+		Description: to.Ptr("The first feature"),
+	})
+
+	s := server.NewMCPServer("test", "1.0.0")
+	tools.NewNetworkTools(fake.ClientCache()).AddToServer(s)
+
+	tests := []struct {
+		name      string
+		toolName  string
+		args      map[string]any
+		wantError bool
+		contains  []string
+	}{
+		{
+			name:     "list private endpoints",
+			toolName: "list_private_endpoints",
+			args:     workspaceArgs(nil),
+			contains: []string{"Found 1 private endpoint connections", "Name: pe-1, Status: Approved"},
+		},
+		{
+			name:     "list workspace connections",
+			toolName: "list_workspace_connections",
+			args:     workspaceArgs(nil),
+			contains: []string{"Name: conn-1, Category: ACR, Auth Type: PAT"},
+		},
+		{
+			name:     "list workspace features",
+			toolName: "list_workspace_features",
+			args:     workspaceArgs(nil),
+			contains: []string{"ID: feature-1, Name: Feature One, Description: The first feature"},
+		},
+		{
+			name:      "list private endpoints for missing workspace",
+			toolName:  "list_private_endpoints",
+			args:      map[string]any{"subscription_id": testSubscriptionID, "resource_group_name": testResourceGroup, "workspace_name": "missing"},
+			wantError: true,
+			contains:  []string{"Failed to get private endpoints", "ResourceNotFound"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := callTool(t, s, tt.toolName, tt.args)
+			text := resultText(result)
+			if result.IsError != tt.wantError {
+				t.Fatalf("IsError = %v, want %v (text: %s)", result.IsError, tt.wantError, text)
+			}
+			for _, want := range tt.contains {
+				if !strings.Contains(text, want) {
+					t.Errorf("result %q does not contain %q", text, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,178 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"microsoft.com/aml-mcp/internal/azure"
+	"microsoft.com/aml-mcp/internal/operations"
+)
+
+// DefaultWaitTimeout is how long wait_for_operation waits when no timeout is given
+const DefaultWaitTimeout = 5 * time.Minute
+
+// OperationTools contains the tools for tracking long-running operations
+type OperationTools struct {
+	clients *azure.ClientCache
+	shared
+}
+
+// NewOperationTools creates a new OperationTools instance backed by a shared client cache
+func NewOperationTools(clients *azure.ClientCache, opts ...Option) *OperationTools {
+	return &OperationTools{clients: clients, shared: newShared(opts)}
+}
+
+// AddToServer registers all operation tools with the MCP server
+func (ot *OperationTools) AddToServer(s Registrar) {
+	ot.addGetOperationStatusTool(s)
+	ot.addWaitForOperationTool(s)
+	ot.addCancelOperationTool(s)
+}
+
+func (ot *OperationTools) addGetOperationStatusTool(s Registrar) {
+	tool := mcp.NewTool("get_operation_status",
+		mcp.WithDescription("Check the current status of a long-running operation started with wait=false"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOutputSchema[Operation](),
+		mcp.WithString("operation_id",
+			mcp.Required(),
+			mcp.Description("Operation ID returned by the tool that started the operation"),
+		),
+	)
+
+	s.AddTool(tool, ot.handleGetOperationStatus)
+}
+
+func (ot *OperationTools) addWaitForOperationTool(s Registrar) {
+	tool := mcp.NewTool("wait_for_operation",
+		mcp.WithDescription("Wait for a long-running operation to finish, up to a timeout"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOutputSchema[Operation](),
+		mcp.WithString("operation_id",
+			mcp.Required(),
+			mcp.Description("Operation ID returned by the tool that started the operation"),
+		),
+		mcp.WithNumber("timeout_seconds",
+			mcp.Description("Maximum time to wait in seconds (default 300)"),
+		),
+	)
+
+	s.AddTool(tool, ot.handleWaitForOperation)
+}
+
+func (ot *OperationTools) addCancelOperationTool(s Registrar) {
+	tool := mcp.NewTool("cancel_operation",
+		mcp.WithDescription("Stop tracking a long-running operation. Azure does not support cancelling these operations, so the change may still complete."),
+		// Only stops tracking the operation; Azure is not changed
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(false),
+		mcp.WithOutputSchema[Operation](),
+		mcp.WithString("operation_id",
+			mcp.Required(),
+			mcp.Description("Operation ID returned by the tool that started the operation"),
+		),
+	)
+
+	s.AddTool(tool, ot.handleCancelOperation)
+}
+
+func (ot *OperationTools) handleGetOperationStatus(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	operationID, err := request.RequireString("operation_id")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	op, ok := ot.operations.Get(operationID)
+	if !ok {
+		return mcp.NewToolResultError(fmt.Sprintf("Operation '%s' not found", operationID)), nil
+	}
+	if op.Status.Terminal() {
+		return operationResult(op), nil
+	}
+
+	poller, err := ot.resume(ctx, op)
+	if err != nil {
+		return azureError(ot.clients, "Failed to resume operation", err), nil
+	}
+
+	if _, err := poller.Poll(ctx); err != nil {
+		return azureError(ot.clients, "Failed to poll operation", err), nil
+	}
+
+	return operationResult(ot.recordOutcome(ctx, op.ID, poller)), nil
+}
+
+func (ot *OperationTools) handleWaitForOperation(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	operationID, err := request.RequireString("operation_id")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	timeout := DefaultWaitTimeout
+	if seconds := request.GetFloat("timeout_seconds", 0); seconds > 0 {
+		timeout = time.Duration(seconds * float64(time.Second))
+	}
+
+	op, ok := ot.operations.Get(operationID)
+	if !ok {
+		return mcp.NewToolResultError(fmt.Sprintf("Operation '%s' not found", operationID)), nil
+	}
+	if op.Status.Terminal() {
+		return operationResult(op), nil
+	}
+
+	poller, err := ot.resume(ctx, op)
+	if err != nil {
+		return azureError(ot.clients, "Failed to resume operation", err), nil
+	}
+
+	waitCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	// Running out of time leaves the operation in progress
+	description := fmt.Sprintf("%s '%s'", op.Kind, op.Target)
+	if err := ot.pollUntilDone(waitCtx, request, description, poller); err != nil {
+		if !errors.Is(err, context.DeadlineExceeded) || ctx.Err() != nil {
+			return azureError(ot.clients, "Failed to wait for operation", err), nil
+		}
+	}
+
+	return operationResult(ot.recordOutcome(ctx, op.ID, poller)), nil
+}
+
+func (ot *OperationTools) handleCancelOperation(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	operationID, err := request.RequireString("operation_id")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	op, ok := ot.operations.Update(operationID, func(op *operations.Operation) {
+		op.Status = operations.StatusCanceled
+		op.Error = "tracking cancelled; the Azure operation may still complete"
+	})
+	if !ok {
+		return mcp.NewToolResultError(fmt.Sprintf("Operation '%s' not found", operationID)), nil
+	}
+
+	return operationResult(op), nil
+}
+
+// resume rebuilds the poller for a tracked operation using its subscription's clients
+func (ot *OperationTools) resume(ctx context.Context, op operations.Operation) (lro, error) {
+	clients, err := ot.clients.Get(ctx, op.SubscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	return resumeOperation(ctx, clients, op)
+}
+
+func operationResult(op operations.Operation) *mcp.CallToolResult {
+	text := fmt.Sprintf("Operation %s (%s '%s'): %s", op.ID, op.Kind, op.Target, op.Status)
+	if op.Error != "" {
+		text += fmt.Sprintf("\nError: %s", op.Error)
+	}
+	return mcp.NewToolResultStructured(newOperation(op), text)
+}
@@ -0,0 +1,165 @@
+package tools_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/server"
+	"microsoft.com/aml-mcp/internal/fakearm"
+	"microsoft.com/aml-mcp/internal/operations"
+	"microsoft.com/aml-mcp/internal/tools"
+)
+
+// newOperationServer registers the compute, workspace and operation tools
+// against a fake ARM server with a shared operation registry
+func newOperationServer(t *testing.T) (*fakearm.Server, *server.MCPServer) {
+	t.Helper()
+
+	fake := newFakeARM(t)
+	clients := fake.ClientCache()
+	opts := []tools.Option{
+		tools.WithOperations(operations.NewRegistry()),
+		tools.WithPollInterval(10 * time.Millisecond),
+	}
+
+	s := server.NewMCPServer("test", "1.0.0")
+	tools.NewWorkspaceTools(clients, opts...).AddToServer(s)
+	tools.NewComputeTools(clients, opts...).AddToServer(s)
+	tools.NewOperationTools(clients, opts...).AddToServer(s)
+	return fake, s
+}
+
+func TestOperationToolsRegistration(t *testing.T) {
+	s := server.NewMCPServer("test", "1.0.0")
+	tools.NewOperationTools(newFakeARM(t).ClientCache()).AddToServer(s)
+
+	names := map[string]bool{}
+	for _, tool := range listTools(t, s) {
+		names[tool["name"].(string)] = true
+	}
+	for _, want := range []string{"get_operation_status", "wait_for_operation", "cancel_operation"} {
+		if !names[want] {
+			t.Errorf("tool %s is not registered", want)
+		}
+	}
+}
+
+func TestStartComputeWithoutWaiting(t *testing.T) {
+	fake, s := newOperationServer(t)
+	fake.SetPendingPolls(2)
+
+	started := structuredAs[tools.ComputeOperation](t,
+		callTool(t, s, "start_compute", workspaceArgs(map[string]any{"compute_name": testCompute, "wait": false})))
+	if started.OperationID == "" {
+		t.Fatal("start_compute with wait=false returned no operation ID")
+	}
+	if started.Status != string(operations.StatusInProgress) {
+		t.Errorf("status = %q, want %q", started.Status, operations.StatusInProgress)
+	}
+	if got := fake.ComputeState(testSubscriptionID, testResourceGroup, testWorkspace, testCompute); got != "Stopped" {
+		t.Errorf("compute state = %q before the operation finished, want Stopped", got)
+	}
+
+	args := map[string]any{"operation_id": started.OperationID}
+
+	status := structuredAs[tools.Operation](t, callTool(t, s, "get_operation_status", args))
+	if status.Status != string(operations.StatusInProgress) {
+		t.Errorf("get_operation_status status = %q, want %q", status.Status, operations.StatusInProgress)
+	}
+	if status.Kind != operations.KindStartCompute || status.Target != testCompute {
+		t.Errorf("get_operation_status = %+v, want start of %s", status, testCompute)
+	}
+
+	done := structuredAs[tools.Operation](t, callTool(t, s, "wait_for_operation", args))
+	if done.Status != string(operations.StatusSucceeded) {
+		t.Fatalf("wait_for_operation status = %q, want %q (error: %s)", done.Status, operations.StatusSucceeded, done.Error)
+	}
+	if got := fake.ComputeState(testSubscriptionID, testResourceGroup, testWorkspace, testCompute); got != "Running" {
+		t.Errorf("compute state = %q, want Running", got)
+	}
+
+	status = structuredAs[tools.Operation](t, callTool(t, s, "get_operation_status", args))
+	if status.Status != string(operations.StatusSucceeded) {
+		t.Errorf("get_operation_status after completion = %q, want %q", status.Status, operations.StatusSucceeded)
+	}
+}
+
+func TestCreateWorkspaceWithoutWaiting(t *testing.T) {
+	fake, s := newOperationServer(t)
+	fake.SetPendingPolls(1)
+
+	result := callTool(t, s, "create_workspace", map[string]any{
+		"subscription_id":     testSubscriptionID,
+		"resource_group_name": testResourceGroup,
+		"workspace_name":      "async-ws",
+		"location":            testLocation,
+		"wait":                false,
+	})
+	created := structuredAs[tools.WorkspaceCreation](t, result)
+	if created.OperationID == "" || created.Workspace != nil {
+		t.Fatalf("create_workspace with wait=false = %+v, want an operation ID and no workspace", created)
+	}
+	if !strings.Contains(resultText(result), created.OperationID) {
+		t.Errorf("result %q does not mention operation ID %s", resultText(result), created.OperationID)
+	}
+
+	done := structuredAs[tools.Operation](t, callTool(t, s, "wait_for_operation", map[string]any{"operation_id": created.OperationID}))
+	if done.Status != string(operations.StatusSucceeded) {
+		t.Fatalf("wait_for_operation status = %q, want %q (error: %s)", done.Status, operations.StatusSucceeded, done.Error)
+	}
+	if _, ok := fake.Workspace(testSubscriptionID, testResourceGroup, "async-ws"); !ok {
+		t.Error("workspace was not created")
+	}
+}
+
+func TestWaitForOperationTimeout(t *testing.T) {
+	fake, s := newOperationServer(t)
+	fake.SetPendingPolls(1000)
+
+	started := structuredAs[tools.ComputeOperation](t,
+		callTool(t, s, "stop_compute", workspaceArgs(map[string]any{"compute_name": testCompute, "wait": false})))
+
+	result := callTool(t, s, "wait_for_operation", map[string]any{"operation_id": started.OperationID, "timeout_seconds": 0.05})
+	if result.IsError {
+		t.Fatalf("wait_for_operation returned an error: %s", resultText(result))
+	}
+	if got := structuredAs[tools.Operation](t, result).Status; got != string(operations.StatusInProgress) {
+		t.Errorf("status after timeout = %q, want %q", got, operations.StatusInProgress)
+	}
+}
+
+func TestCancelOperation(t *testing.T) {
+	fake, s := newOperationServer(t)
+	fake.SetPendingPolls(1000)
+
+	started := structuredAs[tools.ComputeOperation](t,
+		callTool(t, s, "start_compute", workspaceArgs(map[string]any{"compute_name": testCompute, "wait": false})))
+	args := map[string]any{"operation_id": started.OperationID}
+
+	canceled := structuredAs[tools.Operation](t, callTool(t, s, "cancel_operation", args))
+	if canceled.Status != string(operations.StatusCanceled) {
+		t.Errorf("cancel_operation status = %q, want %q", canceled.Status, operations.StatusCanceled)
+	}
+
+	status := structuredAs[tools.Operation](t, callTool(t, s, "get_operation_status", args))
+	if status.Status != string(operations.StatusCanceled) {
+		t.Errorf("get_operation_status after cancel = %q, want %q", status.Status, operations.StatusCanceled)
+	}
+}
+
+func TestUnknownOperation(t *testing.T) {
+	_, s := newOperationServer(t)
+
+	for _, name := range []string{"get_operation_status", "wait_for_operation", "cancel_operation"} {
+		t.Run(name, func(t *testing.T) {
+			result := callTool(t, s, name, map[string]any{"operation_id": "op-missing"})
+			if !result.IsError {
+				t.Fatalf("%s with unknown operation did not return an error", name)
+			}
+			if !strings.Contains(resultText(result), "not found") {
+				t.Errorf("result %q does not report a missing operation", resultText(result))
+			}
+		})
+	}
+}
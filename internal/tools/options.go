@@ -0,0 +1,85 @@
+package tools
+
+import (
+	"time"
+
+	"microsoft.com/aml-mcp/internal/confirm"
+	"microsoft.com/aml-mcp/internal/operations"
+	"microsoft.com/aml-mcp/internal/session"
+)
+
+// DefaultPollInterval is how often operations are polled when Azure sends no Retry-After
+const DefaultPollInterval = 10 * time.Second
+
+// Option configures the services shared between tool sets
+type Option func(*shared)
+
+// shared holds the long-lived services a tool set may use
+type shared struct {
+	operations   *operations.Registry
+	pollInterval time.Duration
+	sessions     *session.Store
+	defaults     session.Workspace
+	// subscriptions is the configured list get_inventory covers by default
+	subscriptions []string
+	// confirmations is nil when mutating tools act without confirmation
+	confirmations *confirm.Store
+}
+
+func newShared(opts []Option) shared {
+	s := shared{pollInterval: DefaultPollInterval}
+	for _, opt := range opts {
+		opt(&s)
+	}
+	if s.operations == nil {
+		s.operations = operations.NewRegistry()
+	}
+	if s.sessions == nil {
+		s.sessions = session.NewStore()
+	}
+	return s
+}
+
+// WithOperations sets the registry used to track long-running operations
+func WithOperations(registry *operations.Registry) Option {
+	return func(s *shared) {
+		s.operations = registry
+	}
+}
+
+// WithPollInterval sets how often long-running operations are polled
+func WithPollInterval(interval time.Duration) Option {
+	return func(s *shared) {
+		if interval > 0 {
+			s.pollInterval = interval
+		}
+	}
+}
+
+// WithSessions sets the store holding each client session's active workspace
+func WithSessions(store *session.Store) Option {
+	return func(s *shared) {
+		s.sessions = store
+	}
+}
+
+// WithDefaults sets the workspace used when neither the call nor the session names one
+func WithDefaults(defaults session.Workspace) Option {
+	return func(s *shared) {
+		s.defaults = defaults
+	}
+}
+
+// WithConfirmations makes mutating tools ask for confirmation, tracking confirm tokens in store
+func WithConfirmations(store *confirm.Store) Option {
+	return func(s *shared) {
+		s.confirmations = store
+	}
+}
+
+// WithSubscriptions sets the subscriptions get_inventory covers when the call names none
+func WithSubscriptions(subscriptionIDs []string) Option {
+	return func(s *shared) {
+		s.subscriptions = subscriptionIDs
+	}
+}
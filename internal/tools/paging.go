@@ -0,0 +1,275 @@
+package tools
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Page sizes of list tools
+const (
+	DefaultPageSize = 50
+	MaxPageSize     = 500
+)
+
+// Arguments of list tools that filter, sort and page their results
+const (
+	argNameContains = "name_contains"
+	argLocation     = "location"
+	argState        = "state"
+	argType         = "type"
+	argSortBy       = "sort_by"
+	argSortOrder    = "sort_order"
+	argPageSize     = "page_size"
+	argCursor       = "cursor"
+)
+
+const (
+	sortAscending  = "asc"
+	sortDescending = "desc"
+)
+
+var errCursorMismatch = errors.New("cursor was returned for a call with different filters, sort or scope; repeat that call's arguments, or leave out cursor to start over")
+
+// addListOptions adds the sort and paging arguments to a list tool
+func addListOptions(tool *mcp.Tool, sortKeys ...string) {
+	for _, opt := range []mcp.ToolOption{
+		mcp.WithString(argSortBy,
+			mcp.Description("Sort results by this field. Sorting lists every page from Azure before returning the first"),
+			mcp.Enum(sortKeys...),
+		),
+		mcp.WithString(argSortOrder,
+			mcp.Description("Sort order (default asc)"),
+			mcp.Enum(sortAscending, sortDescending),
+		),
+		mcp.WithNumber(argPageSize,
+			mcp.Description(fmt.Sprintf("Maximum number of results to return (default %d)", DefaultPageSize)),
+			mcp.Min(1),
+			mcp.Max(MaxPageSize),
+		),
+		mcp.WithString(argCursor,
+			mcp.Description("nextCursor returned by a previous call with the same arguments, to get the next page"),
+		),
+	} {
+		opt(tool)
+	}
+}
+
+// listQuery is how a list tool call filters, sorts and pages its results
+type listQuery struct {
+	sortBy     string
+	sortOrder  string
+	descending bool
+	pageSize   int
+	cursor     listCursor
+	// fingerprint identifies the call's scope, filters and sort
+	fingerprint string
+}
+
+// listCursor is where the next page of a list starts
+type listCursor struct {
+	Fingerprint string `json:"f"`
+	// Skip is the $skip token of the ARM page to continue from
+	Skip string `json:"s,omitempty"`
+	// Offset is how many items of that page or sorted list were already returned
+	Offset int `json:"o,omitempty"`
+}
+
+// newListQuery reads the sort and paging arguments of a list tool call in scope
+func newListQuery(request mcp.CallToolRequest, scope ...string) (listQuery, error) {
+	q := listQuery{
+		sortBy:    request.GetString(argSortBy, ""),
+		sortOrder: request.GetString(argSortOrder, sortAscending),
+		pageSize:  request.GetInt(argPageSize, DefaultPageSize),
+	}
+	q.descending = strings.EqualFold(q.sortOrder, sortDescending)
+	if q.pageSize < 1 || q.pageSize > MaxPageSize {
+		return listQuery{}, fmt.Errorf("page_size must be between 1 and %d", MaxPageSize)
+	}
+
+	arguments := maps.Clone(request.GetArguments())
+	for _, name := range []string{argPageSize, argCursor, argRefresh} {
+		delete(arguments, name)
+	}
+	data, _ := json.Marshal(arguments)
+	sum := sha256.Sum256([]byte(request.Params.Name + "\n" + strings.Join(scope, "/") + "\n" + string(data)))
+	q.fingerprint = hex.EncodeToString(sum[:8])
+
+	if encoded := request.GetString(argCursor, ""); encoded != "" {
+		data, err := base64.RawURLEncoding.DecodeString(encoded)
+		if err == nil {
+			err = json.Unmarshal(data, &q.cursor)
+		}
+		if err != nil || q.cursor.Offset < 0 {
+			return listQuery{}, errors.New("invalid cursor")
+		}
+		if q.cursor.Fingerprint != q.fingerprint {
+			return listQuery{}, errCursorMismatch
+		}
+	}
+	return q, nil
+}
+
+func (q listQuery) nextCursor(skip string, offset int) string {
+	data, _ := json.Marshal(listCursor{Fingerprint: q.fingerprint, Skip: skip, Offset: offset})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// pageFetcher fetches the ARM page that starts at skip
+type pageFetcher[T any] func(ctx context.Context, skip string) (items []T, nextLink string, err error)
+
+// listPage returns the page of matching items q asks for and the cursor of the next page
+func listPage[T any](ctx context.Context, q listQuery, fetch pageFetcher[T], match func(T) bool, compares map[string]func(a, b T) int) ([]T, string, error) {
+	// mcp-go does not enforce the enums of the sort arguments
+	if !strings.EqualFold(q.sortOrder, sortAscending) && !q.descending {
+		return nil, "", fmt.Errorf("%w: sort_order must be %q or %q, got %q", errInvalidArgument, sortAscending, sortDescending, q.sortOrder)
+	}
+	if q.sortBy != "" {
+		compare, ok := compares[q.sortBy]
+		if !ok {
+			return nil, "", fmt.Errorf("%w: sort_by must be one of %s, got %q",
+				errInvalidArgument, strings.Join(slices.Sorted(maps.Keys(compares)), ", "), q.sortBy)
+		}
+		return sortedPage(ctx, q, fetch, match, compare)
+	}
+
+	items := []T{}
+	skip, offset := q.cursor.Skip, q.cursor.Offset
+	for {
+		page, nextLink, err := fetch(ctx, skip)
+		if err != nil {
+			return nil, "", err
+		}
+		var next string
+		if nextLink != "" {
+			if next, err = skipToken(nextLink); err != nil {
+				return nil, "", err
+			}
+		}
+
+		for i := offset; i < len(page); i++ {
+			if !match(page[i]) {
+				continue
+			}
+			items = append(items, page[i])
+			if len(items) < q.pageSize {
+				continue
+			}
+			switch {
+			case i+1 < len(page):
+				return items, q.nextCursor(skip, i+1), nil
+			case nextLink != "":
+				return items, q.nextCursor(next, 0), nil
+			default:
+				return items, "", nil
+			}
+		}
+
+		if nextLink == "" {
+			return items, "", nil
+		}
+		skip, offset = next, 0
+	}
+}
+
+// sortedPage lists and sorts every matching item and returns the page q asks for
+func sortedPage[T any](ctx context.Context, q listQuery, fetch pageFetcher[T], match func(T) bool, compare func(a, b T) int) ([]T, string, error) {
+	all := []T{}
+	skip := ""
+	for {
+		page, nextLink, err := fetch(ctx, skip)
+		if err != nil {
+			return nil, "", err
+		}
+		for _, item := range page {
+			if match(item) {
+				all = append(all, item)
+			}
+		}
+		if nextLink == "" {
+			break
+		}
+		if skip, err = skipToken(nextLink); err != nil {
+			return nil, "", err
+		}
+	}
+
+	if q.descending {
+		slices.SortStableFunc(all, func(a, b T) int { return compare(b, a) })
+	} else {
+		slices.SortStableFunc(all, compare)
+	}
+
+	start := min(q.cursor.Offset, len(all))
+	end := min(start+q.pageSize, len(all))
+	if end < len(all) {
+		return all[start:end], q.nextCursor("", end), nil
+	}
+	return all[start:end], "", nil
+}
+
+// skipToken extracts the $skip continuation token from an ARM next link
+func skipToken(nextLink string) (string, error) {
+	u, err := url.Parse(nextLink)
+	if err != nil {
+		return "", fmt.Errorf("invalid next link from Azure: %v", err)
+	}
+	token := u.Query().Get("$skip")
+	if token == "" {
+		return "", fmt.Errorf("next link from Azure has no $skip token: %s", nextLink)
+	}
+	return token, nil
+}
+
+// matchFilter reports whether value passes a filter argument, ignoring case
+func matchFilter(filter, value string) bool {
+	return filter == "" || strings.EqualFold(filter, value)
+}
+
+// matchLocation is matchFilter for Azure regions
+func matchLocation(filter, location string) bool {
+	normalize := func(s string) string { return strings.ToLower(strings.ReplaceAll(s, " ", "")) }
+	return filter == "" || normalize(filter) == normalize(location)
+}
+
+// matchNameContains reports whether name contains filter, ignoring case
+func matchNameContains(filter, name string) bool {
+	return strings.Contains(strings.ToLower(name), strings.ToLower(filter))
+}
+
+// compareFold orders strings ignoring case
+func compareFold(a, b string) int {
+	return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+}
+
+// compareTimes orders times, with unknown times first
+func compareTimes(a, b *time.Time) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return -1
+	case b == nil:
+		return 1
+	}
+	return a.Compare(*b)
+}
+
+// moreResultsText is appended to the text output of a list with more pages
+func moreResultsText(cursor string) string {
+	if cursor == "" {
+		return ""
+	}
+	return fmt.Sprintf("\nMore results are available. Call again with the same arguments and cursor %q.", cursor)
+}
@@ -0,0 +1,169 @@
+package tools_test
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/machinelearning/armmachinelearning"
+	"github.com/mark3labs/mcp-go/server"
+	"microsoft.com/aml-mcp/internal/fakearm"
+	"microsoft.com/aml-mcp/internal/tools"
+)
+
+// newPagingServer seeds four clusters next to the test compute instance and
+// makes the fake ARM return two resources per page
+func newPagingServer(t *testing.T) (*fakearm.Server, *server.MCPServer) {
+	t.Helper()
+
+	fake := newFakeARM(t)
+	fake.SetPageSize(2)
+	for i, location := range []string{"eastus", "westus", "eastus", "westus"} {
+		fake.AddCompute(testSubscriptionID, testResourceGroup, testWorkspace, armmachinelearning.ComputeResource{
+			Name:     to.Ptr(fmt.Sprintf("cluster-%d", i)),
+			Location: to.Ptr(location),
+			Properties: &armmachinelearning.AmlCompute{
+				ComputeType:       to.Ptr(armmachinelearning.ComputeTypeAmlCompute),
+				ProvisioningState: to.Ptr(armmachinelearning.ProvisioningStateSucceeded),
+			},
+		})
+	}
+
+	s := server.NewMCPServer("test", "1.0.0")
+	tools.NewComputeTools(fake.ClientCache()).AddToServer(s)
+	return fake, s
+}
+
+// listAllCompute follows nextCursor until the last page and returns the
+// names in the order they were returned
+func listAllCompute(t *testing.T, s *server.MCPServer, args map[string]any) (names []string, pages int) {
+	t.Helper()
+
+	cursor := ""
+	for {
+		pageArgs := workspaceArgs(args)
+		if cursor != "" {
+			pageArgs["cursor"] = cursor
+		}
+		result := callTool(t, s, "list_compute", pageArgs)
+		if result.IsError {
+			t.Fatalf("list_compute failed: %s", resultText(result))
+		}
+		list := structuredAs[tools.ComputeList](t, result)
+		for _, c := range list.Computes {
+			names = append(names, c.Name)
+		}
+		pages++
+		if list.NextCursor == "" {
+			return names, pages
+		}
+		if !strings.Contains(resultText(result), list.NextCursor) {
+			t.Errorf("text output does not mention the next cursor: %s", resultText(result))
+		}
+		cursor = list.NextCursor
+		if pages > 10 {
+			t.Fatal("too many pages")
+		}
+	}
+}
+
+func TestListPaging(t *testing.T) {
+	_, s := newPagingServer(t)
+
+	names, pages := listAllCompute(t, s, map[string]any{"page_size": 2})
+	if pages != 3 {
+		t.Errorf("pages = %d, want 3", pages)
+	}
+	want := []string{"cluster-0", "cluster-1", "cluster-2", "cluster-3", "test-ci"}
+	if !slices.Equal(names, want) {
+		t.Errorf("names = %v, want %v", names, want)
+	}
+
+	// A page that ends inside an ARM page continues from the same ARM page
+	names, _ = listAllCompute(t, s, map[string]any{"page_size": 3})
+	if !slices.Equal(names, want) {
+		t.Errorf("names with page_size 3 = %v, want %v", names, want)
+	}
+}
+
+func TestListFilters(t *testing.T) {
+	_, s := newPagingServer(t)
+
+	tests := []struct {
+		name string
+		args map[string]any
+		want []string
+	}{
+		{"type", map[string]any{"type": "amlcompute"}, []string{"cluster-0", "cluster-1", "cluster-2", "cluster-3"}},
+		{"location", map[string]any{"location": "West US"}, []string{"cluster-1", "cluster-3"}},
+		{"name contains", map[string]any{"name_contains": "CI"}, []string{"test-ci"}},
+		{"combined", map[string]any{"location": "eastus", "type": "AmlCompute", "page_size": 1}, []string{"cluster-0", "cluster-2"}},
+		{"sorted descending", map[string]any{"sort_by": "name", "sort_order": "desc", "page_size": 2}, []string{"test-ci", "cluster-3", "cluster-2", "cluster-1", "cluster-0"}},
+		{"sorted by type", map[string]any{"sort_by": "type", "name_contains": "1"}, []string{"cluster-1"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			names, _ := listAllCompute(t, s, tt.args)
+			if !slices.Equal(names, tt.want) {
+				t.Errorf("names = %v, want %v", names, tt.want)
+			}
+		})
+	}
+}
+
+func TestListCursorErrors(t *testing.T) {
+	_, s := newPagingServer(t)
+
+	result := callTool(t, s, "list_compute", workspaceArgs(map[string]any{"page_size": 1}))
+	cursor := structuredAs[tools.ComputeList](t, result).NextCursor
+	if cursor == "" {
+		t.Fatal("first page has no next cursor")
+	}
+
+	tests := []struct {
+		name     string
+		args     map[string]any
+		contains string
+	}{
+		{"different filters", map[string]any{"page_size": 1, "cursor": cursor, "location": "eastus"}, "different filters"},
+		{"invalid cursor", map[string]any{"cursor": "not-a-cursor"}, "invalid cursor"},
+		{"page size too large", map[string]any{"page_size": tools.MaxPageSize + 1}, "page_size"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := callTool(t, s, "list_compute", workspaceArgs(tt.args))
+			if !result.IsError {
+				t.Fatalf("expected an error, got %s", resultText(result))
+			}
+			if text := resultText(result); !strings.Contains(text, tt.contains) {
+				t.Errorf("error %q does not contain %q", text, tt.contains)
+			}
+		})
+	}
+
+	// Sort arguments outside their enums are rejected rather than ignored
+	badSorts := []struct {
+		args     map[string]any
+		contains string
+	}{
+		{map[string]any{"sort_by": "nmae"}, "createdOn, location, name, state, type"},
+		{map[string]any{"sort_by": "name", "sort_order": "up"}, `"asc" or "desc"`},
+	}
+	for _, tt := range badSorts {
+		result := callTool(t, s, "list_compute", workspaceArgs(tt.args))
+		if got := toolError(t, result); got.Code != tools.ErrorInvalidArgument {
+			t.Errorf("%v: code = %s, want %s", tt.args, got.Code, tools.ErrorInvalidArgument)
+		}
+		if text := resultText(result); !strings.Contains(text, tt.contains) {
+			t.Errorf("%v: error %q does not contain %q", tt.args, text, tt.contains)
+		}
+	}
+
+	// The same arguments with a different page_size still accept the cursor
+	result = callTool(t, s, "list_compute", workspaceArgs(map[string]any{"page_size": 2, "cursor": cursor}))
+	if result.IsError {
+		t.Fatalf("cursor rejected after changing page_size: %s", resultText(result))
+	}
+}
@@ -0,0 +1,152 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/authorization/armauthorization/v2"
+	"github.com/mark3labs/mcp-go/mcp"
+	"microsoft.com/aml-mcp/internal/azure"
+	"microsoft.com/aml-mcp/internal/logging"
+)
+
+// providerNamespace is the resource provider of Azure ML resources
+const providerNamespace = "Microsoft.MachineLearningServices"
+
+// toolActions lists the mutating tools and the RBAC action each needs, in report order
+var toolActions = []struct {
+	tool   string
+	action string
+}{
+	{"create_workspace", "Microsoft.MachineLearningServices/workspaces/write"},
+	{"start_compute", "Microsoft.MachineLearningServices/workspaces/computes/start/action"},
+	{"stop_compute", "Microsoft.MachineLearningServices/workspaces/computes/stop/action"},
+	{"grant_workspace_role", "Microsoft.Authorization/roleAssignments/write"},
+	{"revoke_workspace_role", "Microsoft.Authorization/roleAssignments/delete"},
+}
+
+// toolAction returns the RBAC action a mutating tool needs, or "" for other tools
+func toolAction(tool string) string {
+	for _, ta := range toolActions {
+		if ta.tool == tool {
+			return ta.action
+		}
+	}
+	return ""
+}
+
+// permissionScope is a resource group, workspace or compute resource whose permissions are checked
+type permissionScope struct {
+	ResourceGroup string
+	Workspace     string
+	Compute       string
+}
+
+// describe names the scope in messages
+func (p permissionScope) describe() string {
+	switch {
+	case p.Compute != "":
+		return fmt.Sprintf("compute resource '%s' in workspace '%s'", p.Compute, p.Workspace)
+	case p.Workspace != "":
+		return fmt.Sprintf("workspace '%s' in resource group '%s'", p.Workspace, p.ResourceGroup)
+	default:
+		return fmt.Sprintf("resource group '%s'", p.ResourceGroup)
+	}
+}
+
+// resourceID returns the ARM ID of the scope
+func (p permissionScope) resourceID(subscriptionID string) string {
+	id := fmt.Sprintf("/subscriptions/%s/resourceGroups/%s", subscriptionID, p.ResourceGroup)
+	if p.Workspace != "" {
+		id += fmt.Sprintf("/providers/%s/workspaces/%s", providerNamespace, p.Workspace)
+	}
+	if p.Compute != "" {
+		id += "/computes/" + p.Compute
+	}
+	return id
+}
+
+// listPermissions returns the caller's effective permissions on scope
+func listPermissions(ctx context.Context, clients *azure.ClientSet, scope permissionScope) ([]*armauthorization.Permission, error) {
+	var permissions []*armauthorization.Permission
+	if scope.Workspace == "" {
+		pager := clients.PermissionsClient.NewListForResourceGroupPager(scope.ResourceGroup, nil)
+		for pager.More() {
+			page, err := pager.NextPage(ctx)
+			if err != nil {
+				return nil, err
+			}
+			permissions = append(permissions, page.Value...)
+		}
+		return permissions, nil
+	}
+
+	parent, resourceType, name := "", "workspaces", scope.Workspace
+	if scope.Compute != "" {
+		parent, resourceType, name = "workspaces/"+scope.Workspace, "computes", scope.Compute
+	}
+	pager := clients.PermissionsClient.NewListForResourcePager(scope.ResourceGroup, providerNamespace, parent, resourceType, name, nil)
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		permissions = append(permissions, page.Value...)
+	}
+	return permissions, nil
+}
+
+// allowed reports whether a single permission in permissions grants action without excluding it
+func allowed(permissions []*armauthorization.Permission, action string) bool {
+	for _, p := range permissions {
+		if p != nil && matchesAny(p.Actions, action) && !matchesAny(p.NotActions, action) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesAny(patterns []*string, action string) bool {
+	for _, pattern := range patterns {
+		if pattern != nil && actionMatches(*pattern, action) {
+			return true
+		}
+	}
+	return false
+}
+
+// actionMatches reports whether action matches an RBAC action pattern, ignoring case
+func actionMatches(pattern, action string) bool {
+	if !strings.Contains(pattern, "*") {
+		return strings.EqualFold(pattern, action)
+	}
+	expr := strings.ReplaceAll(regexp.QuoteMeta(pattern), `\*`, ".*")
+	matched, err := regexp.MatchString("(?i)^"+expr+"$", action)
+	return err == nil && matched
+}
+
+// preflight returns a PermissionDenied result if the caller may not run the tool on scope, or nil
+func preflight(ctx context.Context, request mcp.CallToolRequest, cache *azure.ClientCache, clients *azure.ClientSet, scope permissionScope) *mcp.CallToolResult {
+	tool := request.Params.Name
+	action := toolAction(tool)
+	if action == "" || request.GetBool(argDryRun, false) {
+		return nil
+	}
+	permissions, err := listPermissions(ctx, clients, scope)
+	if err != nil {
+		// Azure still enforces the role when the call goes ahead
+		cache.InvalidateOnAuthError(err)
+		logging.FromContext(ctx).Debug("Permission check failed", "tool", tool, "error", err)
+		return nil
+	}
+	if allowed(permissions, action) {
+		return nil
+	}
+	return toolErrorResult(fmt.Sprintf("Not allowed to call %s", tool), ToolError{
+		Code:    ErrorPermissionDenied,
+		Message: fmt.Sprintf("the signed-in identity has no role that allows %s on %s", action, scope.describe()),
+		Hint:    "Ask an owner of the resource for a role assignment that includes the action, or use a resource you have access to. check_permissions lists what is allowed.",
+	})
+}
@@ -0,0 +1,174 @@
+package tools_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/authorization/armauthorization/v2"
+	"github.com/mark3labs/mcp-go/server"
+	"microsoft.com/aml-mcp/internal/confirm"
+	"microsoft.com/aml-mcp/internal/fakearm"
+	"microsoft.com/aml-mcp/internal/operations"
+	"microsoft.com/aml-mcp/internal/tools"
+)
+
+// newPermissionServer registers the auth tools alongside the mutating tools
+// check_permissions reports on
+func newPermissionServer(fake *fakearm.Server) *server.MCPServer {
+	clients := fake.ClientCache()
+	opts := []tools.Option{tools.WithPollInterval(10 * time.Millisecond)}
+
+	s := server.NewMCPServer("test", "1.0.0")
+	tools.NewWorkspaceTools(clients, opts...).AddToServer(s)
+	tools.NewComputeTools(clients, opts...).AddToServer(s)
+	tools.NewAuthTools(clients, opts...).AddToServer(s)
+	return s
+}
+
+// computeOperatorPermissions allows reading the workspace and starting, but
+// not stopping, its compute
+func computeOperatorPermissions() *armauthorization.Permission {
+	return &armauthorization.Permission{
+		Actions: []*string{
+			to.Ptr("Microsoft.MachineLearningServices/workspaces/*/read"),
+			to.Ptr("microsoft.machinelearningservices/workspaces/computes/*"),
+		},
+		NotActions: []*string{to.Ptr("Microsoft.MachineLearningServices/workspaces/computes/stop/action")},
+	}
+}
+
+func TestCheckPermissions(t *testing.T) {
+	fake := newFakeARM(t)
+	fake.SetPermissions(fakearm.WorkspaceID(testSubscriptionID, testResourceGroup, testWorkspace), computeOperatorPermissions())
+	s := newPermissionServer(fake)
+
+	tests := []struct {
+		name        string
+		args        map[string]any
+		wantScope   string
+		wantPath    string
+		wantAllowed map[string]bool
+	}{
+		{
+			name:        "compute",
+			args:        workspaceArgs(map[string]any{"compute_name": testCompute}),
+			wantScope:   fakearm.ComputeID(testSubscriptionID, testResourceGroup, testWorkspace, testCompute),
+			wantPath:    "/workspaces/" + testWorkspace + "/computes/" + testCompute + "/providers/Microsoft.Authorization/permissions",
+			wantAllowed: map[string]bool{"create_workspace": false, "start_compute": true, "stop_compute": false},
+		},
+		{
+			name:        "resource ID",
+			args:        map[string]any{"resource_id": fakearm.WorkspaceID(testSubscriptionID, testResourceGroup, testWorkspace)},
+			wantScope:   fakearm.WorkspaceID(testSubscriptionID, testResourceGroup, testWorkspace),
+			wantPath:    "/workspaces/" + testWorkspace + "/providers/Microsoft.Authorization/permissions",
+			wantAllowed: map[string]bool{"create_workspace": false, "start_compute": true, "stop_compute": false},
+		},
+		{
+			// Nothing is set on the resource group, which the fake treats as Owner
+			name:        "resource group",
+			args:        map[string]any{"subscription_id": testSubscriptionID, "resource_group_name": testResourceGroup},
+			wantScope:   "/subscriptions/" + testSubscriptionID + "/resourceGroups/" + testResourceGroup,
+			wantPath:    "/resourcegroups/" + testResourceGroup + "/providers/Microsoft.Authorization/permissions",
+			wantAllowed: map[string]bool{"create_workspace": true, "start_compute": true, "stop_compute": true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := callTool(t, s, "check_permissions", tt.args)
+			if result.IsError {
+				t.Fatalf("check_permissions failed: %s", resultText(result))
+			}
+			check := structuredAs[tools.PermissionCheck](t, result)
+			if check.Scope != tt.wantScope {
+				t.Errorf("scope = %q, want %q", check.Scope, tt.wantScope)
+			}
+			if len(check.Tools) != len(tt.wantAllowed) {
+				t.Fatalf("tools = %+v, want %d", check.Tools, len(tt.wantAllowed))
+			}
+			for _, permission := range check.Tools {
+				if want, ok := tt.wantAllowed[permission.Tool]; !ok || permission.Allowed != want {
+					t.Errorf("%s allowed = %v, want %v", permission.Tool, permission.Allowed, want)
+				}
+			}
+
+			requests := fake.Requests()
+			if path := requests[len(requests)-1].Path; !strings.HasSuffix(path, tt.wantPath) {
+				t.Errorf("request path = %q, want suffix %q", path, tt.wantPath)
+			}
+		})
+	}
+}
+
+func TestPreflightDenied(t *testing.T) {
+	fake := newFakeARM(t)
+	fake.SetPermissions(fakearm.WorkspaceID(testSubscriptionID, testResourceGroup, testWorkspace), computeOperatorPermissions())
+	s := newPermissionServer(fake)
+
+	got := toolError(t, callTool(t, s, "stop_compute", workspaceArgs(map[string]any{"compute_name": testCompute})))
+	if got.Code != tools.ErrorPermissionDenied || !strings.Contains(got.Message, "computes/stop/action") {
+		t.Errorf("error = %+v, want PermissionDenied naming the stop action", got)
+	}
+	for _, request := range fake.Requests() {
+		if request.Method == http.MethodPost {
+			t.Errorf("stop_compute sent %s %s despite the failed permission check", request.Method, request.Path)
+		}
+	}
+
+	result := callTool(t, s, "start_compute", workspaceArgs(map[string]any{"compute_name": testCompute}))
+	if result.IsError {
+		t.Fatalf("start_compute failed: %s", resultText(result))
+	}
+	if state := fake.ComputeState(testSubscriptionID, testResourceGroup, testWorkspace, testCompute); state != "Running" {
+		t.Errorf("compute state = %q, want Running", state)
+	}
+}
+
+func TestPreflightKeepsConfirmToken(t *testing.T) {
+	fake := newFakeARM(t)
+	workspaceID := fakearm.WorkspaceID(testSubscriptionID, testResourceGroup, testWorkspace)
+	fake.SetPermissions(workspaceID, computeOperatorPermissions())
+	opts := []tools.Option{
+		tools.WithConfirmations(confirm.NewStore(confirm.DefaultTTL)),
+		tools.WithPollInterval(10 * time.Millisecond),
+	}
+	s := server.NewMCPServer("test", "1.0.0")
+	tools.NewComputeTools(fake.ClientCache(), opts...).AddToServer(s)
+
+	args := workspaceArgs(map[string]any{"compute_name": testCompute, "dry_run": true})
+	planned := structuredAs[tools.ComputeOperation](t, callTool(t, s, "stop_compute", args))
+	if planned.Plan == nil || planned.Plan.ConfirmToken == "" {
+		t.Fatalf("stop_compute dry run = %+v, want a confirm token", planned)
+	}
+	delete(args, "dry_run")
+	args["confirm_token"] = planned.Plan.ConfirmToken
+
+	// A denied call does not spend the token
+	if got := toolError(t, callTool(t, s, "stop_compute", args)); got.Code != tools.ErrorPermissionDenied {
+		t.Fatalf("error = %+v, want PermissionDenied", got)
+	}
+	fake.SetPermissions(workspaceID, &armauthorization.Permission{Actions: []*string{to.Ptr("*")}})
+	stopped := structuredAs[tools.ComputeOperation](t, callTool(t, s, "stop_compute", args))
+	if stopped.Status != string(operations.StatusSucceeded) {
+		t.Errorf("stop_compute with the token after the role was granted = %+v, want Succeeded", stopped)
+	}
+}
+
+func TestPreflightCheckFailure(t *testing.T) {
+	fake := newFakeARM(t)
+	fake.AddFailure(fakearm.Failure{PathContains: "Microsoft.Authorization", Status: http.StatusInternalServerError, Code: "InternalServerError", Message: "Try again later"})
+	s := newPermissionServer(fake)
+
+	// Azure still checks the role, so a failed pre-flight lets the call through
+	result := callTool(t, s, "start_compute", workspaceArgs(map[string]any{"compute_name": testCompute}))
+	if result.IsError {
+		t.Fatalf("start_compute failed: %s", resultText(result))
+	}
+
+	got := toolError(t, callTool(t, s, "check_permissions", workspaceArgs(nil)))
+	if got.Code != tools.ErrorUnavailable {
+		t.Errorf("check_permissions error = %+v, want Unavailable", got)
+	}
+}
@@ -0,0 +1,37 @@
+package tools
+
+import (
+	"context"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// progressReporter sends MCP progress notifications for a tool call
+type progressReporter struct {
+	server   *server.MCPServer
+	token    mcp.ProgressToken
+	progress float64
+}
+
+func newProgressReporter(ctx context.Context, request mcp.CallToolRequest) *progressReporter {
+	p := &progressReporter{server: server.ServerFromContext(ctx)}
+	if request.Params.Meta != nil {
+		p.token = request.Params.Meta.ProgressToken
+	}
+	return p
+}
+
+// report sends the next progress notification with a human-readable message
+func (p *progressReporter) report(ctx context.Context, message string) {
+	if p.server == nil || p.token == nil {
+		return
+	}
+	p.progress++
+	// Progress is best effort
+	_ = p.server.SendNotificationToClient(ctx, "notifications/progress", map[string]any{
+		"progressToken": p.token,
+		"progress":      p.progress,
+		"message":       message,
+	})
+}
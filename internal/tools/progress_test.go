@@ -0,0 +1,104 @@
+package tools_test
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"microsoft.com/aml-mcp/internal/tools"
+)
+
+// callToolWithProgress invokes a tool within session, asking for progress
+// notifications under token unless it is empty
+func callToolWithProgress(ctx context.Context, t *testing.T, s *server.MCPServer, session *testSession, name, token string, args map[string]any) mcp.CallToolResult {
+	t.Helper()
+
+	params := map[string]any{"name": name, "arguments": args}
+	if token != "" {
+		params["_meta"] = map[string]any{"progressToken": token}
+	}
+	message, err := json.Marshal(map[string]any{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  "tools/call",
+		"params":  params,
+	})
+	if err != nil {
+		t.Fatalf("failed to marshal request: %v", err)
+	}
+
+	response := s.HandleMessage(s.WithContext(ctx, session), message)
+	rpcResponse, ok := response.(mcp.JSONRPCResponse)
+	if !ok {
+		t.Fatalf("tools/call %s returned %T: %+v", name, response, response)
+	}
+	return rpcResponse.Result.(mcp.CallToolResult)
+}
+
+func TestLongRunningOperationProgress(t *testing.T) {
+	fake := newFakeARM(t)
+	fake.SetPendingPolls(2)
+	s := server.NewMCPServer("test", "1.0.0")
+	tools.NewComputeTools(fake.ClientCache(), tools.WithPollInterval(10*time.Millisecond)).AddToServer(s)
+	session := newTestSession("session-1")
+
+	result := callToolWithProgress(context.Background(), t, s, session, "start_compute", "progress-1",
+		workspaceArgs(map[string]any{"compute_name": testCompute}))
+	if result.IsError {
+		t.Fatalf("start_compute failed: %s", resultText(result))
+	}
+
+	messages := session.progressMessages("progress-1")
+	if len(messages) != 2 {
+		t.Fatalf("got %d progress notifications, want 2: %q", len(messages), messages)
+	}
+	for _, message := range messages {
+		if !strings.Contains(message, "Starting compute 'test-ci': InProgress") || !strings.Contains(message, "elapsed") {
+			t.Errorf("progress message %q does not report the operation status and elapsed time", message)
+		}
+	}
+}
+
+func TestLongRunningOperationWithoutProgressToken(t *testing.T) {
+	fake := newFakeARM(t)
+	fake.SetPendingPolls(1)
+	s := server.NewMCPServer("test", "1.0.0")
+	tools.NewComputeTools(fake.ClientCache(), tools.WithPollInterval(10*time.Millisecond)).AddToServer(s)
+	session := newTestSession("session-1")
+
+	result := callToolWithProgress(context.Background(), t, s, session, "stop_compute", "",
+		workspaceArgs(map[string]any{"compute_name": testCompute}))
+	if result.IsError {
+		t.Fatalf("stop_compute failed: %s", resultText(result))
+	}
+	if len(session.notifications) != 0 {
+		t.Errorf("sent %d notifications without a progress token, want 0", len(session.notifications))
+	}
+}
+
+func TestLongRunningOperationCancelled(t *testing.T) {
+	fake := newFakeARM(t)
+	fake.SetPendingPolls(1000)
+	s := server.NewMCPServer("test", "1.0.0")
+	tools.NewComputeTools(fake.ClientCache(), tools.WithPollInterval(10*time.Millisecond)).AddToServer(s)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	result := callToolWithProgress(ctx, t, s, newTestSession("session-1"), "start_compute", "progress-2",
+		workspaceArgs(map[string]any{"compute_name": testCompute}))
+	if !result.IsError {
+		t.Fatalf("start_compute succeeded after its context was cancelled: %s", resultText(result))
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("start_compute took %s to notice cancellation", elapsed)
+	}
+	if got := fake.ComputeState(testSubscriptionID, testResourceGroup, testWorkspace, testCompute); got != "Stopped" {
+		t.Errorf("compute state = %q, want Stopped", got)
+	}
+}
@@ -0,0 +1,236 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// Prompts contains the MCP prompts for common Azure ML workflows
+type Prompts struct {
+	shared
+}
+
+// NewPrompts creates a new Prompts instance
+func NewPrompts(opts ...Option) *Prompts {
+	return &Prompts{shared: newShared(opts)}
+}
+
+// promptStep is one step of a prompt's instructions and the tool it uses
+type promptStep struct {
+	tool        string
+	text        string
+	unavailable string
+}
+
+// AddToServer registers all prompts with the MCP server
+func (p *Prompts) AddToServer(s PromptRegistrar) {
+	s.AddPrompt(mcp.NewPrompt("diagnose_compute",
+		mcp.WithPromptDescription("Find out why an Azure ML compute resource is failing or unavailable"),
+		mcp.WithArgument(argCompute, mcp.RequiredArgument(), mcp.ArgumentDescription("Name of the compute resource")),
+		workspaceArgument(argSubscriptionID, "Azure subscription ID"),
+		workspaceArgument(argResourceGroup, "Resource group name"),
+		workspaceArgument(argWorkspace, "Workspace name"),
+	), p.handleDiagnoseCompute)
+
+	s.AddPrompt(mcp.NewPrompt("audit_network_isolation",
+		mcp.WithPromptDescription("Review how well an Azure ML workspace is isolated from public networks"),
+		workspaceArgument(argSubscriptionID, "Azure subscription ID"),
+		workspaceArgument(argResourceGroup, "Resource group name"),
+		workspaceArgument(argWorkspace, "Workspace name"),
+	), p.handleAuditNetworkIsolation)
+
+	s.AddPrompt(mcp.NewPrompt("find_idle_compute",
+		mcp.WithPromptDescription("Find compute in an Azure ML workspace that looks idle and could be shut down"),
+		workspaceArgument(argSubscriptionID, "Azure subscription ID"),
+		workspaceArgument(argResourceGroup, "Resource group name"),
+		workspaceArgument(argWorkspace, "Workspace name"),
+	), p.handleFindIdleCompute)
+
+	s.AddPrompt(mcp.NewPrompt("check_gpu_quota",
+		mcp.WithPromptDescription("Check there is quota for a GPU cluster in a region before creating it"),
+		mcp.WithArgument("location", mcp.RequiredArgument(), mcp.ArgumentDescription("Azure region of the cluster, e.g. eastus")),
+		mcp.WithArgument("vm_size", mcp.ArgumentDescription("VM size of the cluster's nodes, e.g. Standard_NC6s_v3. Left to the model to pick when empty")),
+		mcp.WithArgument("node_count", mcp.ArgumentDescription("Maximum number of nodes in the cluster (default 1)")),
+		workspaceArgument(argSubscriptionID, "Azure subscription ID"),
+	), p.handleCheckGPUQuota)
+}
+
+// workspaceArgument is an optional prompt argument identifying the workspace
+func workspaceArgument(name, description string) mcp.PromptOption {
+	return mcp.WithArgument(name, mcp.ArgumentDescription(description+". Defaults to the active workspace's"))
+}
+
+func (p *Prompts) handleDiagnoseCompute(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+	computeName := request.Params.Arguments[argCompute]
+	if computeName == "" {
+		return nil, fmt.Errorf("%s is required", argCompute)
+	}
+
+	scope := p.promptScope(ctx, request)
+	return promptResult(ctx, "Diagnose compute "+computeName,
+		fmt.Sprintf("Diagnose why the Azure ML compute resource '%s' in %s is failing or unavailable.", computeName, scope.describe()),
+		[]promptStep{
+			{tool: "get_compute", text: fmt.Sprintf("Call get_compute with compute_name '%s'%s. Note its type, location, provisioning state and when it was last modified.", computeName, scope.arguments())},
+			{tool: "get_workspace", text: fmt.Sprintf("Call get_workspace%s and check the workspace itself provisioned successfully.", scope.arguments())},
+			{tool: "list_usage", text: "Call list_usage for the compute's location and check whether its VM family is at or near its quota limit."},
+			{tool: "list_quotas", text: "Call list_quotas for the same location to see the workspace-level quota."},
+			{tool: "list_audit_events", text: "Call list_audit_events to see recent start, stop and other changes made through this server, and whether any failed."},
+			{tool: "list_private_endpoints", text: fmt.Sprintf("If the failure looks network related, call list_private_endpoints%s and check the connections are approved.", scope.arguments())},
+		},
+		"Explain the most likely cause, quoting the error codes and messages you found, and suggest a fix. Do not start, stop or change anything unless I ask you to."), nil
+}
+
+func (p *Prompts) handleAuditNetworkIsolation(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+	scope := p.promptScope(ctx, request)
+	return promptResult(ctx, "Audit network isolation",
+		fmt.Sprintf("Audit the network isolation of %s.", scope.describe()),
+		[]promptStep{
+			{tool: "get_workspace", text: fmt.Sprintf("Call get_workspace%s to confirm the workspace and its location.", scope.arguments())},
+			{tool: "list_private_endpoints", text: fmt.Sprintf("Call list_private_endpoints%s. A workspace with no approved private endpoint is reachable only over the public network.", scope.arguments())},
+			{tool: "list_workspace_connections", text: fmt.Sprintf("Call list_workspace_connections%s and flag connections to external targets that are not private endpoints or use shared credentials.", scope.arguments())},
+			{tool: "list_compute", text: fmt.Sprintf("Call list_compute%s and note attached compute, which is managed outside the workspace's network controls.", scope.arguments())},
+			{tool: "list_workspace_features", text: fmt.Sprintf("Call list_workspace_features%s for network-related features enabled on the workspace.", scope.arguments())},
+		},
+		"Summarise the findings as a list ordered by risk, each with the evidence and a recommended change. This is a review only: do not change anything."), nil
+}
+
+func (p *Prompts) handleFindIdleCompute(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+	scope := p.promptScope(ctx, request)
+	return promptResult(ctx, "Find idle compute",
+		fmt.Sprintf("Find compute in %s that looks idle and could be shut down to save cost.", scope.describe()),
+		[]promptStep{
+			{tool: "list_compute", text: fmt.Sprintf("Call list_compute%s with sort_by createdOn, and note each resource's type and provisioning state.", scope.arguments())},
+			{tool: "get_compute", text: "Call get_compute for each compute instance and cluster to see when it was last modified. Compute instances and clusters that have not changed for several days are candidates."},
+			{tool: "list_audit_events", text: "Call list_audit_events with tool start_compute to see which candidates were started recently through this server, and leave those out."},
+			{
+				tool:        "stop_compute",
+				text:        "List the candidates with the reason each looks idle, then ask me which ones to stop. Only call stop_compute for the ones I confirm, one at a time.",
+				unavailable: "List the candidates with the reason each looks idle. This server does not offer stop_compute, so tell me how to stop them myself instead.",
+			},
+		},
+		"Do not stop or delete anything I have not confirmed."), nil
+}
+
+func (p *Prompts) handleCheckGPUQuota(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+	location := request.Params.Arguments["location"]
+	if location == "" {
+		return nil, fmt.Errorf("location is required")
+	}
+	vmSize := request.Params.Arguments["vm_size"]
+	nodeCount := request.Params.Arguments["node_count"]
+	if nodeCount == "" {
+		nodeCount = "1"
+	}
+	subscription := ""
+	if subscriptionID := p.promptScope(ctx, request).SubscriptionID; subscriptionID != "" {
+		subscription = fmt.Sprintf(" with subscription_id '%s'", subscriptionID)
+	}
+
+	sizeStep := fmt.Sprintf("Call list_vm_sizes%s for location '%s' with name_contains 'Standard_N' to list the GPU VM sizes available there, and pick the smallest that suits a training cluster.", subscription, location)
+	if vmSize != "" {
+		sizeStep = fmt.Sprintf("Call list_vm_sizes%s for location '%s' with name_contains '%s' to confirm the size is available there and find its vCPU count.", subscription, location, vmSize)
+	}
+	cluster := fmt.Sprintf("a GPU cluster of up to %s nodes", nodeCount)
+	if vmSize != "" {
+		cluster = fmt.Sprintf("a GPU cluster of up to %s %s nodes", nodeCount, vmSize)
+	}
+
+	return promptResult(ctx, "Check GPU quota",
+		fmt.Sprintf("Check whether there is enough quota in %s for %s before it is created.", location, cluster),
+		[]promptStep{
+			{tool: "list_vm_sizes", text: sizeStep},
+			{tool: "list_usage", text: fmt.Sprintf("Call list_usage%s for location '%s' and find the usage and limit of the VM size's family, and of total regional vCPUs.", subscription, location)},
+			{tool: "list_quotas", text: fmt.Sprintf("Call list_quotas%s for location '%s' to see the workspace-level quota for the family.", subscription, location)},
+		},
+		fmt.Sprintf("Work out the vCPUs the cluster needs (vCPUs per node times %s) and compare them with the remaining quota. Say clearly whether it fits; if not, say how many nodes would fit, suggest another size or region, or explain how to request a quota increase. Do not create anything.", nodeCount)), nil
+}
+
+// promptScope is the workspace a prompt is about
+type promptScope struct {
+	SubscriptionID string
+	ResourceGroup  string
+	Workspace      string
+	// explicit lists the arguments the prompt was given
+	explicit []string
+}
+
+func (p *Prompts) promptScope(ctx context.Context, request mcp.GetPromptRequest) promptScope {
+	effective := p.defaults.Merge(p.sessions.Get(sessionIDFromContext(ctx)))
+	scope := promptScope{
+		SubscriptionID: effective.SubscriptionID,
+		ResourceGroup:  effective.ResourceGroup,
+		Workspace:      effective.Workspace,
+	}
+	for _, arg := range []struct {
+		name string
+		dst  *string
+	}{
+		{argSubscriptionID, &scope.SubscriptionID},
+		{argResourceGroup, &scope.ResourceGroup},
+		{argWorkspace, &scope.Workspace},
+	} {
+		if value := request.Params.Arguments[arg.name]; value != "" {
+			*arg.dst = value
+			scope.explicit = append(scope.explicit, fmt.Sprintf("%s '%s'", arg.name, value))
+		}
+	}
+	return scope
+}
+
+// describe names the workspace in the prompt's opening sentence
+func (s promptScope) describe() string {
+	if s.Workspace == "" {
+		return "the active workspace (call get_active_workspace first, and ask me for the workspace if none is set)"
+	}
+	description := fmt.Sprintf("the workspace '%s'", s.Workspace)
+	if s.ResourceGroup != "" {
+		description += fmt.Sprintf(" in resource group '%s'", s.ResourceGroup)
+	}
+	if s.SubscriptionID != "" {
+		description += fmt.Sprintf(" (subscription %s)", s.SubscriptionID)
+	}
+	return description
+}
+
+// arguments renders the workspace arguments to pass to each tool
+func (s promptScope) arguments() string {
+	if len(s.explicit) == 0 {
+		return ""
+	}
+	return " with " + strings.Join(s.explicit, ", ")
+}
+
+// promptResult renders a prompt as a single user message
+func promptResult(ctx context.Context, description, goal string, steps []promptStep, closing string) *mcp.GetPromptResult {
+	var b strings.Builder
+	b.WriteString(goal)
+	b.WriteString("\n")
+	n := 0
+	for _, step := range steps {
+		text := step.text
+		if !toolAvailable(ctx, step.tool) {
+			text = step.unavailable
+		}
+		if text == "" {
+			continue
+		}
+		n++
+		fmt.Fprintf(&b, "\n%d. %s", n, text)
+	}
+	b.WriteString("\n\n")
+	b.WriteString(closing)
+
+	return mcp.NewGetPromptResult(description, []mcp.PromptMessage{
+		mcp.NewPromptMessage(mcp.RoleUser, mcp.NewTextContent(b.String())),
+	})
+}
+
+// toolAvailable reports whether the server handling the request offers a tool
+func toolAvailable(ctx context.Context, name string) bool {
+	s := server.ServerFromContext(ctx)
+	return s == nil || s.GetTool(name) != nil
+}
@@ -0,0 +1,143 @@
+package tools_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"microsoft.com/aml-mcp/internal/azure"
+	"microsoft.com/aml-mcp/internal/session"
+	"microsoft.com/aml-mcp/internal/tools"
+)
+
+// getPrompt renders a prompt through the MCP server's JSON-RPC handler and
+// returns its text, or the JSON-RPC error message
+func getPrompt(t *testing.T, s *server.MCPServer, name string, args map[string]string) (string, string) {
+	t.Helper()
+
+	response := s.HandleMessage(context.Background(), resourceMessage(t, "prompts/get", map[string]any{"name": name, "arguments": args}))
+	if rpcError, ok := response.(mcp.JSONRPCError); ok {
+		return "", rpcError.Error.Message
+	}
+	result, ok := response.(mcp.JSONRPCResponse).Result.(mcp.GetPromptResult)
+	if !ok || len(result.Messages) != 1 {
+		t.Fatalf("prompts/get %s returned %+v", name, response)
+	}
+	text, ok := mcp.AsTextContent(result.Messages[0].Content)
+	if !ok || result.Messages[0].Role != mcp.RoleUser {
+		t.Fatalf("prompts/get %s message is %+v", name, result.Messages[0])
+	}
+	return text.Text, ""
+}
+
+// newPromptServer registers the prompts and, unless readOnly, every compute tool
+func newPromptServer(t *testing.T, readOnly bool, opts ...tools.Option) *server.MCPServer {
+	t.Helper()
+
+	s := server.NewMCPServer("test", "1.0.0", server.WithPromptCapabilities(false))
+	clients := azure.NewClientCache()
+	registrar := tools.Registrar(s)
+	if readOnly {
+		registrar = readOnlyRegistrar{s}
+	}
+	tools.NewWorkspaceTools(clients, opts...).AddToServer(registrar)
+	tools.NewComputeTools(clients, opts...).AddToServer(registrar)
+	tools.NewMonitoringTools(clients, opts...).AddToServer(registrar)
+	tools.NewPrompts(opts...).AddToServer(s)
+	return s
+}
+
+// readOnlyRegistrar registers only tools annotated as read-only
+type readOnlyRegistrar struct {
+	next tools.Registrar
+}
+
+func (r readOnlyRegistrar) AddTool(tool mcp.Tool, handler server.ToolHandlerFunc) {
+	if tool.Annotations.ReadOnlyHint != nil && *tool.Annotations.ReadOnlyHint {
+		r.next.AddTool(tool, handler)
+	}
+}
+
+func TestPrompts(t *testing.T) {
+	s := newPromptServer(t, false, tools.WithDefaults(session.Workspace{
+		SubscriptionID: testSubscriptionID,
+		ResourceGroup:  testResourceGroup,
+		Workspace:      testWorkspace,
+	}))
+
+	tests := []struct {
+		name        string
+		args        map[string]string
+		contains    []string
+		notContains []string
+	}{
+		{
+			name: "diagnose_compute",
+			args: map[string]string{"compute_name": "gpu-cluster", "workspace_name": "other-ws"},
+			contains: []string{
+				"compute resource 'gpu-cluster' in the workspace 'other-ws' in resource group 'test-rg'",
+				"1. Call get_compute with compute_name 'gpu-cluster' with workspace_name 'other-ws'",
+				"Call get_workspace with workspace_name 'other-ws'",
+			},
+			// Tools of categories that were not registered are left out
+			notContains: []string{"list_audit_events", "list_private_endpoints"},
+		},
+		{
+			name:        "audit_network_isolation",
+			contains:    []string{"the workspace 'test-ws' in resource group 'test-rg'", "Call get_workspace to confirm", "Call list_compute and note"},
+			notContains: []string{"with subscription_id"},
+		},
+		{
+			name:     "find_idle_compute",
+			contains: []string{"Call list_compute with sort_by createdOn", "Only call stop_compute for the ones I confirm"},
+		},
+		{
+			name: "check_gpu_quota",
+			args: map[string]string{"location": "westus2", "vm_size": "Standard_NC6s_v3", "node_count": "4"},
+			contains: []string{
+				"a GPU cluster of up to 4 Standard_NC6s_v3 nodes",
+				"Call list_vm_sizes with subscription_id '" + testSubscriptionID + "' for location 'westus2' with name_contains 'Standard_NC6s_v3'",
+				"vCPUs per node times 4",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			text, errMessage := getPrompt(t, s, tt.name, tt.args)
+			if errMessage != "" {
+				t.Fatalf("prompts/get failed: %s", errMessage)
+			}
+			for _, want := range tt.contains {
+				if !strings.Contains(text, want) {
+					t.Errorf("prompt %q does not contain %q", text, want)
+				}
+			}
+			for _, unwanted := range tt.notContains {
+				if strings.Contains(text, unwanted) {
+					t.Errorf("prompt %q contains %q", text, unwanted)
+				}
+			}
+		})
+	}
+}
+
+func TestPromptsWithoutTools(t *testing.T) {
+	s := newPromptServer(t, true)
+
+	text, errMessage := getPrompt(t, s, "find_idle_compute", nil)
+	if errMessage != "" {
+		t.Fatalf("prompts/get failed: %s", errMessage)
+	}
+	if strings.Contains(text, "Only call stop_compute") || !strings.Contains(text, "does not offer stop_compute") {
+		t.Errorf("prompt %q does not account for stop_compute being unavailable", text)
+	}
+	if !strings.Contains(text, "the active workspace (call get_active_workspace first") {
+		t.Errorf("prompt %q does not fall back to the active workspace", text)
+	}
+
+	if _, errMessage := getPrompt(t, s, "diagnose_compute", nil); !strings.Contains(errMessage, "compute_name is required") {
+		t.Errorf("diagnose_compute without compute_name error = %q", errMessage)
+	}
+}
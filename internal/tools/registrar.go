@@ -0,0 +1,35 @@
+package tools
+
+import (
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// Registrar is what tool sets register their tools with
+type Registrar interface {
+	AddTool(tool mcp.Tool, handler server.ToolHandlerFunc)
+}
+
+// ResourceRegistrar is what tool sets register their MCP resources with
+type ResourceRegistrar interface {
+	AddResource(resource mcp.Resource, handler server.ResourceHandlerFunc)
+	AddResourceTemplate(template mcp.ResourceTemplate, handler server.ResourceTemplateHandlerFunc)
+}
+
+// PromptRegistrar is what prompts are registered with
+type PromptRegistrar interface {
+	AddPrompt(prompt mcp.Prompt, handler server.PromptHandlerFunc)
+}
+
+// Tool categories, one per tool set, used to allow or deny groups of tools
+const (
+	CategoryWorkspace  = "workspace"
+	CategoryCompute    = "compute"
+	CategoryMonitoring = "monitoring"
+	CategoryNetwork    = "network"
+	CategoryContext    = "context"
+	CategoryOperations = "operations"
+	CategoryAudit      = "audit"
+	CategoryAuth       = "auth"
+	CategoryAccess     = "access"
+)
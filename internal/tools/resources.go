@@ -0,0 +1,260 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"microsoft.com/aml-mcp/internal/azure"
+	"microsoft.com/aml-mcp/internal/helpers"
+)
+
+// MCP resource URIs, following the ARM IDs of what they expose
+const (
+	subscriptionsURI      = "aml://subscriptions"
+	workspacesURITemplate = "aml://subscriptions/{subscriptionId}/workspaces"
+	workspaceURITemplate  = "aml://subscriptions/{subscriptionId}/resourceGroups/{resourceGroup}/workspaces/{workspace}"
+	computesURITemplate   = workspaceURITemplate + "/computes"
+	computeURITemplate    = computesURITemplate + "/{compute}"
+)
+
+// Variables of the resource URI templates
+const (
+	uriSubscriptionID = "subscriptionId"
+	uriResourceGroup  = "resourceGroup"
+	uriWorkspace      = "workspace"
+	uriCompute        = "compute"
+)
+
+const jsonMIMEType = "application/json"
+
+// AddResourcesToServer registers the subscription and workspace resources with the MCP server
+func (wt *WorkspaceTools) AddResourcesToServer(s ResourceRegistrar) {
+	s.AddResource(mcp.NewResource(subscriptionsURI, "Azure subscriptions",
+		mcp.WithResourceDescription("Azure subscriptions the signed-in identity can see, with links to their workspaces"),
+		mcp.WithMIMEType(jsonMIMEType),
+	), wt.handleSubscriptionsResource)
+
+	s.AddResourceTemplate(mcp.NewResourceTemplate(workspacesURITemplate, "Azure ML workspaces",
+		mcp.WithTemplateDescription("Azure ML workspaces in a subscription, as returned by list_workspaces_by_subscription"),
+		mcp.WithTemplateMIMEType(jsonMIMEType),
+	), wt.handleWorkspacesResource)
+
+	s.AddResourceTemplate(mcp.NewResourceTemplate(workspaceURITemplate, "Azure ML workspace",
+		mcp.WithTemplateDescription("An Azure ML workspace, as returned by get_workspace"),
+		mcp.WithTemplateMIMEType(jsonMIMEType),
+	), wt.handleWorkspaceResource)
+
+	if d := wt.defaults; d.SubscriptionID != "" && d.ResourceGroup != "" && d.Workspace != "" {
+		uri := workspaceURI(d.SubscriptionID, d.ResourceGroup, d.Workspace)
+		s.AddResource(mcp.NewResource(uri, "Default workspace: "+d.Workspace,
+			mcp.WithResourceDescription("The configured default Azure ML workspace"),
+			mcp.WithMIMEType(jsonMIMEType),
+		), withURIArguments(wt.handleWorkspaceResource, map[string]any{
+			uriSubscriptionID: d.SubscriptionID,
+			uriResourceGroup:  d.ResourceGroup,
+			uriWorkspace:      d.Workspace,
+		}))
+	}
+}
+
+// AddResourcesToServer registers the compute resources with the MCP server
+func (ct *ComputeTools) AddResourcesToServer(s ResourceRegistrar) {
+	s.AddResourceTemplate(mcp.NewResourceTemplate(computesURITemplate, "Azure ML compute resources",
+		mcp.WithTemplateDescription("Compute resources in an Azure ML workspace, as returned by list_compute"),
+		mcp.WithTemplateMIMEType(jsonMIMEType),
+	), ct.handleComputesResource)
+
+	s.AddResourceTemplate(mcp.NewResourceTemplate(computeURITemplate, "Azure ML compute resource",
+		mcp.WithTemplateDescription("An Azure ML compute resource, as returned by get_compute"),
+		mcp.WithTemplateMIMEType(jsonMIMEType),
+	), ct.handleComputeResource)
+
+	if d := ct.defaults; d.SubscriptionID != "" && d.ResourceGroup != "" && d.Workspace != "" {
+		uri := workspaceURI(d.SubscriptionID, d.ResourceGroup, d.Workspace) + "/computes"
+		s.AddResource(mcp.NewResource(uri, "Default workspace compute: "+d.Workspace,
+			mcp.WithResourceDescription("Compute resources in the configured default Azure ML workspace"),
+			mcp.WithMIMEType(jsonMIMEType),
+		), withURIArguments(ct.handleComputesResource, map[string]any{
+			uriSubscriptionID: d.SubscriptionID,
+			uriResourceGroup:  d.ResourceGroup,
+			uriWorkspace:      d.Workspace,
+		}))
+	}
+}
+
+func (wt *WorkspaceTools) handleSubscriptionsResource(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	subscriptions, err := listSubscriptions(ctx, wt.clients)
+	if err != nil {
+		return nil, resourceError(wt.clients, "failed to list subscriptions", err)
+	}
+
+	result := SubscriptionList{Count: len(subscriptions), Subscriptions: make([]Subscription, 0, len(subscriptions))}
+	for _, sub := range subscriptions {
+		subscription := Subscription{
+			SubscriptionID: *sub.SubscriptionID,
+			DisplayName:    valueOf(sub.DisplayName),
+			WorkspacesURI:  subscriptionsURI + "/" + *sub.SubscriptionID + "/workspaces",
+		}
+		if sub.State != nil {
+			subscription.State = string(*sub.State)
+		}
+		result.Subscriptions = append(result.Subscriptions, subscription)
+	}
+	return jsonResource(request.Params.URI, result)
+}
+
+func (wt *WorkspaceTools) handleWorkspacesResource(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	subscriptionID, err := uriArgument(request, uriSubscriptionID)
+	if err != nil {
+		return nil, err
+	}
+
+	workspaces, err := listAllWorkspaces(ctx, wt.clients, subscriptionID)
+	if err != nil {
+		return nil, resourceError(wt.clients, "failed to list workspaces", err)
+	}
+	return jsonResource(request.Params.URI, WorkspaceList{SubscriptionID: subscriptionID, Count: len(workspaces), Workspaces: workspaces})
+}
+
+func (wt *WorkspaceTools) handleWorkspaceResource(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	subscriptionID, resourceGroupName, workspaceName, err := workspaceURIArguments(request)
+	if err != nil {
+		return nil, err
+	}
+
+	clients, err := wt.clients.Get(ctx, subscriptionID)
+	if err != nil {
+		return nil, resourceError(wt.clients, "failed to sign in to Azure", err)
+	}
+	resp, err := clients.WorkspacesClient.Get(ctx, resourceGroupName, workspaceName, nil)
+	if err != nil {
+		return nil, resourceError(wt.clients, "failed to get workspace", err)
+	}
+	// As in get_workspace, the URI names the resource group when the ID does not
+	workspace := newWorkspace(&resp.Workspace)
+	if workspace.ResourceGroup == "" {
+		workspace.ResourceGroup = resourceGroupName
+	}
+	return jsonResource(request.Params.URI, workspace)
+}
+
+func (ct *ComputeTools) handleComputesResource(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	subscriptionID, resourceGroupName, workspaceName, err := workspaceURIArguments(request)
+	if err != nil {
+		return nil, err
+	}
+
+	computes, err := listAllCompute(ctx, ct.clients, subscriptionID, resourceGroupName, workspaceName)
+	if err != nil {
+		return nil, resourceError(ct.clients, "failed to list compute resources", err)
+	}
+	return jsonResource(request.Params.URI, ComputeList{Workspace: workspaceName, Count: len(computes), Computes: computes})
+}
+
+func (ct *ComputeTools) handleComputeResource(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	subscriptionID, resourceGroupName, workspaceName, err := workspaceURIArguments(request)
+	if err != nil {
+		return nil, err
+	}
+	computeName, err := uriArgument(request, uriCompute)
+	if err != nil {
+		return nil, err
+	}
+
+	clients, err := ct.clients.Get(ctx, subscriptionID)
+	if err != nil {
+		return nil, resourceError(ct.clients, "failed to sign in to Azure", err)
+	}
+	resp, err := clients.ComputeClient.Get(ctx, resourceGroupName, workspaceName, computeName, nil)
+	if err != nil {
+		return nil, resourceError(ct.clients, "failed to get compute resource", err)
+	}
+	return jsonResource(request.Params.URI, newCompute(&resp.ComputeResource))
+}
+
+// withURIArguments adapts a template handler to a resource with a fixed URI
+func withURIArguments(handler server.ResourceTemplateHandlerFunc, arguments map[string]any) server.ResourceHandlerFunc {
+	return func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		request.Params.Arguments = arguments
+		return handler(ctx, request)
+	}
+}
+
+// uriArgument returns a variable matched from a resource URI template
+func uriArgument(request mcp.ReadResourceRequest, name string) (string, error) {
+	var value string
+	switch v := request.Params.Arguments[name].(type) {
+	case string:
+		value = v
+	case []string:
+		if len(v) == 1 {
+			value = v[0]
+		}
+	}
+	if value == "" {
+		return "", fmt.Errorf("resource URI %s has no %s", request.Params.URI, name)
+	}
+	return value, nil
+}
+
+// workspaceURIArguments returns the workspace arguments of a resource URI
+func workspaceURIArguments(request mcp.ReadResourceRequest) (subscriptionID, resourceGroupName, workspaceName string, err error) {
+	if subscriptionID, err = uriArgument(request, uriSubscriptionID); err != nil {
+		return "", "", "", err
+	}
+	if resourceGroupName, err = uriArgument(request, uriResourceGroup); err != nil {
+		return "", "", "", err
+	}
+	if workspaceName, err = uriArgument(request, uriWorkspace); err != nil {
+		return "", "", "", err
+	}
+	return subscriptionID, resourceGroupName, workspaceName, nil
+}
+
+// jsonResource returns v as the JSON contents of the resource at uri
+func jsonResource(uri string, v any) ([]mcp.ResourceContents, error) {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode resource %s: %v", uri, err)
+	}
+	return []mcp.ResourceContents{mcp.TextResourceContents{URI: uri, MIMEType: jsonMIMEType, Text: string(data)}}, nil
+}
+
+// resourceError is azureError for resource reads
+func resourceError(clients *azure.ClientCache, message string, err error) error {
+	toolErr := newToolError(err)
+	if clients.InvalidateOnAuthError(err) {
+		toolErr.credentialDiscarded()
+	}
+	return fmt.Errorf("%s: %s", message, strings.ReplaceAll(toolErr.text(), "\n", "; "))
+}
+
+// workspaceURI builds the resource URI of a workspace
+func workspaceURI(subscriptionID, resourceGroupName, workspaceName string) string {
+	return fmt.Sprintf("%s/%s/resourceGroups/%s/workspaces/%s", subscriptionsURI, subscriptionID, resourceGroupName, workspaceName)
+}
+
+// resourceURI returns the resource URI of a workspace or compute resource ID, or ""
+func resourceURI(id string) string {
+	parsed, err := helpers.ParseResourceID(id)
+	if err != nil || parsed.ResourceGroup == "" {
+		return ""
+	}
+	workspace := parsed.NameOf(workspaceResourceType)
+	if workspace == "" {
+		return ""
+	}
+	uri := workspaceURI(parsed.SubscriptionID, parsed.ResourceGroup, workspace)
+	switch {
+	case strings.EqualFold(parsed.ResourceType(), workspaceResourceType):
+		return uri
+	case strings.EqualFold(parsed.ResourceType(), computeResourceType):
+		return uri + "/computes/" + parsed.Name()
+	default:
+		return ""
+	}
+}
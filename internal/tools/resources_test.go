@@ -0,0 +1,180 @@
+package tools_test
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"microsoft.com/aml-mcp/internal/azure"
+	"microsoft.com/aml-mcp/internal/session"
+	"microsoft.com/aml-mcp/internal/tools"
+)
+
+const testWorkspaceURI = "aml://subscriptions/" + testSubscriptionID + "/resourceGroups/" + testResourceGroup + "/workspaces/" + testWorkspace
+
+// newResourceServer registers the workspace and compute resources against
+// the fake ARM server seeded by newInventoryServer
+func newResourceServer(t *testing.T, opts ...tools.Option) *server.MCPServer {
+	t.Helper()
+
+	fake, _ := newInventoryServer(t)
+	s := server.NewMCPServer("test", "1.0.0", server.WithResourceCapabilities(false, false))
+	tools.NewWorkspaceTools(fake.ClientCache(), opts...).AddResourcesToServer(s)
+	tools.NewComputeTools(fake.ClientCache(), opts...).AddResourcesToServer(s)
+	return s
+}
+
+// readResource reads a resource through the MCP server's JSON-RPC handler
+// and decodes its JSON contents into T. It fails the test on a JSON-RPC error.
+func readResource[T any](t *testing.T, s *server.MCPServer, uri string) T {
+	t.Helper()
+
+	var out T
+	response := s.HandleMessage(context.Background(), resourceMessage(t, "resources/read", map[string]any{"uri": uri}))
+	rpcResponse, ok := response.(mcp.JSONRPCResponse)
+	if !ok {
+		t.Fatalf("resources/read %s returned %T: %+v", uri, response, response)
+	}
+	result, ok := rpcResponse.Result.(mcp.ReadResourceResult)
+	if !ok || len(result.Contents) != 1 {
+		t.Fatalf("resources/read %s result is %+v", uri, rpcResponse.Result)
+	}
+	contents, ok := result.Contents[0].(mcp.TextResourceContents)
+	if !ok || contents.URI != uri || contents.MIMEType != "application/json" {
+		t.Fatalf("resources/read %s contents are %+v", uri, result.Contents[0])
+	}
+	if err := json.Unmarshal([]byte(contents.Text), &out); err != nil {
+		t.Fatalf("failed to decode resource %s: %v", contents.Text, err)
+	}
+	return out
+}
+
+func resourceMessage(t *testing.T, method string, params map[string]any) []byte {
+	t.Helper()
+	message, err := json.Marshal(map[string]any{"jsonrpc": "2.0", "id": 1, "method": method, "params": params})
+	if err != nil {
+		t.Fatalf("failed to marshal request: %v", err)
+	}
+	return message
+}
+
+func TestReadResources(t *testing.T) {
+	s := newResourceServer(t)
+
+	subscriptions := readResource[tools.SubscriptionList](t, s, "aml://subscriptions")
+	if subscriptions.Count != 4 {
+		t.Errorf("subscription count = %d, want 4", subscriptions.Count)
+	}
+	var workspacesURI string
+	for _, sub := range subscriptions.Subscriptions {
+		if sub.SubscriptionID == testSubscriptionID {
+			workspacesURI = sub.WorkspacesURI
+		}
+	}
+
+	workspaces := readResource[tools.WorkspaceList](t, s, workspacesURI)
+	if workspaces.Count != 1 || workspaces.Workspaces[0].URI != testWorkspaceURI {
+		t.Fatalf("workspaces of %s = %+v", workspacesURI, workspaces)
+	}
+
+	workspace := readResource[tools.Workspace](t, s, testWorkspaceURI)
+	if workspace.Name != testWorkspace || workspace.FriendlyName != "Test Workspace" {
+		t.Errorf("workspace = %+v", workspace)
+	}
+
+	computes := readResource[tools.ComputeList](t, s, testWorkspaceURI+"/computes")
+	if computes.Count != 1 || computes.Computes[0].URI != testWorkspaceURI+"/computes/"+testCompute {
+		t.Fatalf("computes = %+v", computes)
+	}
+
+	compute := readResource[tools.Compute](t, s, computes.Computes[0].URI)
+	if compute.Name != testCompute || compute.Type != "ComputeInstance" {
+		t.Errorf("compute = %+v", compute)
+	}
+}
+
+func TestReadResourceErrors(t *testing.T) {
+	s := newResourceServer(t)
+
+	for _, tt := range []struct {
+		uri      string
+		contains string
+	}{
+		{testWorkspaceURI + "/computes/missing", "NotFound"},
+		{"aml://subscriptions/" + failingSubscriptionID + "/workspaces", "PermissionDenied"},
+		{"aml://unknown", "handler not found"},
+	} {
+		response := s.HandleMessage(context.Background(), resourceMessage(t, "resources/read", map[string]any{"uri": tt.uri}))
+		rpcError, ok := response.(mcp.JSONRPCError)
+		if !ok {
+			t.Errorf("resources/read %s returned %+v, want an error", tt.uri, response)
+			continue
+		}
+		if !strings.Contains(rpcError.Error.Message, tt.contains) {
+			t.Errorf("resources/read %s error %q does not contain %q", tt.uri, rpcError.Error.Message, tt.contains)
+		}
+	}
+}
+
+func TestListResources(t *testing.T) {
+	s := newResourceServer(t, tools.WithDefaults(session.Workspace{
+		SubscriptionID: testSubscriptionID,
+		ResourceGroup:  testResourceGroup,
+		Workspace:      testWorkspace,
+	}))
+
+	response := s.HandleMessage(context.Background(), resourceMessage(t, "resources/list", nil))
+	result, ok := response.(mcp.JSONRPCResponse).Result.(mcp.ListResourcesResult)
+	if !ok {
+		t.Fatalf("resources/list returned %+v", response)
+	}
+	var uris []string
+	for _, resource := range result.Resources {
+		uris = append(uris, resource.URI)
+	}
+	for _, want := range []string{"aml://subscriptions", testWorkspaceURI, testWorkspaceURI + "/computes"} {
+		if !slices.Contains(uris, want) {
+			t.Errorf("resources/list does not include %s (resources: %v)", want, uris)
+		}
+	}
+
+	response = s.HandleMessage(context.Background(), resourceMessage(t, "resources/templates/list", nil))
+	templates, ok := response.(mcp.JSONRPCResponse).Result.(mcp.ListResourceTemplatesResult)
+	if !ok || len(templates.ResourceTemplates) != 4 {
+		t.Fatalf("resources/templates/list returned %+v", response)
+	}
+
+	// The default workspace resource reads like the template it mirrors
+	workspace := readResource[tools.Workspace](t, s, testWorkspaceURI)
+	if workspace.Name != testWorkspace {
+		t.Errorf("default workspace = %+v", workspace)
+	}
+}
+
+func TestReadResourceSignInErrors(t *testing.T) {
+	for _, name := range []string{azure.EnvTenantID, azure.EnvClientID} {
+		t.Setenv(name, "")
+	}
+	clients := azure.NewClientCacheWithOptions(azure.ClientCacheOptions{
+		Auth: azure.AuthOptions{Modes: []string{azure.AuthServicePrincipal}},
+	})
+	s := server.NewMCPServer("test", "1.0.0", server.WithResourceCapabilities(false, false))
+	tools.NewWorkspaceTools(clients).AddResourcesToServer(s)
+	tools.NewComputeTools(clients).AddResourcesToServer(s)
+
+	for _, uri := range []string{testWorkspaceURI, testWorkspaceURI + "/computes/" + testCompute} {
+		response := s.HandleMessage(context.Background(), resourceMessage(t, "resources/read", map[string]any{"uri": uri}))
+		rpcError, ok := response.(mcp.JSONRPCError)
+		if !ok {
+			t.Errorf("resources/read %s returned %+v, want an error", uri, response)
+			continue
+		}
+		if message := rpcError.Error.Message; !strings.HasPrefix(message, "failed to sign in to Azure: ") || !strings.Contains(message, tools.ErrorUnauthenticated) {
+			t.Errorf("resources/read %s error = %q, want an Unauthenticated sign-in error", uri, message)
+		}
+	}
+}
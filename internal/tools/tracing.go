@@ -0,0 +1,73 @@
+package tools
+
+import (
+	"context"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"microsoft.com/aml-mcp/internal/audit"
+)
+
+// TracerName is the instrumentation scope of tool call spans
+const TracerName = "microsoft.com/aml-mcp/internal/tools"
+
+// Traced returns a Registrar that records every tool call as a span before registering the tool with r
+func Traced(r Registrar, tracer trace.Tracer, opts ...Option) Registrar {
+	return &traceRegistrar{next: r, tracer: tracer, shared: newShared(opts)}
+}
+
+type traceRegistrar struct {
+	next   Registrar
+	tracer trace.Tracer
+	shared
+}
+
+func (t *traceRegistrar) AddTool(tool mcp.Tool, handler server.ToolHandlerFunc) {
+	t.next.AddTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		ctx, span := t.tracer.Start(ctx, "tools/call "+request.Params.Name,
+			trace.WithAttributes(t.spanAttrs(ctx, request)...),
+		)
+		defer span.End()
+
+		result, err := handler(ctx, request)
+
+		outcome, message := auditOutcome(result, err)
+		span.SetAttributes(attribute.String("mcp.tool.result", outcome))
+		if outcome == audit.OutcomeFailed {
+			span.SetAttributes(attribute.String("error.type", errorCode(result)))
+			span.SetStatus(codes.Error, message)
+		}
+		if err != nil {
+			span.RecordError(err)
+		}
+		return result, err
+	})
+}
+
+// spanAttrs returns the attributes identifying a tool call
+func (t *traceRegistrar) spanAttrs(ctx context.Context, request mcp.CallToolRequest) []attribute.KeyValue {
+	attrs := []attribute.KeyValue{
+		attribute.String("mcp.method.name", "tools/call"),
+		attribute.String("gen_ai.tool.name", request.Params.Name),
+	}
+	if sessionID := sessionIDFromContext(ctx); sessionID != "" {
+		attrs = append(attrs, attribute.String("mcp.session.id", sessionID))
+	}
+	scope := scopeAttrs(t.shared, ctx, request, [3]string{"azure.subscription.id", "azure.resource_group.name", "azureml.workspace.name"},
+		attribute.String)
+	return append(attrs, scope...)
+}
+
+// errorCode returns the ToolError code of a failed result, or Unknown
+func errorCode(result *mcp.CallToolResult) string {
+	if result == nil || result.Meta == nil {
+		return ErrorUnknown
+	}
+	if toolErr, ok := result.Meta.AdditionalFields[errorMetaKey].(ToolError); ok && toolErr.Code != "" {
+		return toolErr.Code
+	}
+	return ErrorUnknown
+}
@@ -0,0 +1,97 @@
+package tools_test
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/server"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"microsoft.com/aml-mcp/internal/azure"
+	"microsoft.com/aml-mcp/internal/tools"
+)
+
+func TestTracedTools(t *testing.T) {
+	fake := newFakeARM(t)
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	clients := azure.NewClientCacheWithOptions(azure.ClientCacheOptions{
+		Credential:     fake.Credential(),
+		ClientOptions:  fake.ClientOptions(),
+		TracerProvider: provider,
+	})
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&logs, nil))
+
+	s := server.NewMCPServer("test", "1.0.0")
+	logged := tools.Logged(tools.Traced(s, provider.Tracer(tools.TracerName)), logger)
+	tools.NewWorkspaceTools(clients).AddToServer(logged)
+
+	callTool(t, s, "get_workspace", workspaceArgs(nil))
+	callTool(t, s, "get_workspace", workspaceArgs(map[string]any{"workspace_name": "missing-ws"}))
+
+	spans := exporter.GetSpans()
+	var calls []tracetest.SpanStub
+	for _, span := range spans {
+		if span.Name == "tools/call get_workspace" {
+			calls = append(calls, span)
+		}
+	}
+	if len(calls) != 2 {
+		t.Fatalf("recorded %d tool call spans, want 2", len(calls))
+	}
+
+	found, missing := calls[0], calls[1]
+	attrs := spanAttributes(found)
+	for key, want := range map[string]string{
+		"gen_ai.tool.name":          "get_workspace",
+		"azure.subscription.id":     testSubscriptionID,
+		"azure.resource_group.name": testResourceGroup,
+		"azureml.workspace.name":    testWorkspace,
+		"mcp.tool.result":           "Succeeded",
+	} {
+		if got := attrs[key]; got != want {
+			t.Errorf("%s = %q, want %q", key, got, want)
+		}
+	}
+	if found.Status.Code == codes.Error {
+		t.Errorf("successful call status = %v, want no error", found.Status)
+	}
+
+	attrs = spanAttributes(missing)
+	if attrs["mcp.tool.result"] != "Failed" || attrs["error.type"] != tools.ErrorNotFound || missing.Status.Code != codes.Error {
+		t.Errorf("failed call attributes = %v, status = %v, want a NotFound error", attrs, missing.Status)
+	}
+
+	// The ARM requests of each call are its children, in the same trace
+	for _, call := range calls {
+		var children int
+		for _, span := range spans {
+			if span.Parent.SpanID() == call.SpanContext.SpanID() && span.SpanContext.TraceID() == call.SpanContext.TraceID() {
+				children++
+			}
+		}
+		if children == 0 {
+			t.Errorf("%s has no ARM request spans", call.Name)
+		}
+	}
+
+	// Logs name the trace of the call they belong to
+	if traceID := found.SpanContext.TraceID().String(); !strings.Contains(logs.String(), "traceId="+traceID) {
+		t.Errorf("logs = %s, want trace ID %s", logs.String(), traceID)
+	}
+}
+
+func spanAttributes(span tracetest.SpanStub) map[string]string {
+	attrs := make(map[string]string, len(span.Attributes))
+	for _, attr := range span.Attributes {
+		if attr.Value.Type() == attribute.STRING {
+			attrs[string(attr.Key)] = attr.Value.AsString()
+		}
+	}
+	return attrs
+}
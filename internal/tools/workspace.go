@@ -8,72 +8,95 @@ import (
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/machinelearning/armmachinelearning"
 	"github.com/mark3labs/mcp-go/mcp"
-	"github.com/mark3labs/mcp-go/server"
 	"microsoft.com/aml-mcp/internal/azure"
-	"microsoft.com/aml-mcp/internal/helpers"
+	"microsoft.com/aml-mcp/internal/operations"
 )
 
 // WorkspaceTools contains all workspace-related MCP tools
-type WorkspaceTools struct{}
+type WorkspaceTools struct {
+	clients *azure.ClientCache
+	shared
+}
 
-// NewWorkspaceTools creates a new WorkspaceTools instance
-func NewWorkspaceTools() *WorkspaceTools {
-	return &WorkspaceTools{}
+// NewWorkspaceTools creates a new WorkspaceTools instance backed by a shared client cache
+func NewWorkspaceTools(clients *azure.ClientCache, opts ...Option) *WorkspaceTools {
+	return &WorkspaceTools{clients: clients, shared: newShared(opts)}
 }
 
 // AddToServer registers all workspace tools with the MCP server
-func (wt *WorkspaceTools) AddToServer(s *server.MCPServer) {
+func (wt *WorkspaceTools) AddToServer(s Registrar) {
 	wt.addListWorkspacesBySubscriptionTool(s)
 	wt.addGetWorkspaceTool(s)
 	wt.addCreateWorkspaceTool(s)
+	wt.addGetInventoryTool(s)
 }
 
-func (wt *WorkspaceTools) addListWorkspacesBySubscriptionTool(s *server.MCPServer) {
+func (wt *WorkspaceTools) addListWorkspacesBySubscriptionTool(s Registrar) {
 	tool := mcp.NewTool("list_workspaces_by_subscription",
 		mcp.WithDescription("List all Azure ML workspaces in a subscription"),
-		mcp.WithString("subscription_id",
-			mcp.Required(),
-			mcp.Description("Azure subscription ID"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOutputSchema[WorkspaceList](),
+		mcp.WithString(argResourceID,
+			mcp.Description("ARM resource ID of the subscription or any resource in it. Takes precedence over subscription_id"),
+		),
+		mcp.WithString(argSubscriptionID,
+			mcp.Description("Azure subscription ID. Defaults to the active workspace's subscription"),
+		),
+		mcp.WithString(argNameContains,
+			mcp.Description("Only list workspaces whose name contains this text, ignoring case"),
+		),
+		mcp.WithString(argLocation,
+			mcp.Description("Only list workspaces in this Azure region (e.g., eastus)"),
+		),
+		mcp.WithString(argState,
+			mcp.Description("Only list workspaces in this provisioning state (e.g., Succeeded, Failed)"),
+		),
+		mcp.WithBoolean(argRefresh,
+			mcp.Description(refreshDescription),
 		),
 	)
+	addListOptions(&tool, "name", "location", "resourceGroup", "state")
 
 	s.AddTool(tool, wt.handleListWorkspacesBySubscription)
 }
 
-func (wt *WorkspaceTools) addGetWorkspaceTool(s *server.MCPServer) {
+func (wt *WorkspaceTools) addGetWorkspaceTool(s Registrar) {
 	tool := mcp.NewTool("get_workspace",
 		mcp.WithDescription("Get details of a specific Azure ML workspace"),
-		mcp.WithString("subscription_id",
-			mcp.Required(),
-			mcp.Description("Azure subscription ID"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOutputSchema[Workspace](),
+		mcp.WithString(argResourceID,
+			mcp.Description("Full ARM resource ID of the workspace. Takes precedence over subscription_id, resource_group_name and workspace_name"),
 		),
-		mcp.WithString("resource_group_name",
-			mcp.Required(),
-			mcp.Description("Resource group name"),
+		mcp.WithString(argSubscriptionID,
+			mcp.Description("Azure subscription ID. Defaults to the active workspace's subscription"),
 		),
-		mcp.WithString("workspace_name",
-			mcp.Required(),
-			mcp.Description("Workspace name"),
+		mcp.WithString(argResourceGroup,
+			mcp.Description("Resource group name. Defaults to the active workspace's resource group"),
+		),
+		mcp.WithString(argWorkspace,
+			mcp.Description("Workspace name. Defaults to the active workspace"),
 		),
 	)
 
 	s.AddTool(tool, wt.handleGetWorkspace)
 }
 
-func (wt *WorkspaceTools) addCreateWorkspaceTool(s *server.MCPServer) {
+func (wt *WorkspaceTools) addCreateWorkspaceTool(s Registrar) {
 	tool := mcp.NewTool("create_workspace",
 		mcp.WithDescription("Create a new Azure ML workspace"),
-		mcp.WithString("subscription_id",
-			mcp.Required(),
-			mcp.Description("Azure subscription ID"),
+		mcp.WithOutputSchema[WorkspaceCreation](),
+		mcp.WithString(argResourceID,
+			mcp.Description("Full ARM resource ID of the workspace to create. Takes precedence over subscription_id, resource_group_name and workspace_name"),
 		),
-		mcp.WithString("resource_group_name",
-			mcp.Required(),
-			mcp.Description("Resource group name"),
+		mcp.WithString(argSubscriptionID,
+			mcp.Description("Azure subscription ID. Defaults to the active workspace's subscription"),
 		),
-		mcp.WithString("workspace_name",
-			mcp.Required(),
-			mcp.Description("Workspace name"),
+		mcp.WithString(argResourceGroup,
+			mcp.Description("Resource group name. Defaults to the active workspace's resource group"),
+		),
+		mcp.WithString(argWorkspace,
+			mcp.Description("Name of the workspace to create. Required unless resource_id is given; the active workspace is never used"),
 		),
 		mcp.WithString("location",
 			mcp.Required(),
@@ -85,75 +108,115 @@ func (wt *WorkspaceTools) addCreateWorkspaceTool(s *server.MCPServer) {
 		mcp.WithString("friendly_name",
 			mcp.Description("Friendly name for the workspace"),
 		),
+		mcp.WithBoolean("wait",
+			mcp.Description("Wait for the workspace to be created (default true). Set to false to return an operation ID immediately and track it with get_operation_status"),
+		),
+		mcp.WithBoolean(argDryRun,
+			mcp.Description(dryRunDescription),
+		),
+		mcp.WithString(argConfirmToken,
+			mcp.Description(confirmTokenDescription),
+		),
 	)
 
 	s.AddTool(tool, wt.handleCreateWorkspace)
 }
 
 func (wt *WorkspaceTools) handleListWorkspacesBySubscription(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	subscriptionID, err := request.RequireString("subscription_id")
+	subscriptionID, err := wt.scopeArgument(ctx, request, argSubscriptionID)
 	if err != nil {
 		return mcp.NewToolResultError(err.Error()), nil
 	}
 
-	clients, err := azure.NewClientSet(subscriptionID)
+	query, err := newListQuery(request, subscriptionID)
 	if err != nil {
 		return mcp.NewToolResultError(err.Error()), nil
 	}
 
-	pager := clients.WorkspacesClient.NewListBySubscriptionPager(nil)
-	var workspaces []string
+	clients, err := wt.clients.Get(ctx, subscriptionID)
+	if err != nil {
+		return azureError(wt.clients, "Failed to sign in to Azure", err), nil
+	}
 
-	for pager.More() {
-		page, err := pager.NextPage(ctx)
+	fetch := func(ctx context.Context, skip string) ([]Workspace, string, error) {
+		var options *armmachinelearning.WorkspacesClientListBySubscriptionOptions
+		if skip != "" {
+			options = &armmachinelearning.WorkspacesClientListBySubscriptionOptions{Skip: &skip}
+		}
+		page, err := clients.WorkspacesClient.NewListBySubscriptionPager(options).NextPage(ctx)
 		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("Failed to get workspaces: %v", err)), nil
+			return nil, "", err
 		}
-
+		var workspaces []Workspace
 		for _, workspace := range page.Value {
 			if workspace.Name != nil {
-				workspaces = append(workspaces, fmt.Sprintf("Name: %s, Location: %s, Resource Group: %s",
-					*workspace.Name,
-					helpers.GetStringValue(workspace.Location),
-					helpers.ExtractResourceGroupFromID(helpers.GetStringValue(workspace.ID))))
+				workspaces = append(workspaces, newWorkspace(workspace))
 			}
 		}
+		return workspaces, valueOf(page.NextLink), nil
+	}
+	nameContains := request.GetString(argNameContains, "")
+	location := request.GetString(argLocation, "")
+	state := request.GetString(argState, "")
+	match := func(ws Workspace) bool {
+		return matchNameContains(nameContains, ws.Name) && matchLocation(location, ws.Location) && matchFilter(state, ws.ProvisioningState)
+	}
+	compares := map[string]func(a, b Workspace) int{
+		"name":          func(a, b Workspace) int { return compareFold(a.Name, b.Name) },
+		"location":      func(a, b Workspace) int { return compareFold(a.Location, b.Location) },
+		"resourceGroup": func(a, b Workspace) int { return compareFold(a.ResourceGroup, b.ResourceGroup) },
+		"state":         func(a, b Workspace) int { return compareFold(a.ProvisioningState, b.ProvisioningState) },
 	}
 
-	if len(workspaces) == 0 {
-		return mcp.NewToolResultText("No Azure ML workspaces found in the subscription."), nil
+	workspaces, nextCursor, err := listPage(ctx, query, fetch, match, compares)
+	if err != nil {
+		return azureError(wt.clients, "Failed to get workspaces", err), nil
 	}
+	result := WorkspaceList{SubscriptionID: subscriptionID, Count: len(workspaces), Workspaces: workspaces, NextCursor: nextCursor}
 
-	return mcp.NewToolResultText(fmt.Sprintf("Found %d Azure ML workspaces:\n%s", len(workspaces), strings.Join(workspaces, "\n"))), nil
+	if result.Count == 0 {
+		return mcp.NewToolResultStructured(result, "No Azure ML workspaces found in the subscription."), nil
+	}
+
+	lines := make([]string, 0, len(workspaces))
+	for _, ws := range workspaces {
+		lines = append(lines, fmt.Sprintf("Name: %s, Location: %s, Resource Group: %s",
+			ws.Name, orNA(ws.Location), orNA(ws.ResourceGroup)))
+	}
+	return mcp.NewToolResultStructured(result, fmt.Sprintf("Found %d Azure ML workspaces:\n%s%s",
+		result.Count, strings.Join(lines, "\n"), moreResultsText(nextCursor))), nil
 }
 
 func (wt *WorkspaceTools) handleGetWorkspace(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	subscriptionID, err := request.RequireString("subscription_id")
+	subscriptionID, err := wt.scopeArgument(ctx, request, argSubscriptionID)
 	if err != nil {
 		return mcp.NewToolResultError(err.Error()), nil
 	}
 
-	resourceGroupName, err := request.RequireString("resource_group_name")
+	resourceGroupName, err := wt.scopeArgument(ctx, request, argResourceGroup)
 	if err != nil {
 		return mcp.NewToolResultError(err.Error()), nil
 	}
 
-	workspaceName, err := request.RequireString("workspace_name")
+	workspaceName, err := wt.scopeArgument(ctx, request, argWorkspace)
 	if err != nil {
 		return mcp.NewToolResultError(err.Error()), nil
 	}
 
-	clients, err := azure.NewClientSet(subscriptionID)
+	clients, err := wt.clients.Get(ctx, subscriptionID)
 	if err != nil {
-		return mcp.NewToolResultError(err.Error()), nil
+		return azureError(wt.clients, "Failed to sign in to Azure", err), nil
 	}
 
 	resp, err := clients.WorkspacesClient.Get(ctx, resourceGroupName, workspaceName, nil)
 	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("Failed to get workspace: %v", err)), nil
+		return azureError(wt.clients, "Failed to get workspace", err), nil
 	}
 
-	workspace := resp.Workspace
+	workspace := newWorkspace(&resp.Workspace)
+	if workspace.ResourceGroup == "" {
+		workspace.ResourceGroup = resourceGroupName
+	}
 	details := fmt.Sprintf(`Workspace Details:
 Name: %s
 Location: %s
@@ -165,32 +228,32 @@ Description: %s
 Friendly Name: %s
 Discovery URL: %s
 ML Flow Tracking URI: %s`,
-		helpers.GetStringValue(workspace.Name),
-		helpers.GetStringValue(workspace.Location),
-		resourceGroupName,
-		helpers.GetStringValue(workspace.ID),
-		helpers.GetStringValue(workspace.Type),
-		helpers.GetSKUString(workspace.SKU),
-		helpers.GetWorkspacePropertyString(workspace.Properties, "Description"),
-		helpers.GetWorkspacePropertyString(workspace.Properties, "FriendlyName"),
-		helpers.GetWorkspacePropertyString(workspace.Properties, "DiscoveryUrl"),
-		helpers.GetWorkspacePropertyString(workspace.Properties, "MlFlowTrackingUri"))
-
-	return mcp.NewToolResultText(details), nil
+		orNA(workspace.Name),
+		orNA(workspace.Location),
+		workspace.ResourceGroup,
+		orNA(workspace.ID),
+		orNA(workspace.Type),
+		orNA(workspace.SKU),
+		orNA(workspace.Description),
+		orNA(workspace.FriendlyName),
+		orNA(workspace.DiscoveryURL),
+		orNA(workspace.MLFlowTrackingURI))
+
+	return mcp.NewToolResultStructured(workspace, details), nil
 }
 
 func (wt *WorkspaceTools) handleCreateWorkspace(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	subscriptionID, err := request.RequireString("subscription_id")
+	subscriptionID, err := wt.scopeArgument(ctx, request, argSubscriptionID)
 	if err != nil {
 		return mcp.NewToolResultError(err.Error()), nil
 	}
 
-	resourceGroupName, err := request.RequireString("resource_group_name")
+	resourceGroupName, err := wt.scopeArgument(ctx, request, argResourceGroup)
 	if err != nil {
 		return mcp.NewToolResultError(err.Error()), nil
 	}
 
-	workspaceName, err := request.RequireString("workspace_name")
+	workspaceName, err := explicitArgument(request, argWorkspace)
 	if err != nil {
 		return mcp.NewToolResultError(err.Error()), nil
 	}
@@ -200,32 +263,75 @@ func (wt *WorkspaceTools) handleCreateWorkspace(ctx context.Context, request mcp
 		return mcp.NewToolResultError(err.Error()), nil
 	}
 
-	description := request.GetString("description", "")
-	friendlyName := request.GetString("friendly_name", "")
-
-	clients, err := azure.NewClientSet(subscriptionID)
-	if err != nil {
-		return mcp.NewToolResultError(err.Error()), nil
-	}
-
+	// Properties the caller leaves out are not sent, so they are not blanked
 	workspace := armmachinelearning.Workspace{
 		Location: to.Ptr(location),
 		Properties: &armmachinelearning.WorkspaceProperties{
-			Description:  to.Ptr(description),
-			FriendlyName: to.Ptr(friendlyName),
+			Description:  optionalString(request, "description"),
+			FriendlyName: optionalString(request, "friendly_name"),
 		},
 	}
 
+	clients, err := wt.clients.Get(ctx, subscriptionID)
+	if err != nil {
+		return azureError(wt.clients, "Failed to sign in to Azure", err), nil
+	}
+	// The workspace may not exist yet, so the resource group is checked
+	if denied := preflight(ctx, request, wt.clients, clients, permissionScope{ResourceGroup: resourceGroupName}); denied != nil {
+		return denied, nil
+	}
+
+	plan, failed := wt.planMutation(ctx, request, wt.clients, subscriptionID,
+		fmt.Sprintf("Create workspace '%s' in resource group '%s' at location '%s'", workspaceName, resourceGroupName, location),
+		func(clients *azure.ClientSet) error {
+			_, err := clients.WorkspacesClient.BeginCreateOrUpdate(ctx, resourceGroupName, workspaceName, workspace, nil)
+			return err
+		})
+	if failed != nil {
+		return failed, nil
+	}
+	if plan != nil {
+		return mcp.NewToolResultStructured(WorkspaceCreation{Status: plan.status(), Plan: plan}, plan.text()), nil
+	}
+
 	poller, err := clients.WorkspacesClient.BeginCreateOrUpdate(ctx, resourceGroupName, workspaceName, workspace, nil)
 	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("Failed to start workspace creation: %v", err)), nil
+		return azureError(wt.clients, "Failed to start workspace creation", err), nil
+	}
+
+	if !request.GetBool("wait", true) {
+		op, err := wt.trackOperation(ctx, operations.Operation{
+			Kind:           operations.KindCreateWorkspace,
+			SubscriptionID: subscriptionID,
+			ResourceGroup:  resourceGroupName,
+			Target:         workspaceName,
+		}, sdkPoller[armmachinelearning.WorkspacesClientCreateOrUpdateResponse]{poller})
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		return mcp.NewToolResultStructured(WorkspaceCreation{Status: string(op.Status), OperationID: op.ID},
+			fmt.Sprintf("Creation of workspace '%s' in resource group '%s' is %s. Operation ID: %s",
+				workspaceName, resourceGroupName, op.Status, op.ID)), nil
 	}
 
-	result, err := poller.PollUntilDone(ctx, nil)
+	err = wt.pollUntilDone(ctx, request, fmt.Sprintf("Creating workspace '%s'", workspaceName), sdkPoller[armmachinelearning.WorkspacesClientCreateOrUpdateResponse]{poller})
+	if err != nil {
+		return azureError(wt.clients, "Failed to create workspace", err), nil
+	}
+	result, err := poller.Result(ctx)
 	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("Failed to create workspace: %v", err)), nil
+		return azureError(wt.clients, "Failed to create workspace", err), nil
 	}
 
-	return mcp.NewToolResultText(fmt.Sprintf("Successfully created workspace '%s' in resource group '%s' at location '%s'. Workspace ID: %s",
-		workspaceName, resourceGroupName, location, helpers.GetStringValue(result.ID))), nil
+	created := newWorkspace(&result.Workspace)
+	return mcp.NewToolResultStructured(WorkspaceCreation{Status: string(operations.StatusSucceeded), Workspace: &created}, fmt.Sprintf("Successfully created workspace '%s' in resource group '%s' at location '%s'. Workspace ID: %s",
+		workspaceName, resourceGroupName, location, orNA(created.ID))), nil
+}
+
+// optionalString returns a string argument the call gives, or nil
+func optionalString(request mcp.CallToolRequest, name string) *string {
+	if value, ok := request.GetArguments()[name].(string); ok {
+		return &value
+	}
+	return nil
 }
@@ -1,16 +1,24 @@
 package tools_test
 
 import (
+	"bytes"
+	"context"
 	"errors"
+	"net/http"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/mark3labs/mcp-go/server"
+	"microsoft.com/aml-mcp/internal/azure"
+	"microsoft.com/aml-mcp/internal/helpers"
+	"microsoft.com/aml-mcp/internal/session"
 	"microsoft.com/aml-mcp/internal/tools"
 )
 
 func TestWorkspaceTools_AddToServer(t *testing.T) {
 	s := server.NewMCPServer("test", "1.0.0")
-	workspaceTools := tools.NewWorkspaceTools()
+	workspaceTools := tools.NewWorkspaceTools(azure.NewClientCache())
 
 	// Test that AddToServer doesn't panic
 	workspaceTools.AddToServer(s)
@@ -20,7 +28,7 @@ func TestWorkspaceTools_AddToServer(t *testing.T) {
 }
 
 func TestWorkspaceTools_New(t *testing.T) {
-	workspaceTools := tools.NewWorkspaceTools()
+	workspaceTools := tools.NewWorkspaceTools(azure.NewClientCache())
 	if workspaceTools == nil {
 		t.Error("NewWorkspaceTools() returned nil")
 	}
@@ -105,13 +113,13 @@ func (m *mockCallToolRequest) GetString(key, defaultValue string) string {
 
 // Test workspace tools structure
 func TestWorkspaceToolsStructure(t *testing.T) {
-	wt := tools.NewWorkspaceTools()
+	wt := tools.NewWorkspaceTools(azure.NewClientCache())
 	if wt == nil {
 		t.Fatal("NewWorkspaceTools() returned nil")
 	}
 
 	// Test that we can create multiple instances
-	wt2 := tools.NewWorkspaceTools()
+	wt2 := tools.NewWorkspaceTools(azure.NewClientCache())
 	if wt2 == nil {
 		t.Fatal("Second NewWorkspaceTools() returned nil")
 	}
@@ -121,3 +129,122 @@ func TestWorkspaceToolsStructure(t *testing.T) {
 		t.Error("NewWorkspaceTools() returned the same instance")
 	}
 }
+
+func TestWorkspaceToolHandlers(t *testing.T) {
+	fake := newFakeARM(t)
+	s := server.NewMCPServer("test", "1.0.0")
+	tools.NewWorkspaceTools(fake.ClientCache()).AddToServer(s)
+
+	tests := []struct {
+		name      string
+		toolName  string
+		args      map[string]any
+		wantError bool
+		contains  []string
+	}{
+		{
+			name:     "list workspaces by subscription",
+			toolName: "list_workspaces_by_subscription",
+			args:     map[string]any{"subscription_id": testSubscriptionID},
+			contains: []string{"Found 1 Azure ML workspaces", "Name: test-ws, Location: eastus, Resource Group: test-rg"},
+		},
+		{
+			name:     "list workspaces in empty subscription",
+			toolName: "list_workspaces_by_subscription",
+			args:     map[string]any{"subscription_id": "00000000-0000-0000-0000-000000000099"},
+			contains: []string{"No Azure ML workspaces found"},
+		},
+		{
+			name:     "get workspace",
+			toolName: "get_workspace",
+			args:     workspaceArgs(nil),
+			contains: []string{"Name: test-ws", "SKU: Basic", "Friendly Name: Test Workspace"},
+		},
+		{
+			name:      "get missing workspace",
+			toolName:  "get_workspace",
+			args:      map[string]any{"subscription_id": testSubscriptionID, "resource_group_name": testResourceGroup, "workspace_name": "missing"},
+			wantError: true,
+			contains:  []string{"Failed to get workspace", "ResourceNotFound"},
+		},
+		{
+			name:      "get workspace missing workspace_name",
+			toolName:  "get_workspace",
+			args:      map[string]any{"subscription_id": testSubscriptionID, "resource_group_name": testResourceGroup},
+			wantError: true,
+			contains:  []string{"workspace_name"},
+		},
+		{
+			name:     "create workspace",
+			toolName: "create_workspace",
+			args: map[string]any{
+				"subscription_id":     testSubscriptionID,
+				"resource_group_name": testResourceGroup,
+				"workspace_name":      "new-ws",
+				"location":            "westus2",
+				"description":         "Created by test",
+			},
+			contains: []string{"Successfully created workspace 'new-ws'", "workspaces/new-ws"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := callTool(t, s, tt.toolName, tt.args)
+			text := resultText(result)
+			if result.IsError != tt.wantError {
+				t.Fatalf("IsError = %v, want %v (text: %s)", result.IsError, tt.wantError, text)
+			}
+			for _, want := range tt.contains {
+				if !strings.Contains(text, want) {
+					t.Errorf("result %q does not contain %q", text, want)
+				}
+			}
+		})
+	}
+
+	ws, ok := fake.Workspace(testSubscriptionID, testResourceGroup, "new-ws")
+	if !ok {
+		t.Fatal("create_workspace did not create the workspace")
+	}
+	if got := helpers.GetStringValue(ws.Properties.Description); got != "Created by test" {
+		t.Errorf("created workspace description = %q, want %q", got, "Created by test")
+	}
+}
+
+func TestCreateWorkspaceNeedsName(t *testing.T) {
+	fake := newFakeARM(t)
+	clients := fake.ClientCache()
+	opts := []tools.Option{tools.WithSessions(session.NewStore()), tools.WithPollInterval(10 * time.Millisecond)}
+	s := server.NewMCPServer("test", "1.0.0")
+	tools.NewContextTools(clients, opts...).AddToServer(s)
+	tools.NewWorkspaceTools(clients, opts...).AddToServer(s)
+	ctx := s.WithContext(context.Background(), newTestSession("session-1"))
+
+	if result := callToolInContext(ctx, t, s, "set_active_workspace", workspaceArgs(nil)); result.IsError {
+		t.Fatalf("set_active_workspace failed: %s", resultText(result))
+	}
+
+	// The active workspace names an existing workspace, which a create
+	// without workspace_name would overwrite
+	result := callToolInContext(ctx, t, s, "create_workspace", map[string]any{"location": testLocation})
+	if !result.IsError || !strings.Contains(resultText(result), "workspace_name is required") {
+		t.Fatalf("create_workspace without workspace_name = %s, want it rejected", resultText(result))
+	}
+	for _, request := range fake.Requests() {
+		if request.Method == http.MethodPut {
+			t.Errorf("create_workspace sent %s %s", request.Method, request.Path)
+		}
+	}
+
+	// Properties the caller leaves out are not sent
+	result = callToolInContext(ctx, t, s, "create_workspace", map[string]any{"workspace_name": "new-ws", "location": testLocation})
+	if result.IsError {
+		t.Fatalf("create_workspace failed: %s", resultText(result))
+	}
+	for _, request := range fake.Requests() {
+		if request.Method == http.MethodPut && (bytes.Contains(request.Body, []byte("description")) || bytes.Contains(request.Body, []byte("friendlyName"))) {
+			t.Errorf("create_workspace sent %s, want no description or friendly name", request.Body)
+		}
+	}
+}